@@ -0,0 +1,80 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/runfinch/finch/pkg/compat"
+	"github.com/runfinch/finch/pkg/templates"
+)
+
+const defaultCompatCheckFormat = "table {{.Status}}\t{{.Command}}\t{{.Flag}}\t{{.Value}}\t{{.Note}}"
+
+func newCompatCommand(stdOut io.Writer) *cobra.Command {
+	compatCommand := &cobra.Command{
+		Use:   "compat",
+		Short: "Docker compatibility",
+	}
+	compatCommand.AddCommand(
+		newCompatCheckCommand(stdOut),
+	)
+	return compatCommand
+}
+
+func newCompatCheckCommand(stdOut io.Writer) *cobra.Command {
+	compatCheckCommand := &cobra.Command{
+		Use:   "check -- DOCKER_COMMAND [ARG...]",
+		Args:  cobra.MinimumNArgs(1),
+		Short: "Report the docker commands and flags that finch does not fully support",
+		Long: "Checks a docker command line, e.g. finch compat check -- docker run --gpus all alpine, " +
+			"against the compatibility catalog of finch and reports the commands and flags that are unsupported " +
+			"or only partially supported.",
+		RunE: newCompatCheckAction(compat.DefaultCatalog(), stdOut).runAdapter,
+	}
+	addFormatFlag(compatCheckCommand, "")
+	return compatCheckCommand
+}
+
+type compatCheckAction struct {
+	catalog *compat.Catalog
+	stdOut  io.Writer
+}
+
+func newCompatCheckAction(catalog *compat.Catalog, stdOut io.Writer) *compatCheckAction {
+	return &compatCheckAction{catalog: catalog, stdOut: stdOut}
+}
+
+func (cca *compatCheckAction) runAdapter(cmd *cobra.Command, args []string) error {
+	format, err := getFormatFlag(cmd)
+	if err != nil {
+		return err
+	}
+	return cca.run(format, args)
+}
+
+func (cca *compatCheckAction) run(format string, args []string) error {
+	tmpl, err := templates.Parse("compat", format, defaultCompatCheckFormat)
+	if err != nil {
+		return err
+	}
+
+	findings := cca.catalog.Check(args)
+	if len(findings) == 0 {
+		if tmpl.IsTable() {
+			_, err := fmt.Fprintln(cca.stdOut, "No known compatibility issue found")
+			return err
+		}
+		return nil
+	}
+
+	items := make([]any, len(findings))
+	for i, f := range findings {
+		items[i] = f
+	}
+	return tmpl.Execute(cca.stdOut, items...)
+}
@@ -0,0 +1,86 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/runfinch/finch/pkg/compat"
+)
+
+func TestNewCompatCommand(t *testing.T) {
+	t.Parallel()
+
+	cmd := newCompatCommand(nil)
+	assert.Equal(t, cmd.Name(), "compat")
+	assert.Equal(t, cmd.Commands()[0].Name(), "check")
+}
+
+func TestCompatCheckAction_run(t *testing.T) {
+	t.Parallel()
+
+	catalog, err := compat.ParseCatalog([]byte(`
+- commands: [run]
+  flags: [--link]
+  status: unsupported
+  note: Links are not supported.
+- commands: [run]
+  flags: [--gpus]
+  status: partial
+  note: No GPUs.
+`))
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name    string
+		format  string
+		args    []string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "table",
+			args: []string{"docker", "run", "--gpus", "all", "--link", "db", "alpine"},
+			want: "STATUS        COMMAND   FLAG      VALUE     NOTE\n" +
+				"partial       run       --gpus    all       No GPUs.\n" +
+				"unsupported   run       --link    db        Links are not supported.\n",
+		},
+		{
+			name: "no finding",
+			args: []string{"docker", "run", "alpine"},
+			want: "No known compatibility issue found\n",
+		},
+		{
+			name:   "custom format",
+			format: "{{.Flag}}: {{.Status}}",
+			args:   []string{"docker", "run", "--link", "db", "alpine"},
+			want:   "--link: unsupported\n",
+		},
+		{
+			name:    "invalid format",
+			format:  "{{.Flag",
+			args:    []string{"docker", "run", "alpine"},
+			wantErr: "template: compat:1: unclosed action",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var stdOut bytes.Buffer
+			err := newCompatCheckAction(catalog, &stdOut).run(tc.format, tc.args)
+			if tc.wantErr != "" {
+				assert.EqualError(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, stdOut.String())
+		})
+	}
+}
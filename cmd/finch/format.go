@@ -0,0 +1,21 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+const formatFlag = "format"
+
+// addFormatFlag adds the docker-style --format flag to a finch command, with an optional shorthand.
+func addFormatFlag(cmd *cobra.Command, shorthand string) {
+	cmd.Flags().StringP(formatFlag, shorthand, "",
+		"Format the output using the given Go template, e.g, '{{json .}}', or 'table' to print a table")
+}
+
+// getFormatFlag returns the value of the --format flag of cmd, to be parsed with templates.Parse.
+func getFormatFlag(cmd *cobra.Command) (string, error) {
+	return cmd.Flags().GetString(formatFlag)
+}
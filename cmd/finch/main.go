@@ -5,7 +5,9 @@
 package main
 
 import (
+	"errors"
 	"os"
+	"strings"
 
 	"github.com/containerd/nerdctl/v2/pkg/errutil"
 	"github.com/spf13/afero"
@@ -15,10 +17,16 @@ import (
 	"github.com/runfinch/finch/pkg/config"
 	"github.com/runfinch/finch/pkg/flog"
 	"github.com/runfinch/finch/pkg/fmemory"
+	"github.com/runfinch/finch/pkg/path"
 	"github.com/runfinch/finch/pkg/system"
+	"github.com/runfinch/finch/pkg/timing"
 )
 
-const finchRootCmd = "finch"
+const (
+	finchRootCmd = "finch"
+	timingsFlag  = "timings"
+	timingsEnv   = "FINCH_TIMINGS"
+)
 
 func main() {
 	logger := flog.NewLogrus()
@@ -26,20 +34,98 @@ func main() {
 	fs := afero.NewOsFs()
 	mem := fmemory.NewMemory()
 	stdOut := os.Stdout
-	if err := xmain(logger, stdLib, fs, stdLib, mem, stdOut); err != nil {
+	args, err := enableTimings(logger, os.Args[1:], os.Getenv(timingsEnv))
+	if err != nil {
+		logger.Fatal(err)
+	}
+	os.Args = append(os.Args[:1], args...)
+
+	if err := useInstanceStore(stdLib); err != nil {
+		logger.Debugf("Failed to read the state of the VM from the Lima home directory, falling back to limactl: %v", err)
+	}
+	err = xmain(logger, stdLib, fs, stdLib, mem, stdOut)
+	if reportErr := timing.Report(os.Stderr); reportErr != nil {
+		logger.Debugf("Failed to report the timings: %v", reportErr)
+	}
+	if err != nil {
+		// A wrapped command exiting with a non-zero code has already reported its error,
+		// so finch exits with the same code instead of reporting a fatal error.
+		var exitErr *command.ExitCodeError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.ExitCode())
+		}
 		errutil.HandleExitCoder(err)
 		logger.Fatal(err)
 	}
 }
 
+// enableTimings enables the recording of timings when FINCH_TIMINGS is set, to "table", "json" or "1",
+// or when --timings[=format] precedes the command, and returns args without the flag.
+// The flag is removed as the arguments of the nerdctl commands are passed through to nerdctl.
+// An invalid FINCH_TIMINGS is only warned about, as it is set for every command, unlike an invalid flag.
+func enableTimings(logger flog.Logger, args []string, env string) ([]string, error) {
+	flagFormat := ""
+	rest := make([]string, 0, len(args))
+	i := 0
+	for ; i < len(args) && strings.HasPrefix(args[i], "-"); i++ {
+		if args[i] == "--"+timingsFlag {
+			flagFormat = timing.TableFormat
+			continue
+		}
+		if v, ok := strings.CutPrefix(args[i], "--"+timingsFlag+"="); ok {
+			flagFormat = v
+			continue
+		}
+		rest = append(rest, args[i])
+	}
+	rest = append(rest, args[i:]...)
+
+	if flagFormat != "" {
+		return rest, timing.Enable(flagFormat)
+	}
+	envFormat := ""
+	switch strings.ToLower(env) {
+	case "", "0", "false":
+	case "1", "true":
+		envFormat = timing.TableFormat
+	default:
+		envFormat = env
+	}
+	if envFormat == "" {
+		return rest, nil
+	}
+	if err := timing.Enable(envFormat); err != nil {
+		logger.Warnf("Ignoring %s, the timings are not recorded: %v", timingsEnv, err)
+	}
+	return rest, nil
+}
+
+// addTimingsFlag adds the global --timings flag to rootCmd.
+// It is usually consumed by enableTimings, but it is also honored after a finch command, e.g. finch vm start --timings.
+func addTimingsFlag(rootCmd *cobra.Command) {
+	rootCmd.PersistentFlags().String(timingsFlag, "",
+		`print how long the steps of the command take on stderr at exit, as a "table" or as "json"`)
+	rootCmd.PersistentFlags().Lookup(timingsFlag).NoOptDefVal = timing.TableFormat
+}
+
+// enableTimingsFlag enables the recording of timings if --timings is passed to cmd.
+func enableTimingsFlag(cmd *cobra.Command) error {
+	format, _ := cmd.Flags().GetString(timingsFlag)
+	if format == "" {
+		return nil
+	}
+	return timing.Enable(format)
+}
+
 func initializeNerdctlCommands(
 	ncc command.NerdctlCmdCreator,
 	ecc command.Creator,
 	logger flog.Logger,
 	fs afero.Fs,
 	fc *config.Finch,
+	fp path.Finch,
 ) []*cobra.Command {
-	nerdctlCommandCreator := newNerdctlCommandCreator(ncc, ecc, system.NewStdLib(), logger, fs, fc)
+	nerdctlCommandCreator := newNerdctlCommandCreator(ncc, ecc, system.NewStdLib(), logger, fs, fc, fp)
 	var allNerdctlCommands []*cobra.Command
 	for cmdName, cmdDescription := range nerdctlCmds {
 		allNerdctlCommands = append(allNerdctlCommands, nerdctlCommandCreator.create(cmdName, cmdDescription))
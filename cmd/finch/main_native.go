@@ -22,9 +22,15 @@ import (
 	"github.com/runfinch/finch/pkg/path"
 	"github.com/runfinch/finch/pkg/support"
 	"github.com/runfinch/finch/pkg/system"
+	"github.com/runfinch/finch/pkg/timing"
 	"github.com/runfinch/finch/pkg/version"
 )
 
+// useInstanceStore does nothing, as there is no VM on Linux.
+func useInstanceStore(path.FinchFinderDeps) error {
+	return nil
+}
+
 func xmain(logger flog.Logger,
 	_ path.FinchFinderDeps,
 	fs afero.Fs,
@@ -34,6 +40,7 @@ func xmain(logger flog.Logger,
 ) error {
 	fp := path.NewFinchPath()
 	ecc := command.NewExecCmdCreator()
+	endConfigLoad := timing.Start("config load")
 	fc, err := config.Load(
 		fs,
 		fp.ConfigFilePath(),
@@ -42,6 +49,7 @@ func xmain(logger flog.Logger,
 		mem,
 		ecc,
 	)
+	endConfigLoad()
 	if err != nil {
 		if errors.Is(err, os.ErrPermission) {
 			logger.Warnf("Failed to load config, using default values. You may need to be root or use sudo. (%s)", err)
@@ -79,13 +87,14 @@ var newApp = func(
 	// TODO: Decide when to forward --debug to the dependencies
 	// (e.g. nerdctl for container commands and limactl for VM commands).
 	rootCmd.PersistentFlags().Bool("debug", false, "running under debug mode")
+	addTimingsFlag(rootCmd)
 	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, _ []string) error {
 		// running commands under debug mode will print out debug logs
 		debugMode, _ := cmd.Flags().GetBool("debug")
 		if debugMode {
 			logger.SetLevel(flog.Debug)
 		}
-		return nil
+		return enableTimingsFlag(cmd)
 	}
 
 	ncc := command.NewNerdctlCmdCreator(ecc,
@@ -105,14 +114,16 @@ var newApp = func(
 		ncc,
 		lima,
 		system.NewStdLib(),
+		stdOut,
 	)
 
 	// append nerdctl commands
-	allCommands := initializeNerdctlCommands(ncc, ecc, logger, fs, fc)
+	allCommands := initializeNerdctlCommands(ncc, ecc, logger, fs, fc, fp)
 	// append finch specific commands
 	allCommands = append(allCommands,
 		newVersionCommand(ncc, logger, stdOut),
-		newSupportBundleCommand(logger, supportBundleBuilder, ncc),
+		newSupportBundleCommand(logger, supportBundleBuilder, support.NewBundleUploader(fs, logger, os.Stderr), ncc, fs, fc, stdOut),
+		newCompatCommand(stdOut),
 		newGenDocsCommand(rootCmd, logger, fs, system.NewStdLib()),
 	)
 
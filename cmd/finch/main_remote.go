@@ -8,6 +8,7 @@ package main
 import (
 	"fmt"
 	"io"
+	"os"
 
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
@@ -16,13 +17,30 @@ import (
 	"github.com/runfinch/finch/pkg/config"
 	"github.com/runfinch/finch/pkg/flog"
 	"github.com/runfinch/finch/pkg/fmemory"
+	"github.com/runfinch/finch/pkg/lima"
 	"github.com/runfinch/finch/pkg/lima/wrapper"
 	"github.com/runfinch/finch/pkg/path"
 	"github.com/runfinch/finch/pkg/support"
 	"github.com/runfinch/finch/pkg/system"
+	"github.com/runfinch/finch/pkg/timing"
 	"github.com/runfinch/finch/pkg/version"
 )
 
+// useInstanceStore makes the state of the VM, which is checked before most commands, read from the Lima home
+// directory, which is much faster than running limactl ls. The store package of Lima locates the Lima home
+// with LIMA_HOME only, so it is set for the whole process, to the Lima home passed to the limactl commands.
+func useInstanceStore(ffd path.FinchFinderDeps) error {
+	fp, err := path.FindFinch(ffd)
+	if err != nil {
+		return err
+	}
+	if err := os.Setenv(command.EnvKeyLimaHome, fp.LimaHomePath()); err != nil {
+		return err
+	}
+	lima.UseInstanceStore(lima.NewInstanceStore())
+	return nil
+}
+
 func xmain(logger flog.Logger,
 	ffd path.FinchFinderDeps,
 	fs afero.Fs,
@@ -44,6 +62,7 @@ func xmain(logger flog.Logger,
 		return fmt.Errorf("failed to get finch root path: %w", err)
 	}
 	ecc := command.NewExecCmdCreator()
+	endConfigLoad := timing.Start("config load")
 	fc, err := config.Load(
 		fs,
 		fp.ConfigFilePath(finchRootPath),
@@ -52,6 +71,7 @@ func xmain(logger flog.Logger,
 		mem,
 		ecc,
 	)
+	endConfigLoad()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
@@ -89,13 +109,14 @@ var newApp = func(
 	// TODO: Decide when to forward --debug to the dependencies
 	// (e.g. nerdctl for container commands and limactl for VM commands).
 	rootCmd.PersistentFlags().Bool("debug", false, "running under debug mode")
+	addTimingsFlag(rootCmd)
 	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, _ []string) error {
 		// running commands under debug mode will print out debug logs
 		debugMode, _ := cmd.Flags().GetBool("debug")
 		if debugMode {
 			logger.SetLevel(flog.Debug)
 		}
-		return nil
+		return enableTimingsFlag(cmd)
 	}
 
 	ncc := command.NewNerdctlCmdCreator(ecc,
@@ -115,15 +136,17 @@ var newApp = func(
 		ncc,
 		lima,
 		system.NewStdLib(),
+		stdOut,
 	)
 
 	// append nerdctl commands
-	allCommands := initializeNerdctlCommands(ncc, ecc, logger, fs, fc)
+	allCommands := initializeNerdctlCommands(ncc, ecc, logger, fs, fc, fp)
 	// append finch specific commands
 	allCommands = append(allCommands,
 		newVersionCommand(ncc, logger, stdOut),
 		virtualMachineCommands(logger, fp, ncc, ecc, fs, fc, home, finchRootPath),
-		newSupportBundleCommand(logger, supportBundleBuilder, ncc),
+		newSupportBundleCommand(logger, supportBundleBuilder, support.NewBundleUploader(fs, logger, os.Stderr), ncc, fs, fc, stdOut),
+		newCompatCommand(stdOut),
 		newGenDocsCommand(rootCmd, logger, fs, system.NewStdLib()),
 		newLoginLocalCommand(),
 		newLogoutLocalCommand(),
@@ -0,0 +1,83 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/runfinch/finch/pkg/mocks"
+)
+
+func TestEnableTimings(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		args    []string
+		env     string
+		mockSvc func(*mocks.Logger)
+		want    []string
+		wantErr string
+	}{
+		{
+			name: "disabled",
+			args: []string{"ps", "-a"},
+			env:  "0",
+			want: []string{"ps", "-a"},
+		},
+		{
+			name: "flag preceding the command is removed",
+			args: []string{"--debug", "--timings=json", "ps", "-a"},
+			want: []string{"--debug", "ps", "-a"},
+		},
+		{
+			name: "flag following the command is passed through",
+			args: []string{"run", "alpine", "--timings"},
+			want: []string{"run", "alpine", "--timings"},
+		},
+		{
+			name: "unknown format of the environment variable",
+			args: []string{"ps"},
+			env:  "yes",
+			mockSvc: func(logger *mocks.Logger) {
+				logger.EXPECT().Warnf("Ignoring %s, the timings are not recorded: %v", "FINCH_TIMINGS", gomock.Any())
+			},
+			want: []string{"ps"},
+		},
+		{
+			name:    "unknown format of the flag",
+			args:    []string{"--timings=yaml", "ps"},
+			want:    []string{"ps"},
+			wantErr: `unknown timings format "yaml", expected "table" or "json"`,
+		},
+		{
+			name:    "flag overrides the environment variable",
+			args:    []string{"--timings=yaml", "ps"},
+			env:     "on",
+			want:    []string{"ps"},
+			wantErr: `unknown timings format "yaml", expected "table" or "json"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			logger := mocks.NewLogger(gomock.NewController(t))
+			if tc.mockSvc != nil {
+				tc.mockSvc(logger)
+			}
+			got, err := enableTimings(logger, tc.args, tc.env)
+			if tc.wantErr != "" {
+				assert.EqualError(t, err, tc.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
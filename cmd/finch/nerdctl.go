@@ -4,20 +4,21 @@
 package main
 
 import (
-	"fmt"
 	"runtime"
-	"strings"
 
 	"golang.org/x/exp/slices"
 
-	"github.com/sirupsen/logrus"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 
 	"github.com/runfinch/finch/pkg/command"
 	"github.com/runfinch/finch/pkg/config"
 	"github.com/runfinch/finch/pkg/flog"
+	"github.com/runfinch/finch/pkg/path"
+	"github.com/runfinch/finch/pkg/pathmap"
 	"github.com/runfinch/finch/pkg/system"
+	"github.com/runfinch/finch/pkg/timing"
+	"github.com/runfinch/finch/pkg/translate"
 
 	"k8s.io/apimachinery/pkg/util/sets"
 )
@@ -32,6 +33,9 @@ type NerdctlCommandSystemDeps interface {
 	system.AbsFilePath
 	system.FilePathToSlash
 	system.EnvGetter
+	system.EnvSetter
+	system.UserHomeDir
+	system.SymlinksEvaluator
 }
 
 type nerdctlCommandCreator struct {
@@ -41,13 +45,9 @@ type nerdctlCommandCreator struct {
 	logger     flog.Logger
 	fs         afero.Fs
 	fc         *config.Finch
+	fp         path.Finch
 }
 
-type (
-	argHandler     func(systemDeps NerdctlCommandSystemDeps, fc *config.Finch, args []string, index int) error
-	commandHandler func(systemDeps NerdctlCommandSystemDeps, fc *config.Finch, cmdName *string, args *[]string, inspectType *string) error
-)
-
 func newNerdctlCommandCreator(
 	ncc command.NerdctlCmdCreator,
 	ecc command.Creator,
@@ -55,8 +55,9 @@ func newNerdctlCommandCreator(
 	logger flog.Logger,
 	fs afero.Fs,
 	fc *config.Finch,
+	fp path.Finch,
 ) *nerdctlCommandCreator {
-	return &nerdctlCommandCreator{ncc: ncc, ecc: ecc, systemDeps: systemDeps, logger: logger, fs: fs, fc: fc}
+	return &nerdctlCommandCreator{ncc: ncc, ecc: ecc, systemDeps: systemDeps, logger: logger, fs: fs, fc: fc, fp: fp}
 }
 
 func (ncc *nerdctlCommandCreator) create(cmdName string, cmdDesc string) *cobra.Command {
@@ -67,7 +68,7 @@ func (ncc *nerdctlCommandCreator) create(cmdName string, cmdDesc string) *cobra.
 		// the args passed to nerdctlCommand.run will be empty because
 		// cobra will try to parse `-d alpine` as if alpine is the value of the `-d` flag.
 		DisableFlagParsing: true,
-		RunE:               newNerdctlCommand(ncc.ncc, ncc.ecc, ncc.systemDeps, ncc.logger, ncc.fs, ncc.fc).runAdapter,
+		RunE:               newNerdctlCommand(ncc.ncc, ncc.ecc, ncc.systemDeps, ncc.logger, ncc.fs, ncc.fc, ncc.fp).runAdapter,
 	}
 
 	return command
@@ -80,6 +81,9 @@ type nerdctlCommand struct {
 	logger     flog.Logger
 	fs         afero.Fs
 	fc         *config.Finch
+	fp         path.Finch
+	// sharedPaths caches the host directories shared into the VM, see translateHooks.
+	sharedPaths *pathmap.Map
 }
 
 func newNerdctlCommand(
@@ -89,8 +93,9 @@ func newNerdctlCommand(
 	logger flog.Logger,
 	fs afero.Fs,
 	fc *config.Finch,
+	fp path.Finch,
 ) *nerdctlCommand {
-	return &nerdctlCommand{ncc: ncc, ecc: ecc, systemDeps: systemDeps, logger: logger, fs: fs, fc: fc}
+	return &nerdctlCommand{ncc: ncc, ecc: ecc, systemDeps: systemDeps, logger: logger, fs: fs, fc: fc, fp: fp}
 }
 
 func (nc *nerdctlCommand) runAdapter(cmd *cobra.Command, args []string) error {
@@ -173,207 +178,27 @@ var dockerCompatCmds = map[string]string{
 	"buildx": "build version",
 }
 
-var aliasMap = map[string]string{
-	"build": "image build",
-	"run":   "container run",
-	"cp":    "container cp",
-}
-
-var commandHandlerMap = map[string]commandHandler{
-	"buildx":  handleBuildx,
-	"inspect": handleDockerCompatInspect,
+// translator returns the translator that converts the command line typed by the user into a nerdctl command line.
+func (nc *nerdctlCommand) translator() *translate.Translator {
+	return translate.NewTranslator(nc.systemDeps, nc.logger, nc.fs, nc.fc, nc.translateHooks())
 }
 
-var argHandlerMap = map[string]map[string]argHandler{
-	"image build": {
-		"--load": handleDockerBuildLoad,
-	},
-	"container run": {
-		"--mount": handleBindMounts,
-	},
-}
-
-var cmdFlagSetMap = map[string]map[string]sets.Set[string]{
-	"container run": {
-		"shortBoolFlags": sets.New[string]("-d", "-i", "-t"),
-		"longBoolFlags": sets.New[string](
-			"--detach", "--init", "--interactive", "--oom-kill-disable",
-			"--privileged", "--read-only", "--rm", "--rootfs", "--tty", "--sig-proxy"),
-		"shortArgFlags": sets.New[string]("-e", "-h", "-m", "-u", "-w", "-p", "-l", "-v"),
-	},
-	"exec": {
-		"shortBoolFlags": sets.New[string]("-d", "-i", "-t"),
-		"longBoolFlags": sets.New[string](
-			"--detach", "--init", "--interactive", "--oom-kill-disable",
-			"--privileged", "--read-only", "--rm", "--rootfs", "--tty"),
-		"shortArgFlags": sets.New[string]("-e", "-h", "-m", "-u", "-w", "-p", "-l", "-v"),
-	},
-	"compose": {
-		"shortBoolFlags": sets.New[string]("-d", "-i", "-t"),
-		"longBoolFlags": sets.New[string](
-			"--detach", "--init", "--interactive", "--oom-kill-disable",
-			"--privileged", "--read-only", "--rm", "--rootfs", "--tty"),
-		"shortArgFlags": sets.New[string]("-e", "-h", "-m", "-u", "-w", "-p", "-l", "-v"),
-	},
-}
-
-// converts "docker build --load" flag to "nerdctl build --output=type=docker".
-func handleDockerBuildLoad(_ NerdctlCommandSystemDeps, fc *config.Finch, nerdctlCmdArgs []string, index int) error {
-	if fc != nil && fc.DockerCompat {
-		nerdctlCmdArgs[index] = "--output=type=docker"
-	}
-
-	return nil
-}
-
-func handleBuildx(_ NerdctlCommandSystemDeps, fc *config.Finch, cmdName *string, args *[]string, _ *string) error {
-	if fc == nil || !fc.DockerCompat {
-		return nil
+// execute runs the nerdctl command built from tc, where runArgs are the arguments passed to the nerdctl command creator.
+func (nc *nerdctlCommand) execute(tc *translate.Command, runArgs []string) error {
+	if nc.shouldReplaceForHelp(tc.Name, tc.Args) {
+		return nc.ncc.RunWithReplacingStdout([]command.Replacement{{Source: "nerdctl", Target: "finch"}}, runArgs...)
 	}
 
-	if cmdName != nil && *cmdName == "buildx" {
-		subCmd := (*args)[0]
-		buildxSubcommands := []string{"bake", "create", "debug", "du", "imagetools", "inspect", "ls", "prune", "rm", "stop", "use", "version"}
-
-		if slices.Contains(buildxSubcommands, subCmd) {
-			return fmt.Errorf("unsupported buildx command: %s", subCmd)
-		}
-
-		logrus.Warn("buildx is not supported. using standard buildkit instead...")
-		if subCmd == "build" {
-			*args = (*args)[1:]
-		}
-		*cmdName = "build"
+	if tc.InspectType == "container" && nc.fc.DockerCompat && !slices.Contains(runArgs, "--format") {
+		runArgs = append(runArgs, "--format", "{{json .}}")
+		cmd := nc.ncc.Create(runArgs...)
+		return inspectContainerOutputHandler(cmd)
 	}
-	// else, continue with the original command
-	return nil
-}
 
-func handleDockerCompatInspect(_ NerdctlCommandSystemDeps, fc *config.Finch, cmdName *string, args *[]string, inspectType *string) error {
-	if fc == nil || !fc.DockerCompat {
+	if err := handleDockerCompatComposeVersion(tc.Name, *nc, tc.Args); err == nil {
 		return nil
 	}
 
-	if *args == nil {
-		return fmt.Errorf("invalid arguments: args (null pointer)")
-	}
-
-	modeDockerCompat := `--mode=dockercompat`
-	sizeArg := ""
-	savedArgs := []string{}
-	skip := false
-	*inspectType = ""
-
-	for idx, arg := range *args {
-		if skip {
-			skip = false
-			continue
-		}
-
-		if (arg == "--type") && (idx < len(*args)-1) {
-			*inspectType = (*args)[idx+1]
-			skip = true
-			continue
-		}
-
-		if strings.Contains(arg, "--type") && strings.Contains(arg, "=") {
-			*inspectType = strings.Split(arg, "=")[1]
-			continue
-		}
-
-		if (arg == "--size") || (arg == "-s") {
-			sizeArg = "--size"
-			continue
-		}
-
-		savedArgs = append(savedArgs, arg)
-	}
-
-	switch *inspectType {
-	case "image":
-		*cmdName = "image inspect"
-		*args = append([]string{modeDockerCompat}, savedArgs...)
-	case "volume":
-		*cmdName = "volume inspect"
-		if sizeArg != "" {
-			*args = append([]string{sizeArg}, savedArgs...)
-		} else {
-			*args = append([]string{}, savedArgs...)
-		}
-	case "container":
-		*cmdName = "inspect"
-		*args = append([]string{modeDockerCompat}, savedArgs...)
-	case "":
-		*cmdName = "inspect"
-		*args = append([]string{modeDockerCompat}, savedArgs...)
-		*inspectType = "container"
-	default:
-		return fmt.Errorf("unsupported inspect type: %s", *inspectType)
-	}
-
-	return nil
-}
-
-// handles the argument & value of --mount option
-//
-//	invokes OS specific path handler for source path of the bind mount
-//	and removes the consistency key-value entity from value
-func handleBindMounts(systemDeps NerdctlCommandSystemDeps, _ *config.Finch, nerdctlCmdArgs []string, index int) error {
-	prefix := nerdctlCmdArgs[index]
-	var (
-		v      string
-		found  bool
-		before string
-	)
-	if strings.Contains(nerdctlCmdArgs[index], "=") {
-		before, v, found = strings.Cut(prefix, "=")
-	} else {
-		if (index + 1) < len(nerdctlCmdArgs) {
-			v = nerdctlCmdArgs[index+1]
-		} else {
-			return fmt.Errorf("invalid positional parameter for %s", prefix)
-		}
-	}
-
-	// eg --mount type=bind,source="$(pwd)"/target,target=/app,readonly
-	// eg --mount type=bind, source=${pwd}/source_dir, target=<path>/target_dir, consistency=cached
-	// https://docs.docker.com/storage/bind-mounts/#choose-the--v-or---mount-flag  order does not matter, so convert to a map
-	entries := strings.Split(v, ",")
-	m := make(map[string]string)
-	ro := []string{}
-	for _, e := range entries {
-		parts := strings.Split(e, "=")
-		if len(parts) < 2 {
-			ro = append(ro, parts...)
-		} else {
-			m[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
-		}
-	}
-	// Check if type is bind mount, else return
-	if m["type"] != "bind" {
-		return nil
-	}
-
-	// Remove 'consistency' key-value pair, if present
-	delete(m, "consistency")
-
-	// Invoke the OS specific path handler
-	err := handleBindMountPath(systemDeps, m)
-	if err != nil {
-		return err
-	}
-
-	// Convert to string representation
-	s := mapToString(m)
-	// append read-only key if present
-	if len(ro) > 0 {
-		s = s + "," + strings.Join(ro, ",")
-	}
-	if found {
-		nerdctlCmdArgs[index] = fmt.Sprintf("%s=%s", before, s)
-	} else {
-		nerdctlCmdArgs[index+1] = s
-	}
-
-	return nil
+	defer timing.Start("nerdctl")()
+	return nc.ncc.Create(runArgs...).Run()
 }
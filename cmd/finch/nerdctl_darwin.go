@@ -6,55 +6,77 @@
 package main
 
 import (
-	"fmt"
 	"strings"
 
-	dockerops "github.com/docker/docker/opts"
 	"github.com/lima-vm/lima/pkg/networks"
 
-	"github.com/runfinch/finch/pkg/command"
-	"github.com/runfinch/finch/pkg/flog"
+	"github.com/runfinch/finch/pkg/config"
+	"github.com/runfinch/finch/pkg/pathmap"
+	"github.com/runfinch/finch/pkg/translate"
 )
 
 func convertToWSLPath(_ NerdctlCommandSystemDeps, _ string) (string, error) {
 	return "", nil
 }
 
-var osAliasMap = map[string]string{}
-
-var osArgHandlerMap = map[string]map[string]argHandler{}
-
-var osCommandHandlerMap = map[string]commandHandler{}
-
 func (nc *nerdctlCommand) GetCmdArgs() []string {
 	return []string{"shell", limaInstanceName, "sudo", "-E"}
 }
 
-func resolveIP(host string, logger flog.Logger, _ command.Creator) (string, error) {
-	parts := strings.SplitN(host, ":", 2)
-	// If the IP Address is a string called "host-gateway", replace this value with the IP address that can be used to
-	// access host from the containers.
-	// TODO: make the host gateway ip configurable.
-	var resolvedIP string
-	if parts[1] == dockerops.HostGatewayName {
-		resolvedIP = networks.SlirpGateway
-
-		logger.Debugf(`Resolving special IP "host-gateway" to %q for host %q`, resolvedIP, parts[0])
-		return fmt.Sprintf("%s:%s", parts[0], resolvedIP), nil
+// translateHooks returns the macOS specific parts of the translation.
+// Host paths are shared into the VM at the same location, so they are not converted.
+func (nc *nerdctlCommand) translateHooks() translate.Hooks {
+	return translate.Hooks{
+		HostGatewayIP: func() (string, error) {
+			return networks.SlirpGateway, nil
+		},
+		CheckHostPath: nc.checkSharedPath,
 	}
-	return host, nil
 }
 
-func handleBindMountPath(_ NerdctlCommandSystemDeps, _ map[string]string) error {
-	// Do nothing by default
+// checkSharedPath warns when a bind mount source is not in a directory shared into the VM.
+// nerdctl does not fail in that case; it creates an empty directory in the VM and mounts it instead.
+func (nc *nerdctlCommand) checkSharedPath(hostPath string) error {
+	if nc.fc == nil {
+		return nil
+	}
+	if nc.sharedPaths == nil {
+		dirs, err := nc.limaMounts()
+		if err != nil {
+			nc.logger.Debugf("Skipping the check of bind mount sources: %v", err)
+			return nil
+		}
+		nc.sharedPaths = pathmap.Identity(dirs...)
+	}
+
+	// e.g. /tmp is a symlink to /private/tmp
+	if resolved, err := nc.systemDeps.EvalSymlinks(hostPath); err == nil {
+		hostPath = resolved
+	}
+	if _, err := nc.sharedPaths.ToGuest(hostPath); err == nil {
+		return nil
+	}
+	nc.logger.Warnf("%q is not shared with the VM, so it will be mounted as an empty directory. "+
+		`To share it, add "- path: %s" to additional_directories in finch.yaml and restart the VM`, hostPath, hostPath)
 	return nil
 }
 
-func mapToString(m map[string]string) string {
-	var parts []string
-	for k, v := range m {
-		part := fmt.Sprintf("%s=%s", k, v)
-		parts = append(parts, part)
+// limaMounts returns the host directories shared into the VM, with "~" expanded.
+func (nc *nerdctlCommand) limaMounts() ([]string, error) {
+	locations, err := config.LimaMounts(nc.fs, nc.fc, nc.fp.BaseYamlFilePath())
+	if err != nil {
+		return nil, err
+	}
+	dirs := make([]string, 0, len(locations))
+	for _, loc := range locations {
+		if rest, ok := strings.CutPrefix(loc, "~"); ok {
+			home, err := nc.systemDeps.GetUserHome()
+			if err != nil {
+				return nil, err
+			}
+			loc = nc.systemDeps.FilePathJoin(home, rest)
+		}
+		dirs = append(dirs, loc)
 	}
-	return strings.Join(parts, ",")
+	return dirs, nil
 }
@@ -65,7 +65,7 @@ func TestNerdctlCommand_runAdaptor(t *testing.T) {
 			logger := mocks.NewLogger(ctrl)
 			tc.mockSvc(ncc, logger, ctrl, ncsd)
 
-			assert.NoError(t, newNerdctlCommand(ncc, ecc, ncsd, logger, nil, &config.Finch{}).runAdapter(tc.cmd, tc.args))
+			assert.NoError(t, newNerdctlCommand(ncc, ecc, ncsd, logger, nil, &config.Finch{}, mockFinchPath).runAdapter(tc.cmd, tc.args))
 		})
 	}
 }
@@ -282,7 +282,7 @@ func TestNerdctlCommand_run_pullCommand(t *testing.T) {
 			fs := afero.NewMemMapFs()
 			tc.mockSvc(t, ncc, ecc, ncsd, logger, ctrl, fs)
 
-			assert.Equal(t, tc.wantErr, newNerdctlCommand(ncc, ecc, ncsd, logger, fs, tc.fc).run(tc.cmdName, tc.args))
+			assert.Equal(t, tc.wantErr, newNerdctlCommand(ncc, ecc, ncsd, logger, fs, tc.fc, mockFinchPath).run(tc.cmdName, tc.args))
 		})
 	}
 }
@@ -817,19 +817,23 @@ func TestNerdctlCommand_run(t *testing.T) {
 			},
 			wantErr: nil,
 			mockSvc: func(
-				_ *testing.T,
+				t *testing.T,
 				lcc *mocks.NerdctlCmdCreator,
 				_ *mocks.CommandCreator,
 				ncsd *mocks.NerdctlCommandSystemDeps,
 				logger *mocks.Logger,
 				ctrl *gomock.Controller,
-				_ afero.Fs,
+				fs afero.Fs,
 			) {
 				getVMStatusC := mocks.NewCommand(ctrl)
 				lcc.EXPECT().CreateWithoutStdio("ls", "-f", "{{.Status}}", limaInstanceName).Return(getVMStatusC)
 				getVMStatusC.EXPECT().Output().Return([]byte("Running"), nil)
 				logger.EXPECT().Debugf("Status of virtual machine: %s", "Running")
 				AddEmptyEnvLookUps(ncsd)
+				writeBaseYaml(t, fs)
+				ncsd.EXPECT().GetUserHome().Return("/Users/user", nil)
+				ncsd.EXPECT().FilePathJoin("/Users/user", "").Return("/Users/user")
+				ncsd.EXPECT().EvalSymlinks("/tmp").Return("/private/tmp", nil).Times(4)
 				c := mocks.NewCommand(ctrl)
 				lcc.EXPECT().Create("shell", limaInstanceName, "sudo", "-E", nerdctlCmdName, "container", "run",
 					"--rm", "-v", "/tmp:/tmp1/tmp2:rro", "--volume", "/tmp:/tmp1:rprivate,rro", "-v", "/tmp:/tmp1/tmp2/tmp3/tmp4:rro",
@@ -847,19 +851,23 @@ func TestNerdctlCommand_run(t *testing.T) {
 			},
 			wantErr: nil,
 			mockSvc: func(
-				_ *testing.T,
+				t *testing.T,
 				lcc *mocks.NerdctlCmdCreator,
 				_ *mocks.CommandCreator,
 				ncsd *mocks.NerdctlCommandSystemDeps,
 				logger *mocks.Logger,
 				ctrl *gomock.Controller,
-				_ afero.Fs,
+				fs afero.Fs,
 			) {
 				getVMStatusC := mocks.NewCommand(ctrl)
 				lcc.EXPECT().CreateWithoutStdio("ls", "-f", "{{.Status}}", limaInstanceName).Return(getVMStatusC)
 				getVMStatusC.EXPECT().Output().Return([]byte("Running"), nil)
 				logger.EXPECT().Debugf("Status of virtual machine: %s", "Running")
 				AddEmptyEnvLookUps(ncsd)
+				writeBaseYaml(t, fs)
+				ncsd.EXPECT().GetUserHome().Return("/Users/user", nil)
+				ncsd.EXPECT().FilePathJoin("/Users/user", "").Return("/Users/user")
+				ncsd.EXPECT().EvalSymlinks("/tmp").Return("/private/tmp", nil).Times(4)
 				c := mocks.NewCommand(ctrl)
 				lcc.EXPECT().Create("shell", limaInstanceName, "sudo", "-E", nerdctlCmdName, "container", "run",
 					"--rm", "-v", "/tmp:/tmp1/tmp2:rro", "--volume", "/tmp:/tmp1:rprivate,rro",
@@ -898,6 +906,83 @@ func TestNerdctlCommand_run(t *testing.T) {
 				c.EXPECT().Run()
 			},
 		},
+		{
+			name:    "bind mount source that is not shared with the VM",
+			cmdName: "run",
+			fc:      &config.Finch{},
+			args:    []string{"-v", "/Volumes/work/project:/app", "alpine:latest"},
+			wantErr: nil,
+			mockSvc: func(
+				t *testing.T,
+				lcc *mocks.NerdctlCmdCreator,
+				_ *mocks.CommandCreator,
+				ncsd *mocks.NerdctlCommandSystemDeps,
+				logger *mocks.Logger,
+				ctrl *gomock.Controller,
+				fs afero.Fs,
+			) {
+				getVMStatusC := mocks.NewCommand(ctrl)
+				lcc.EXPECT().CreateWithoutStdio("ls", "-f", "{{.Status}}", limaInstanceName).Return(getVMStatusC)
+				getVMStatusC.EXPECT().Output().Return([]byte("Running"), nil)
+				logger.EXPECT().Debugf("Status of virtual machine: %s", "Running")
+				AddEmptyEnvLookUps(ncsd)
+				writeBaseYaml(t, fs)
+				ncsd.EXPECT().GetUserHome().Return("/Users/user", nil)
+				ncsd.EXPECT().FilePathJoin("/Users/user", "").Return("/Users/user")
+				ncsd.EXPECT().EvalSymlinks("/Volumes/work/project").Return("", errors.New("no such file or directory"))
+				logger.EXPECT().Warnf("%q is not shared with the VM, so it will be mounted as an empty directory. "+
+					`To share it, add "- path: %s" to additional_directories in finch.yaml and restart the VM`,
+					"/Volumes/work/project", "/Volumes/work/project")
+				c := mocks.NewCommand(ctrl)
+				lcc.EXPECT().Create("shell", limaInstanceName, "sudo", "-E", nerdctlCmdName, "container", "run",
+					"-v", "/Volumes/work/project:/app", "alpine:latest").Return(c)
+				c.EXPECT().Run()
+			},
+		},
+		{
+			name:    "compose bind mount source that is not shared with the VM",
+			cmdName: "compose",
+			fc:      &config.Finch{},
+			args:    []string{"-f", "/Users/user/project/compose.yaml", "up"},
+			wantErr: nil,
+			mockSvc: func(
+				t *testing.T,
+				lcc *mocks.NerdctlCmdCreator,
+				_ *mocks.CommandCreator,
+				ncsd *mocks.NerdctlCommandSystemDeps,
+				logger *mocks.Logger,
+				ctrl *gomock.Controller,
+				fs afero.Fs,
+			) {
+				getVMStatusC := mocks.NewCommand(ctrl)
+				lcc.EXPECT().CreateWithoutStdio("ls", "-f", "{{.Status}}", limaInstanceName).Return(getVMStatusC)
+				getVMStatusC.EXPECT().Output().Return([]byte("Running"), nil)
+				logger.EXPECT().Debugf("Status of virtual machine: %s", "Running")
+				AddEmptyEnvLookUps(ncsd)
+				writeBaseYaml(t, fs)
+				require.NoError(t, afero.WriteFile(fs, "/Users/user/project/compose.yaml", []byte(`services:
+  web:
+    volumes:
+      - ./src:/app
+      - type: bind
+        source: /Volumes/data
+        target: /data
+      - cache:/cache
+`), 0o600))
+				ncsd.EXPECT().FilePathJoin("/Users/user/project", "./src").Return("/Users/user/project/src")
+				ncsd.EXPECT().GetUserHome().Return("/Users/user", nil)
+				ncsd.EXPECT().FilePathJoin("/Users/user", "").Return("/Users/user")
+				ncsd.EXPECT().EvalSymlinks("/Users/user/project/src").Return("/Users/user/project/src", nil)
+				ncsd.EXPECT().EvalSymlinks("/Volumes/data").Return("/Volumes/data", nil)
+				logger.EXPECT().Warnf("%q is not shared with the VM, so it will be mounted as an empty directory. "+
+					`To share it, add "- path: %s" to additional_directories in finch.yaml and restart the VM`,
+					"/Volumes/data", "/Volumes/data")
+				c := mocks.NewCommand(ctrl)
+				lcc.EXPECT().Create("shell", limaInstanceName, "sudo", "-E", nerdctlCmdName, "compose",
+					"-f", "/Users/user/project/compose.yaml", "up").Return(c)
+				c.EXPECT().Run()
+			},
+		},
 		{
 			name:    "bindmount with src and consistency",
 			cmdName: "run",
@@ -905,22 +990,28 @@ func TestNerdctlCommand_run(t *testing.T) {
 			args:    []string{"--mount", "type=bind,src=./src,consistency=cached", "alpine:latest"},
 			wantErr: nil,
 			mockSvc: func(
-				_ *testing.T,
+				t *testing.T,
 				lcc *mocks.NerdctlCmdCreator,
 				_ *mocks.CommandCreator,
 				ncsd *mocks.NerdctlCommandSystemDeps,
 				logger *mocks.Logger,
 				ctrl *gomock.Controller,
-				_ afero.Fs,
+				fs afero.Fs,
 			) {
 				getVMStatusC := mocks.NewCommand(ctrl)
 				lcc.EXPECT().CreateWithoutStdio("ls", "-f", "{{.Status}}", limaInstanceName).Return(getVMStatusC)
 				getVMStatusC.EXPECT().Output().Return([]byte("Running"), nil)
 				logger.EXPECT().Debugf("Status of virtual machine: %s", "Running")
 				AddEmptyEnvLookUps(ncsd)
+				ncsd.EXPECT().FilePathAbs("./src").Return("/Users/user/src", nil)
+				writeBaseYaml(t, fs)
+				ncsd.EXPECT().GetUserHome().Return("/Users/user", nil)
+				ncsd.EXPECT().FilePathJoin("/Users/user", "").Return("/Users/user")
+				ncsd.EXPECT().EvalSymlinks("/Users/user/src").Return("/Users/user/src", nil)
 				c := mocks.NewCommand(ctrl)
 				lcc.EXPECT().Create("shell", limaInstanceName, "sudo", "-E", nerdctlCmdName, "container", "run",
-					"--mount", ContainsMultipleStrs([]string{"bind", "type", "!consistency"}), "alpine:latest").Return(c)
+					"--mount", ContainsMultipleStrs([]string{"type=bind", "src=/Users/user/src", "!consistency"}),
+					"alpine:latest").Return(c)
 				c.EXPECT().Run()
 			},
 		},
@@ -1000,7 +1091,7 @@ func TestNerdctlCommand_run(t *testing.T) {
 			fs := afero.NewMemMapFs()
 			tc.mockSvc(t, lcc, ecc, ncsd, logger, ctrl, fs)
 
-			assert.Equal(t, tc.wantErr, newNerdctlCommand(lcc, ecc, ncsd, logger, fs, tc.fc).run(tc.cmdName, tc.args))
+			assert.Equal(t, tc.wantErr, newNerdctlCommand(lcc, ecc, ncsd, logger, fs, tc.fc, mockFinchPath).run(tc.cmdName, tc.args))
 		})
 	}
 }
@@ -1201,7 +1292,7 @@ func TestNerdctlCommand_run_inspectCommand(t *testing.T) {
 			fs := afero.NewMemMapFs()
 			tc.mockSvc(t, ncc, ecc, ncsd, logger, ctrl, fs)
 
-			assert.Equal(t, tc.wantErr, newNerdctlCommand(ncc, ecc, ncsd, logger, fs, tc.fc).run(tc.cmdName, tc.args))
+			assert.Equal(t, tc.wantErr, newNerdctlCommand(ncc, ecc, ncsd, logger, fs, tc.fc, mockFinchPath).run(tc.cmdName, tc.args))
 		})
 	}
 }
@@ -1292,7 +1383,7 @@ func TestNerdctlCommand_run_buildxCommand(t *testing.T) {
 			fs := afero.NewMemMapFs()
 			tc.mockSvc(t, ncc, ecc, ncsd, logger, ctrl, fs)
 
-			assert.Equal(t, tc.wantErr, newNerdctlCommand(ncc, ecc, ncsd, logger, fs, tc.fc).run(tc.cmdName, tc.args))
+			assert.Equal(t, tc.wantErr, newNerdctlCommand(ncc, ecc, ncsd, logger, fs, tc.fc, mockFinchPath).run(tc.cmdName, tc.args))
 		})
 	}
 }
@@ -1402,6 +1493,72 @@ func TestNerdctlCommand_run_miscCommand(t *testing.T) {
 				c.EXPECT().Run()
 			},
 		},
+		{
+			name:    "with compose --env-file",
+			cmdName: "compose",
+			fc:      &config.Finch{},
+			args:    []string{"--env-file", "/env-file", "up", "-d"},
+			wantErr: nil,
+			mockSvc: func(
+				t *testing.T,
+				lcc *mocks.NerdctlCmdCreator,
+				_ *mocks.CommandCreator,
+				ncsd *mocks.NerdctlCommandSystemDeps,
+				logger *mocks.Logger,
+				ctrl *gomock.Controller,
+				fs afero.Fs,
+			) {
+				envFileStr := "# a comment\nexport IMAGE='alpine'\nTAG=\"${IMAGE}-latest\" # inline comment\nPORT=8080\n"
+				require.NoError(t, afero.WriteFile(fs, "/env-file", []byte(envFileStr), 0o600))
+
+				getVMStatusC := mocks.NewCommand(ctrl)
+				lcc.EXPECT().CreateWithoutStdio("ls", "-f", "{{.Status}}", limaInstanceName).Return(getVMStatusC)
+				getVMStatusC.EXPECT().Output().Return([]byte("Running"), nil)
+				logger.EXPECT().Debugf("Status of virtual machine: %s", "Running")
+				AddEmptyEnvLookUps(ncsd)
+				ncsd.EXPECT().LookupEnv("IMAGE").Return("", false).Times(2)
+				ncsd.EXPECT().LookupEnv("TAG").Return("", false)
+				ncsd.EXPECT().LookupEnv("PORT").Return("9090", true)
+				// bind mounts of the project files are checked, there are none
+				ncsd.EXPECT().GetWd().Return("/project", nil)
+				ncsd.EXPECT().Env("COMPOSE_FILE").Return("")
+				ncsd.EXPECT().FilePathJoin(gomock.Any()).DoAndReturn(filepath.Join).AnyTimes()
+				c := mocks.NewCommand(ctrl)
+				lcc.EXPECT().Create("shell", limaInstanceName, "sudo", "-E",
+					"IMAGE=alpine", "TAG=alpine-latest", "PORT=9090",
+					nerdctlCmdName, "compose", "--env-file", "/dev/null", "up", "-d").Return(c)
+				c.EXPECT().Run()
+			},
+		},
+		{
+			name:    "with create --env-file",
+			cmdName: "create",
+			fc:      &config.Finch{},
+			args:    []string{"--env-file", "/env-file", "-e", "B=override", "alpine:latest", "env"},
+			wantErr: nil,
+			mockSvc: func(
+				t *testing.T,
+				lcc *mocks.NerdctlCmdCreator,
+				_ *mocks.CommandCreator,
+				ncsd *mocks.NerdctlCommandSystemDeps,
+				logger *mocks.Logger,
+				ctrl *gomock.Controller,
+				fs afero.Fs,
+			) {
+				envFileStr := "A=\"quoted value\"\r\nB=1\r\n"
+				require.NoError(t, afero.WriteFile(fs, "/env-file", []byte(envFileStr), 0o600))
+
+				getVMStatusC := mocks.NewCommand(ctrl)
+				lcc.EXPECT().CreateWithoutStdio("ls", "-f", "{{.Status}}", limaInstanceName).Return(getVMStatusC)
+				getVMStatusC.EXPECT().Output().Return([]byte("Running"), nil)
+				logger.EXPECT().Debugf("Status of virtual machine: %s", "Running")
+				AddEmptyEnvLookUps(ncsd)
+				c := mocks.NewCommand(ctrl)
+				lcc.EXPECT().Create("shell", limaInstanceName, "sudo", "-E", nerdctlCmdName, "create",
+					"-e", `A="quoted value"`, "-e", "B=override", "alpine:latest", "env").Return(c)
+				c.EXPECT().Run()
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -1416,7 +1573,7 @@ func TestNerdctlCommand_run_miscCommand(t *testing.T) {
 			fs := afero.NewMemMapFs()
 			tc.mockSvc(t, ncc, ecc, ncsd, logger, ctrl, fs)
 
-			assert.Equal(t, tc.wantErr, newNerdctlCommand(ncc, ecc, ncsd, logger, fs, tc.fc).run(tc.cmdName, tc.args))
+			assert.Equal(t, tc.wantErr, newNerdctlCommand(ncc, ecc, ncsd, logger, fs, tc.fc, mockFinchPath).run(tc.cmdName, tc.args))
 		})
 	}
 }
@@ -1488,3 +1645,12 @@ func (m *ContainsMultipleSubstrings) String() string {
 func ContainsMultipleStrs(substrs []string) gomock.Matcher {
 	return &ContainsMultipleSubstrings{substrs: substrs}
 }
+
+// writeBaseYaml writes the base Lima config with the mounts of finch.yaml.d/mac.yaml.
+func writeBaseYaml(t *testing.T, fs afero.Fs) {
+	t.Helper()
+
+	baseYaml := "mounts:\n  - location: \"~\"\n  - location: \"/tmp/lima\"\n  - location: \"/private\"\n" +
+		"  - location: \"/var/folders\"\n"
+	require.NoError(t, afero.WriteFile(fs, mockFinchPath.BaseYamlFilePath(), []byte(baseYaml), 0o600))
+}
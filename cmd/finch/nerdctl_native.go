@@ -6,111 +6,78 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/netip"
 	"strings"
 
-	"golang.org/x/exp/slices"
-
-	"github.com/runfinch/finch/pkg/command"
-	"github.com/runfinch/finch/pkg/flog"
+	"github.com/runfinch/finch/pkg/timing"
+	"github.com/runfinch/finch/pkg/translate"
 )
 
-func (nc *nerdctlCommand) run(cmdName string, args []string) error {
-	var (
-		hasCmdHandler, hasArgHandler bool
-		cmdHandler                   commandHandler
-		aMap                         map[string]argHandler
-		err                          error
-		inspectType                  string
-	)
-
-	// eat the debug arg, and set the log level to avoid nerdctl parsing this flag
-	dbgIdx := slices.Index(args, "--debug")
-	if dbgIdx >= 0 {
-		args = append(args[:dbgIdx], args[dbgIdx+1:]...)
-		nc.logger.SetLevel(flog.Debug)
-	}
+// defaultNetwork is the network that nerdctl attaches containers to by default.
+const defaultNetwork = "bridge"
 
-	alias, hasAlias := aliasMap[cmdName]
-	if hasAlias {
-		cmdHandler, hasCmdHandler = commandHandlerMap[alias]
-		aMap, hasArgHandler = argHandlerMap[alias]
-	} else {
-		cmdHandler, hasCmdHandler = commandHandlerMap[cmdName]
-		aMap, hasArgHandler = argHandlerMap[cmdName]
-
-		if !hasArgHandler && len(args) > 0 {
-			// for commands like image build, container run
-			key := fmt.Sprintf("%s %s", cmdName, args[0])
-			cmdHandler, hasCmdHandler = commandHandlerMap[key]
-			aMap, hasArgHandler = argHandlerMap[key]
-		}
+func (nc *nerdctlCommand) run(cmdName string, args []string) error {
+	endTranslation := timing.Start("translation")
+	tc, err := nc.translator().Translate(cmdName, args)
+	endTranslation()
+	if err != nil {
+		return err
 	}
 
-	// First check if the command has a command handler
-	if hasCmdHandler {
-		err := cmdHandler(nc.systemDeps, nc.fc, &cmdName, &args, &inspectType)
-		if err != nil {
+	// nerdctl runs as a child of this process, so it inherits the environment.
+	for _, e := range tc.Env {
+		k, v, _ := strings.Cut(e, "=")
+		if err := nc.systemDeps.Setenv(k, v); err != nil {
 			return err
 		}
 	}
 
-	for i, arg := range args {
-		// Check if individual argument (and possibly following value) requires manipulation-in-place handling
-		if hasArgHandler {
-			// Check if argument for the command needs handling, sometimes it can be --file=<filename>
-			b, _, _ := strings.Cut(arg, "=")
-			h, ok := aMap[b]
-			if ok {
-				err = h(nc.systemDeps, nc.fc, args, i)
-				if err != nil {
-					return err
-				}
-			}
-		}
-	}
-
-	// Extra manipulation for cases that overwrite cmdName with alias
-	splitName := strings.Split(cmdName, " ")
-	cmdArgs := append([]string{splitName[0]}, splitName[1:]...)
-	cmdArgs = append(cmdArgs, args...)
-
-	if nc.shouldReplaceForHelp(splitName[0], args) {
-		return nc.ncc.RunWithReplacingStdout(
-			[]command.Replacement{{Source: "nerdctl", Target: "finch"}},
-			cmdArgs...,
-		)
-	}
+	return nc.execute(tc, append(strings.Fields(tc.Name), tc.Args...))
+}
 
-	if inspectType == "container" && nc.fc.DockerCompat && !slices.Contains(cmdArgs, "--format") {
-		cmdArgs = append(cmdArgs, "--format", "{{json .}}")
-		cmd := nc.ncc.Create(cmdArgs...)
-		return inspectContainerOutputHandler(cmd)
+// translateHooks returns the Linux specific parts of the translation.
+// nerdctl runs on the host, so paths are not converted.
+func (nc *nerdctlCommand) translateHooks() translate.Hooks {
+	return translate.Hooks{
+		HostGatewayIP: nc.bridgeGatewayIP,
 	}
+}
 
-	if err := handleDockerCompatComposeVersion(cmdName, *nc, cmdArgs); err == nil {
-		return nil
+// bridgeGatewayIP returns the gateway of the default CNI bridge network, which is the host's address on that network.
+func (nc *nerdctlCommand) bridgeGatewayIP() (string, error) {
+	out, err := nc.ncc.CreateWithoutStdio("network", "inspect", defaultNetwork).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to inspect the %s network: %w", defaultNetwork, err)
 	}
-
-	return nc.ncc.Create(cmdArgs...).Run()
+	return extractGatewayIP(out)
 }
 
-var osAliasMap = map[string]string{}
-
-var osArgHandlerMap = map[string]map[string]argHandler{}
-
-var osCommandHandlerMap = map[string]commandHandler{}
-
-func mapToString(m map[string]string) string {
-	var parts []string
-	for k, v := range m {
-		part := fmt.Sprintf("%s=%s", k, v)
-		parts = append(parts, part)
+// extractGatewayIP extracts the gateway from the output of nerdctl network inspect.
+// The first address of the subnet is used when the network does not set the gateway explicitly.
+func extractGatewayIP(out []byte) (string, error) {
+	var networks []struct {
+		IPAM struct {
+			Config []struct {
+				Subnet  string `json:"Subnet"`
+				Gateway string `json:"Gateway"`
+			} `json:"Config"`
+		} `json:"IPAM"`
+	}
+	if err := json.Unmarshal(out, &networks); err != nil {
+		return "", fmt.Errorf("failed to unmarshal network inspect output: %w", err)
 	}
-	return strings.Join(parts, ",")
-}
 
-func handleBindMountPath(_ NerdctlCommandSystemDeps, _ map[string]string) error {
-	// Do nothing by default
-	return nil
+	for _, n := range networks {
+		for _, c := range n.IPAM.Config {
+			if c.Gateway != "" {
+				return c.Gateway, nil
+			}
+			if prefix, err := netip.ParsePrefix(c.Subnet); err == nil {
+				return prefix.Masked().Addr().Next().String(), nil
+			}
+		}
+	}
+	return "", fmt.Errorf("failed to find the gateway of the %s network", defaultNetwork)
 }
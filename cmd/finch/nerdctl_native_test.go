@@ -12,9 +12,11 @@ import (
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 
 	"github.com/runfinch/finch/pkg/config"
+	"github.com/runfinch/finch/pkg/dotenv"
 	"github.com/runfinch/finch/pkg/flog"
 	"github.com/runfinch/finch/pkg/mocks"
 )
@@ -53,7 +55,7 @@ func TestNerdctlCommand_runAdaptor(t *testing.T) {
 			logger := mocks.NewLogger(ctrl)
 			tc.mockSvc(ncc, logger, ctrl, ncsd)
 
-			assert.NoError(t, newNerdctlCommand(ncc, ecc, ncsd, logger, nil, &config.Finch{}).runAdapter(tc.cmd, tc.args))
+			assert.NoError(t, newNerdctlCommand(ncc, ecc, ncsd, logger, nil, &config.Finch{}, mockFinchPath).runAdapter(tc.cmd, tc.args))
 		})
 	}
 }
@@ -92,7 +94,7 @@ func TestNerdctlCommand_run(t *testing.T) {
 				_ afero.Fs,
 			) {
 				c := mocks.NewCommand(ctrl)
-				ncc.EXPECT().Create("build", "-t", "demo", ".").Return(c)
+				ncc.EXPECT().Create("image", "build", "-t", "demo", ".").Return(c)
 				c.EXPECT().Run()
 			},
 		},
@@ -156,6 +158,102 @@ func TestNerdctlCommand_run(t *testing.T) {
 					Return(fmt.Errorf("failed to replace"))
 			},
 		},
+		{
+			name:    "with compose --env-file",
+			cmdName: "compose",
+			fc:      &config.Finch{},
+			args:    []string{"-f", "compose.yaml", "--env-file", "/env-file", "up", "--env-file", "ignored"},
+			wantErr: nil,
+			mockSvc: func(
+				t *testing.T,
+				ncc *mocks.NerdctlCmdCreator,
+				_ *mocks.CommandCreator,
+				ncsd *mocks.NerdctlCommandSystemDeps,
+				_ *mocks.Logger,
+				ctrl *gomock.Controller,
+				fs afero.Fs,
+			) {
+				require.NoError(t, afero.WriteFile(fs, "/env-file", []byte("export A='1'\nB=\"${A}\"\n"), 0o600))
+				ncsd.EXPECT().LookupEnv("A").Return("", false).Times(2)
+				ncsd.EXPECT().LookupEnv("B").Return("", false)
+				ncsd.EXPECT().Setenv("A", "1").Return(nil)
+				ncsd.EXPECT().Setenv("B", "1").Return(nil)
+				c := mocks.NewCommand(ctrl)
+				ncc.EXPECT().Create("compose", "-f", "compose.yaml", "--env-file", "/dev/null", "up", "--env-file", "ignored").Return(c)
+				c.EXPECT().Run()
+			},
+		},
+		{
+			name:    "with --add-host host-gateway",
+			cmdName: "run",
+			fc:      &config.Finch{},
+			args:    []string{"--add-host", "name:host-gateway", "alpine"},
+			wantErr: nil,
+			mockSvc: func(
+				_ *testing.T,
+				ncc *mocks.NerdctlCmdCreator,
+				_ *mocks.CommandCreator,
+				_ *mocks.NerdctlCommandSystemDeps,
+				logger *mocks.Logger,
+				ctrl *gomock.Controller,
+				_ afero.Fs,
+			) {
+				inspect := mocks.NewCommand(ctrl)
+				ncc.EXPECT().CreateWithoutStdio("network", "inspect", "bridge").Return(inspect)
+				inspect.EXPECT().Output().Return([]byte(`[{"Name":"bridge","IPAM":{"Config":[{"Subnet":"10.4.0.0/24"}]}}]`), nil)
+				logger.EXPECT().Debugf(`Resolving special IP "host-gateway" to %q for host %q`, "10.4.0.1", "name")
+				c := mocks.NewCommand(ctrl)
+				ncc.EXPECT().Create("container", "run", "--add-host", "name:10.4.0.1", "alpine").Return(c)
+				c.EXPECT().Run()
+			},
+		},
+		{
+			name:    "with host aliases and a configured host gateway",
+			cmdName: "run",
+			fc: &config.Finch{
+				SharedSettings: config.SharedSettings{
+					HostGatewayIP: "172.17.0.1",
+					HostAliases:   true,
+				},
+			},
+			args:    []string{"alpine"},
+			wantErr: nil,
+			mockSvc: func(
+				_ *testing.T,
+				ncc *mocks.NerdctlCmdCreator,
+				_ *mocks.CommandCreator,
+				_ *mocks.NerdctlCommandSystemDeps,
+				_ *mocks.Logger,
+				ctrl *gomock.Controller,
+				_ afero.Fs,
+			) {
+				c := mocks.NewCommand(ctrl)
+				ncc.EXPECT().Create("container", "run",
+					"--add-host", "host.finch.internal:172.17.0.1",
+					"--add-host", "host.docker.internal:172.17.0.1",
+					"alpine").Return(c)
+				c.EXPECT().Run()
+			},
+		},
+		{
+			name:    "with malformed compose --env-file",
+			cmdName: "compose",
+			fc:      &config.Finch{},
+			args:    []string{"--env-file=/env-file", "up"},
+			wantErr: fmt.Errorf("failed to parse env file %q: %w", "/env-file",
+				&dotenv.ParseError{Line: 2, Msg: "unterminated quoted value, missing closing \""}),
+			mockSvc: func(
+				t *testing.T,
+				_ *mocks.NerdctlCmdCreator,
+				_ *mocks.CommandCreator,
+				_ *mocks.NerdctlCommandSystemDeps,
+				_ *mocks.Logger,
+				_ *gomock.Controller,
+				fs afero.Fs,
+			) {
+				require.NoError(t, afero.WriteFile(fs, "/env-file", []byte("A=1\nB=\"2\n"), 0o600))
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -170,7 +268,53 @@ func TestNerdctlCommand_run(t *testing.T) {
 			fs := afero.NewMemMapFs()
 			tc.mockSvc(t, ncc, ecc, ncsd, logger, ctrl, fs)
 
-			assert.Equal(t, tc.wantErr, newNerdctlCommand(ncc, ecc, ncsd, logger, fs, tc.fc).run(tc.cmdName, tc.args))
+			assert.Equal(t, tc.wantErr, newNerdctlCommand(ncc, ecc, ncsd, logger, fs, tc.fc, mockFinchPath).run(tc.cmdName, tc.args))
+		})
+	}
+}
+
+func TestExtractGatewayIP(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		out     string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "gateway is set",
+			out:  `[{"IPAM":{"Config":[{"Subnet":"10.4.0.0/24","Gateway":"10.4.0.254"}]}}]`,
+			want: "10.4.0.254",
+		},
+		{
+			name: "gateway is the first address of the subnet",
+			out:  `[{"IPAM":{"Config":[{"Subnet":"10.4.0.0/24"}]}}]`,
+			want: "10.4.0.1",
+		},
+		{
+			name:    "no IPAM config",
+			out:     `[{"IPAM":{}}]`,
+			wantErr: "failed to find the gateway of the bridge network",
+		},
+		{
+			name:    "invalid output",
+			out:     `not json`,
+			wantErr: "failed to unmarshal network inspect output: invalid character 'o' in literal null (expecting 'u')",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := extractGatewayIP([]byte(tc.out))
+			if tc.wantErr != "" {
+				assert.EqualError(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
 		})
 	}
 }
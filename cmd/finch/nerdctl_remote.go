@@ -6,314 +6,37 @@
 package main
 
 import (
-	"bufio"
-	"encoding/json"
+	"context"
 	"fmt"
-	"maps"
 	"path/filepath"
 	"runtime"
 	"strings"
 
-	"github.com/aws/aws-sdk-go-v2/aws"
-	orderedmap "github.com/wk8/go-ordered-map"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
 	"golang.org/x/exp/slices"
-	"k8s.io/apimachinery/pkg/util/sets"
-
-	"github.com/spf13/afero"
 
+	"github.com/runfinch/finch/pkg/awscreds"
 	"github.com/runfinch/finch/pkg/command"
-	"github.com/runfinch/finch/pkg/config"
 	"github.com/runfinch/finch/pkg/flog"
 	"github.com/runfinch/finch/pkg/lima"
+	"github.com/runfinch/finch/pkg/timing"
 )
 
 const nerdctlCmdName = "nerdctl"
 
 func (nc *nerdctlCommand) run(cmdName string, args []string) error {
+	endVMStatus := timing.Start("vm status")
 	err := nc.assertVMIsRunning(nc.ncc, nc.logger)
+	endVMStatus()
 	if err != nil {
 		return err
 	}
-	var (
-		nerdctlArgs, envs, fileEnvs, cmdArgs, runArgs []string
-		skip, hasCmdHandler, hasArgHandler, lastOpt   bool
-		cmdHandler                                    commandHandler
-		aMap                                          map[string]argHandler
-		firstOptPos                                   int
-		inspectType                                   string
-	)
-
-	// accumulate distributed map entities
-	aggAliasMap := make(map[string]string)
-	maps.Copy(aggAliasMap, aliasMap)
-	maps.Copy(aggAliasMap, osAliasMap)
-
-	aggCmdHandlerMap := make(map[string]commandHandler)
-	maps.Copy(aggCmdHandlerMap, commandHandlerMap)
-	maps.Copy(aggCmdHandlerMap, osCommandHandlerMap)
-
-	aggArgHandlerMap := make(map[string]map[string]argHandler)
-	for k := range argHandlerMap {
-		aggArgHandlerMap[k] = make(map[string]argHandler)
-		maps.Copy(aggArgHandlerMap[k], argHandlerMap[k])
-	}
-	for k := range osArgHandlerMap {
-		if _, ok := aggArgHandlerMap[k]; !ok {
-			aggArgHandlerMap[k] = make(map[string]argHandler)
-		}
-		maps.Copy(aggArgHandlerMap[k], osArgHandlerMap[k])
-	}
-
-	alias, hasAlias := aggAliasMap[cmdName]
-	if hasAlias {
-		cmdName = alias
-		cmdHandler, hasCmdHandler = aggCmdHandlerMap[alias]
-		aMap, hasArgHandler = aggArgHandlerMap[alias]
-	} else {
-		// Check if the command has a handler
-		cmdHandler, hasCmdHandler = aggCmdHandlerMap[cmdName]
-		aMap, hasArgHandler = aggArgHandlerMap[cmdName]
-
-		if !hasCmdHandler && !hasArgHandler && len(args) > 0 {
-			// for commands like image build, container run
-			key := fmt.Sprintf("%s %s", cmdName, args[0])
-			cmdHandler, hasCmdHandler = aggCmdHandlerMap[key]
-			aMap, hasArgHandler = aggArgHandlerMap[key]
-		}
-	}
-
-	// First check if the command has command handler
-	if hasCmdHandler {
-		err := cmdHandler(nc.systemDeps, nc.fc, &cmdName, &args, &inspectType)
-		if err != nil {
-			return err
-		}
-	}
-
-	switch cmdName {
-	case "container run", "exec", "compose":
-		// check if an option flag is present; immediately following the command
-		switch {
-		case len(args) > 1 && args[0] == "run" && strings.HasPrefix(args[1], "-"):
-			firstOptPos = 1
-		case len(args) > 0 && strings.HasPrefix(args[0], "-"):
-			firstOptPos = 0
-		case len(args) == 0:
-			args = append(args, "--help")
-			firstOptPos = 0
-		default:
-			firstOptPos = -1
-		}
-		// lastOpt is used to track when no more flags need to be processed
-		lastOpt = false
-
-		shortFlagBoolSet := cmdFlagSetMap[cmdName]["shortBoolFlags"]
-		longFlagBoolSet := cmdFlagSetMap[cmdName]["longBoolFlags"]
-
-		shortFlagArgSet := cmdFlagSetMap[cmdName]["shortArgFlags"]
-
-		for i, arg := range args {
-			// Check if individual argument (and possibly following value) requires manipulation-in-place handling
-			if hasArgHandler {
-				// Check if argument for the command needs handling, sometimes it can be --file=<filename>
-				b, _, _ := strings.Cut(arg, "=")
-				h, ok := aMap[b]
-				if ok {
-					err = h(nc.systemDeps, nc.fc, args, i)
-					if err != nil {
-						return err
-					}
-					// This is required when the positional argument at i is mutated by argHandler, eg -v=C:\Users:/tmp:ro
-					arg = args[i]
-				}
-			}
-
-			// parsing arguments from the command line
-			// may pre-fetch and consume the next argument;
-			// the loop variable will skip any pre-consumed args
-			if skip {
-				skip = false
-				continue
-			}
-
-			// after last Option position is found, pass through each arg as an internal command argument (short-circuit)
-			if lastOpt {
-				cmdArgs = append(cmdArgs, arg)
-				continue
-			}
-			switch {
-			case arg == "--debug":
-				nc.logger.SetLevel(flog.Debug)
-			case arg == "--help":
-				nerdctlArgs = append(nerdctlArgs, arg)
-			case arg == "--add-host":
-				// exact match to --add-host. resolve ip if param passed
-				if len(args) > i+1 {
-					args[i+1], err = resolveIP(args[i+1], nc.logger, nc.ecc)
-					if err != nil {
-						return err
-					}
-				}
-				nerdctlArgs = append(nerdctlArgs, arg)
-			case strings.HasPrefix(arg, "--add-host"):
-				// arg begins with --add-host
-				resolvedIP, err := resolveIP(arg[11:], nc.logger, nc.ecc)
-				if err != nil {
-					return err
-				}
-				arg = fmt.Sprintf("%s%s", arg[0:11], resolvedIP)
-				nerdctlArgs = append(nerdctlArgs, arg)
-			case strings.HasPrefix(arg, "--env-file"):
-				// exact match to --env-file
-				// or arg begins with --env-file
-				if len(args) > i+1 {
-					shouldSkip, addEnvs, err := handleEnvFile(nc.fs, nc.systemDeps, arg, args[i+1])
-					if err != nil {
-						return err
-					}
-					skip = shouldSkip
-					fileEnvs = append(fileEnvs, addEnvs...)
-				} else {
-					// if --env-file is at the end of the args, its refers to entrypoint command
-					// which need not be handled
-					nerdctlArgs = append(nerdctlArgs, arg)
-				}
-			case argIsEnv(arg):
-				// exact match to either -e or --env
-				// or arg begins with -e or --env
-				//     -e="<value>", -e"<value>"
-				//     --env="<key>=<value>", --env"<key>=<value>"
-				if len(args) > i+1 {
-					shouldSkip, addEnv := handleEnv(nc.systemDeps, arg, args[i+1])
-					skip = shouldSkip
-					if addEnv != "" {
-						envs = append(envs, addEnv)
-					}
-				} else {
-					// if -e or --env is at the end of the args, its refers to entrypoint command
-					// which need not be handled
-					nerdctlArgs = append(nerdctlArgs, arg)
-				}
-			case shortFlagBoolSet.Has(arg) || longFlagBoolSet.Has(arg):
-				// exact match to a short no argument flag: -?
-				// or exact match to: --<long_flag>
-				nerdctlArgs = append(nerdctlArgs, arg)
-			case longFlagBoolSet.Has(strings.Split(arg, "=")[0]):
-				// begins with --<long_flag>
-				//    e.g. --sig-proxy=false
-				nerdctlArgs = append(nerdctlArgs, arg)
-			case len(arg) > 1 && shortFlagBoolSet.Has(arg[:2]):
-				// or begins with a defined short no argument flag, but is adjacent to something
-				//   -????   one or more short bool flags; no following values
-				//   -????="<value>" one or more short bool flags ending with a short arg flag equated to value
-				//   -????"<value>" one or more short bool flags ending with a short arg flag concatenated to value
-				addArg := nc.handleMultipleShortFlags(shortFlagBoolSet, shortFlagArgSet, args, i)
-				nerdctlArgs = append(nerdctlArgs, addArg)
-			case shortFlagArgSet.Has(arg) || (len(arg) > 1 && shortFlagArgSet.Has(arg[:2])):
-				// exact match to a short arg flag: -?
-				//     next arg must be the <value>
-				// or begins with a short arg flag:
-				//     short arg flag concatenated to value: -?"<value>"
-				//     short arg flag equated to value: -?="<value>" or -?=<value>
-				if len(args) > i+1 {
-					shouldSkip, addKey, addVal := nc.handleFlagArg(arg, args[i+1])
-					skip = shouldSkip
-					if addKey != "" {
-						nerdctlArgs = append(nerdctlArgs, addKey)
-						nerdctlArgs = append(nerdctlArgs, addVal)
-					}
-				} else {
-					// no value found for short arg flag
-					// pass the arg as a nerdctl command argument
-					nerdctlArgs = append(nerdctlArgs, arg)
-				}
-			case strings.HasPrefix(arg, "--"):
-				// exact match to a long arg flag: -<long_flag>
-				//     next arg must be the <value>
-				// or begins with a long arg flag:
-				//     long arg flag concatenated to value: --<long_flag>"<value>"
-				//     long arg flag equated to value: --<long_flag>="<value>" or --<long_flag>=<value>
-				if len(args) > i+1 {
-					shouldSkip, addKey, addVal := nc.handleFlagArg(arg, args[i+1])
-					skip = shouldSkip
-					if addKey != "" {
-						nerdctlArgs = append(nerdctlArgs, addKey)
-						nerdctlArgs = append(nerdctlArgs, addVal)
-					}
-				} else {
-					// if --<long flag> is at the end of the args, its refers to entrypoint command
-					// which need not be handled
-					nerdctlArgs = append(nerdctlArgs, arg)
-				}
-			default:
-				// arg other than a flag ("-?","--<long_flag>") or a skipped <flag_value>
-				switch {
-				case (i < firstOptPos):
-					// arg is a value prior to the first found flag
-					// pass the arg as a nerdctl command argument
-					nerdctlArgs = append(nerdctlArgs, arg)
-				case (i >= firstOptPos) && !lastOpt:
-					// The first arg after procecssing the flags establishes the last Option Pos
-					lastOpt = true
-					cmdArgs = append(cmdArgs, arg)
-				default:
-					// Unexpected case
-					// pass the arg as a nerdctl arg by default
-					nc.logger.Debugln("Unexpected Arg Value: ", arg)
-					nerdctlArgs = append(nerdctlArgs, arg)
-				}
-			}
-		}
-
-		// to handle environment variables properly, we add all entries found via
-		// env-file includes to the map first and then all command line environment
-		// flags, making sure that command line overrides environment file options,
-		// and that later command line flags override earlier ones
-		envVars := orderedmap.New()
 
-		for _, e := range fileEnvs {
-			evar, eval, _ := strings.Cut(e, "=")
-			envVars.Set(evar, eval)
-		}
-		for _, e := range envs {
-			evar, eval, _ := strings.Cut(e, "=")
-			envVars.Set(evar, eval)
-		}
-
-		var envArgs []string
-		for pair := envVars.Oldest(); pair != nil; pair = pair.Next() {
-			envArgs = append(envArgs, "-e", fmt.Sprintf("%s=%s", pair.Key, pair.Value))
-		}
-
-		nerdctlArgs = append(nerdctlArgs, envArgs...)
-		nerdctlArgs = append(nerdctlArgs, cmdArgs...)
-	default:
-
-		for i, arg := range args {
-			// Check if individual argument (and possibly following value) requires manipulation-in-place handling
-			if hasArgHandler {
-				// Check if argument for the command needs handling, sometimes it can be --file=<filename>
-				b, _, _ := strings.Cut(arg, "=")
-				h, ok := aMap[b]
-				if ok {
-					err = h(nc.systemDeps, nc.fc, args, i)
-					if err != nil {
-						return err
-					}
-					// This is required when the positional argument at i is mutated by argHandler, eg -v=C:\Users:/tmp:ro
-					arg = args[i]
-				}
-			}
-
-			switch arg {
-			case "--debug":
-				nc.logger.SetLevel(flog.Debug)
-			case "--help":
-				nerdctlArgs = append(nerdctlArgs, arg)
-			default:
-				nerdctlArgs = append(nerdctlArgs, arg)
-			}
-		}
+	endTranslation := timing.Start("translation")
+	tc, err := nc.translator().Translate(cmdName, args)
+	endTranslation()
+	if err != nil {
+		return err
 	}
 
 	passedEnvs := []string{
@@ -340,45 +63,20 @@ func (nc *nerdctlCommand) run(cmdName string, args []string) error {
 	}
 
 	var additionalEnv []string
-	switch cmdName {
-	case "image":
-		if slices.Contains(args, "build") || slices.Contains(args, "pull") || slices.Contains(args, "push") {
-			ensureRemoteCredentials(nc.fc, nc.ecc, &additionalEnv, nc.logger)
-		}
-	case "container":
-		if slices.Contains(args, "run") {
-			ensureRemoteCredentials(nc.fc, nc.ecc, &additionalEnv, nc.logger)
-		}
-	case "build", "pull", "push", "container run":
-		ensureRemoteCredentials(nc.fc, nc.ecc, &additionalEnv, nc.logger)
+	if needsRegistryCredentials(tc.Name, tc.Args) {
+		endCredentials := timing.Start("credential export")
+		additionalEnv = nc.ensureRemoteCredentials()
+		endCredentials()
 	}
 
 	// Add -E to sudo command in order to preserve existing environment variables, more info:
 	// https://stackoverflow.com/questions/8633461/how-to-keep-environment-variables-when-using-sudo/8633575#8633575
 	limaArgs := append(nc.GetCmdArgs(), append(additionalEnv, passedEnvArgs...)...)
+	limaArgs = append(limaArgs, tc.Env...)
 
-	limaArgs = append(limaArgs, append([]string{nerdctlCmdName}, strings.Fields(cmdName)...)...)
-
-	// Add -E to sudo command in order to preserve existing environment variables, more info:
-	// https://stackoverflow.com/questions/8633461/how-to-keep-environment-variables-when-using-sudo/8633575#8633575
-	runArgs = append(runArgs, limaArgs...)
-	runArgs = append(runArgs, nerdctlArgs...)
-
-	if nc.shouldReplaceForHelp(cmdName, args) {
-		return nc.ncc.RunWithReplacingStdout([]command.Replacement{{Source: "nerdctl", Target: "finch"}}, runArgs...)
-	}
-
-	if inspectType == "container" && nc.fc.DockerCompat && !slices.Contains(runArgs, "--format") {
-		runArgs = append(runArgs, "--format", "{{json .}}")
-		cmd := nc.ncc.Create(runArgs...)
-		return inspectContainerOutputHandler(cmd)
-	}
-
-	if err := handleDockerCompatComposeVersion(cmdName, *nc, cmdArgs); err == nil {
-		return nil
-	}
+	limaArgs = append(limaArgs, append([]string{nerdctlCmdName}, strings.Fields(tc.Name)...)...)
 
-	return nc.ncc.Create(runArgs...).Run()
+	return nc.execute(tc, append(limaArgs, tc.Args...))
 }
 
 func (nc *nerdctlCommand) assertVMIsRunning(creator command.NerdctlCmdCreator, logger flog.Logger) error {
@@ -399,254 +97,53 @@ func (nc *nerdctlCommand) assertVMIsRunning(creator command.NerdctlCmdCreator, l
 		return fmt.Errorf("instance %q is stopped, run `finch %s start` to start the instance",
 			limaInstanceName, virtualMachineRootCmd)
 	default:
-		return nil
+		return unhealthyVMError(status)
 	}
 }
 
-func argIsEnv(arg string) bool {
-	return strings.HasPrefix(arg, "-e") || (strings.HasPrefix(arg, "--env") && !strings.HasPrefix(arg, "--env-file"))
+// registrySubcommands are the subcommands that may contact a registry, for the commands that have subcommands.
+var registrySubcommands = map[string][]string{
+	"image":     {"build", "pull", "push"},
+	"container": {"run", "create"},
+	"builder":   {"build"},
+	"compose":   {"up", "pull", "push", "build", "run", "create"},
 }
 
-// ensureRemoteCredentials is called before any actions that may require remote resources, in order
-// to ensure that fresh credentials are available inside the VM.
-// For more details on how `aws configure export-credentials` works, checks the docs.
-//
-// [the docs]: https://awscli.amazonaws.com/v2/documentation/api/latest/reference/configure/export-credentials.html
-func ensureRemoteCredentials(
-	fc *config.Finch,
-	ecc command.Creator,
-	outEnv *[]string,
-	logger flog.Logger,
-) {
-	// On macOS, credential helpers run on the host via the credential server,
-	// so we don't need to export static credentials to the VM.
-	if runtime.GOOS == "darwin" {
-		return
+// needsRegistryCredentials returns true if the nerdctl command may pull or push images.
+func needsRegistryCredentials(cmdName string, args []string) bool {
+	switch cmdName {
+	case "build", "pull", "push", "create", "image build", "image pull", "image push", "container run", "container create":
+		return true
 	}
-
-	if slices.Contains(fc.CredsHelpers, "ecr-login") {
-		out, err := ecc.Create(
-			"aws",
-			"configure",
-			"export-credentials",
-			"--format",
-			"process",
-		).CombinedOutput()
-		if err != nil {
-			logger.Debugln("failed to run `aws configure` command")
-			return
+	for _, sub := range registrySubcommands[cmdName] {
+		if slices.Contains(args, sub) {
+			return true
 		}
-
-		var exportCredsOut aws.Credentials
-		err = json.Unmarshal(out, &exportCredsOut)
-		if err != nil {
-			logger.Debugln("`aws configure export-credentials` output is unexpected, is command available? " +
-				"This may result in a broken ecr-credential helper experience.")
-			return
-		}
-
-		*outEnv = append(*outEnv, fmt.Sprintf("AWS_ACCESS_KEY_ID=%s", exportCredsOut.AccessKeyID))
-		*outEnv = append(*outEnv, fmt.Sprintf("AWS_SECRET_ACCESS_KEY=%s", exportCredsOut.SecretAccessKey))
-		*outEnv = append(*outEnv, fmt.Sprintf("AWS_SESSION_TOKEN=%s", exportCredsOut.SessionToken))
 	}
+	return false
 }
 
-func (nc *nerdctlCommand) handleMultipleShortFlags(
-	shortFlagBoolSet sets.Set[string],
-	shortFlagArgSet sets.Set[string],
-	args []string,
-	index int,
-) string {
-	arg := args[index]
-	nextArg := args[index+1]
-
-	argFlagFinal := false
-	argFlagMid := false
-	lastPos := len(arg) - 1
-
-	// Scan the compound shortFlag arg for details
-	for i, c := range strings.Split(arg, "") {
-		switch {
-		case c == "-":
-			continue
-		case i != lastPos && shortFlagArgSet.Has("-"+c):
-			argFlagMid = true
-		case i == lastPos && shortFlagArgSet.Has("-"+c):
-			argFlagFinal = true
-		default:
-			if !shortFlagBoolSet.Has("-" + c) {
-				nc.logger.Debugf("The group of short flags contains an unexpected value: %s, %s", arg, c)
-			}
-		}
-	}
-
-	switch {
-	case argFlagMid:
-		// if a flag in the middle requires arguments,
-		//    but there are more short flags immediately following
-		//    then include a finch debug comment
-		//    and pass the concatenated arg to nerdctl
-		nc.logger.Debugln("Mid Position Short Flag requires an Arg: ", arg)
-	case argFlagFinal:
-		// pre-pend the shortFlagArg member to the next Arg
-		separatedShortFlag := "-" + arg[lastPos:]
-		args[index+1] = separatedShortFlag + nextArg
-		// remove the shortFlagArg member from the current Arg
-		args[index] = arg[:lastPos]
-		arg = args[index]
-	default:
-		// if every following character is a shortFlagBool
-		//    then pass into nerdctlArgs
-	}
-
-	return arg
-}
-
-func (nc *nerdctlCommand) handleFlagArg(arg string, nextArg string) (bool, string, string) {
-	// handling Flag arguments other than environment variables
-	//    note: a Bool Flag should not be passed into this helper function; only Flags that are followed by one argument
-	var (
-		flagKey, flagVal string
-		skip             bool
-	)
-	switch {
-	case strings.HasPrefix(arg, "--") && strings.Contains(arg, "="):
-		// long flag concatenated to value by '=': --<long_flag>="<value>"
-		skip = false
-		flagKey, flagVal, _ = strings.Cut(arg, "=")
-	case strings.HasPrefix(arg, "--") && isNumericArg(nextArg),
-		strings.HasPrefix(arg, "--") && !strings.HasPrefix(nextArg, "-"):
-		// long flag followed by a value (including a negative number): --<long_flag> "<value>".
-		// the isNumeric check is needed because the value can be a negative number.
-		// for example, in our health check tests where we pass --health-retries -5 or --health-timeout -5s
-		skip = true
-		flagKey = arg
-		flagVal = nextArg
-	// undefined case: long flag adjacent to value: --<long_flag>"<value>" or --<long_flag><value>
-	case strings.HasPrefix(arg, "-") && strings.Contains(arg, "="):
-		// short flag concatenated to value by '=': -?="<value>" or -?=<value>
-		skip = false
-		flagKey, flagVal, _ = strings.Cut(arg, "=")
-	case strings.HasPrefix(arg, "-") && len(arg) > 2:
-		// short flag adjacent to value: -?"<value>" or -?<value>
-		skip = false
-		flagKey = arg[:2]
-		flagVal = arg[2:]
-	case strings.HasPrefix(arg, "-") && len(arg) == 2 && isNumericArg(nextArg),
-		strings.HasPrefix(arg, "-") && len(arg) == 2 && !strings.HasPrefix(nextArg, "-"):
-		// short flag followed by a value (including a negative number): -? "<value>" or -? <value>
-		skip = true
-		flagKey = arg
-		flagVal = nextArg
-	default:
-		return false, "", ""
-	}
-
-	return skip, flagKey, flagVal
-}
-
-func handleEnv(systemDeps NerdctlCommandSystemDeps, arg, arg2 string) (bool, string) {
-	var (
-		envVar string
-		skip   bool
-	)
-	switch arg {
-	case "-e", "--env":
-		skip = true
-		envVar = arg2
-	default:
-		// flag and value are in the same string
-		if strings.HasPrefix(arg, "-e") {
-			envVar = arg[2:]
-		} else {
-			// only other case is "--env="; skip that prefix
-			eqPos := strings.Index(arg, "=")
-			envVar = arg[eqPos+1:]
-		}
-	}
-
-	if strings.Contains(envVar, "=") {
-		return skip, envVar
-	}
-	// if no value was provided we need to check the OS environment
-	// for a value and only set if it exists in the current env
-	if val, ok := systemDeps.LookupEnv(envVar); ok {
-		return skip, fmt.Sprintf("%s=%s", envVar, val)
+// ensureRemoteCredentials is called before any actions that may require remote resources, in order
+// to ensure that fresh credentials are available to the ecr-login credential helper inside the VM.
+// It returns the environment variables that pass the credentials to nerdctl.
+func (nc *nerdctlCommand) ensureRemoteCredentials() []string {
+	// On macOS, credential helpers run on the host via the credential server,
+	// so we don't need to export static credentials to the VM.
+	if runtime.GOOS == "darwin" || !slices.Contains(nc.fc.CredsHelpers, "ecr-login") {
+		return nil
 	}
-	// no value found; do not set the variable in the env
-	return skip, ""
-}
-
-func handleEnvFile(fs afero.Fs, systemDeps NerdctlCommandSystemDeps, arg, arg2 string) (bool, []string, error) {
-	var (
-		filename string
-		skip     bool
-	)
 
-	switch arg {
-	case "--env-file":
-		skip = true
-		filename = arg2
-	default:
-		filename = arg[11:]
+	var cachePath string
+	if home, err := nc.systemDeps.GetUserHome(); err == nil {
+		cachePath = filepath.Join(home, ".finch", "aws-credentials-cache.json")
+	} else {
+		nc.logger.Debugf("Failed to get user home directory, AWS credentials will not be cached: %v", err)
 	}
-
-	file, err := fs.Open(filepath.Clean(filename))
+	resolver := awscreds.NewResolver(awsconfig.LoadDefaultConfig, nc.fs, cachePath, nc.systemDeps, nc.logger)
+	env, err := resolver.Env(context.Background())
 	if err != nil {
-		return false, []string{}, err
-	}
-	defer file.Close() //nolint:errcheck // We did not write to the file, and the file will be closed when the CLI process exits anyway.
-
-	scanner := bufio.NewScanner(file)
-
-	var envs []string
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if len(line) == 0 {
-			continue
-		}
-		switch {
-		case strings.HasPrefix(line, "#"):
-			// ignore comments
-			continue
-		case !strings.Contains(line, "="):
-			// only has the variable name; need to lookup value
-			if val, ok := systemDeps.LookupEnv(line); ok {
-				envs = append(envs, fmt.Sprintf("%s=%s", line, val))
-			}
-		default:
-			// contains a name and value
-			envs = append(envs, line)
-		}
-	}
-	if err := scanner.Err(); err != nil {
-		return skip, []string{}, err
-	}
-	return skip, envs, nil
-}
-
-// isNumericArg returns whether the passed argument is a numeric argument or not.
-// For example, it returns true for cases like 5, -5, 5s and -5s.
-func isNumericArg(arg string) bool {
-	if arg == "" {
-		return false
-	}
-	// handle the case where the arg is a negative number followed by a char
-	// for example: --health-timeout -5s
-	if arg[0] == '-' && len(arg) > 1 {
-		for i := 1; i < len(arg); i++ {
-			if arg[i] < '0' || arg[i] > '9' {
-				return i > 1
-			}
-		}
-		return true
-	}
-	// handle the case where the arg is a positive number followed by a char
-	// for example: --health-timeout -5s
-	for i := 0; i < len(arg); i++ {
-		if arg[i] < '0' || arg[i] > '9' {
-			return i > 0
-		}
+		nc.logger.Warnf("Failed to resolve AWS credentials, the ecr-login credential helper may fail to authenticate: %v", err)
+		return nil
 	}
-	return true
+	return env
 }
@@ -76,11 +76,12 @@ func TestNerdctlCommand_withVMErrors(t *testing.T) {
 			},
 		},
 		{
-			name:    "unknown VM status",
+			name:    "broken VM",
 			cmdName: "build",
 			fc:      &config.Finch{},
 			args:    []string{"-t", "demo", "."},
-			wantErr: errors.New("unrecognized system status"),
+			wantErr: errors.New("the instance \"finch\" is broken, run `finch vm status` to see why, " +
+				"then `finch vm stop --force` to stop it, or `finch vm remove --force` to remove it"),
 			mockSvc: func(
 				_ *testing.T,
 				ncc *mocks.NerdctlCmdCreator,
@@ -96,6 +97,27 @@ func TestNerdctlCommand_withVMErrors(t *testing.T) {
 				logger.EXPECT().Debugf("Status of virtual machine: %s", "Broken")
 			},
 		},
+		{
+			name:    "unknown VM status",
+			cmdName: "build",
+			fc:      &config.Finch{},
+			args:    []string{"-t", "demo", "."},
+			wantErr: errors.New("unrecognized system status"),
+			mockSvc: func(
+				_ *testing.T,
+				ncc *mocks.NerdctlCmdCreator,
+				_ *mocks.CommandCreator,
+				_ *mocks.NerdctlCommandSystemDeps,
+				logger *mocks.Logger,
+				ctrl *gomock.Controller,
+				_ afero.Fs,
+			) {
+				getVMStatusC := mocks.NewCommand(ctrl)
+				ncc.EXPECT().CreateWithoutStdio("ls", "-f", "{{.Status}}", limaInstanceName).Return(getVMStatusC)
+				getVMStatusC.EXPECT().Output().Return([]byte("Suspended"), nil)
+				logger.EXPECT().Debugf("Status of virtual machine: %s", "Suspended")
+			},
+		},
 		{
 			name:    "status command returns an error",
 			cmdName: "build",
@@ -128,181 +150,31 @@ func TestNerdctlCommand_withVMErrors(t *testing.T) {
 			logger := mocks.NewLogger(ctrl)
 			fs := afero.NewMemMapFs()
 			tc.mockSvc(t, ncc, ecc, ncsd, logger, ctrl, fs)
-			assert.Equal(t, tc.wantErr, newNerdctlCommand(ncc, ecc, ncsd, logger, fs, tc.fc).run(tc.cmdName, tc.args))
+			assert.Equal(t, tc.wantErr, newNerdctlCommand(ncc, ecc, ncsd, logger, fs, tc.fc, mockFinchPath).run(tc.cmdName, tc.args))
 		})
 	}
 }
 
-func TestHandleFlagArg(t *testing.T) {
+func TestNeedsRegistryCredentials(t *testing.T) {
 	t.Parallel()
-	testCases := []struct {
-		inputArgs    []string
-		expectedArgs []string
-	}{
-		{
-			inputArgs:    []string{"--flag", "value"},
-			expectedArgs: []string{"--flag", "value"},
-		},
-		{
-			inputArgs:    []string{"--flag=value", ""},
-			expectedArgs: []string{"--flag", "value"},
-		},
-		{
-			inputArgs:    []string{"--flag=\"value\"", ""},
-			expectedArgs: []string{"--flag", "\"value\""},
-		},
-		{
-			inputArgs:    []string{"--flag", "10"},
-			expectedArgs: []string{"--flag", "10"},
-		},
-		{
-			inputArgs:    []string{"--flag", "-10"},
-			expectedArgs: []string{"--flag", "-10"},
-		},
-		{
-			inputArgs:    []string{"--flag", "10s"},
-			expectedArgs: []string{"--flag", "10s"},
-		},
-		{
-			inputArgs:    []string{"--flag", "-10s"},
-			expectedArgs: []string{"--flag", "-10s"},
-		},
-		{
-			inputArgs:    []string{"--flag=10", ""},
-			expectedArgs: []string{"--flag", "10"},
-		},
-		{
-			inputArgs:    []string{"--flag=10s", ""},
-			expectedArgs: []string{"--flag", "10s"},
-		},
-		{
-			inputArgs:    []string{"--flag=-10", ""},
-			expectedArgs: []string{"--flag", "-10"},
-		},
-		{
-			inputArgs:    []string{"--flag=-10s", ""},
-			expectedArgs: []string{"--flag", "-10s"},
-		},
-		{
-			inputArgs:    []string{"--flag=\"10\"", ""},
-			expectedArgs: []string{"--flag", "\"10\""},
-		},
-		{
-			inputArgs:    []string{"--flag=\"-10\"", ""},
-			expectedArgs: []string{"--flag", "\"-10\""},
-		},
-		{
-			inputArgs:    []string{"--flag=\"10s\"", ""},
-			expectedArgs: []string{"--flag", "\"10s\""},
-		},
-		{
-			inputArgs:    []string{"--flag=\"-10s\"", ""},
-			expectedArgs: []string{"--flag", "\"-10s\""},
-		},
-		{
-			inputArgs:    []string{"-f", "value"},
-			expectedArgs: []string{"-f", "value"},
-		},
-		{
-			inputArgs:    []string{"-f=value", ""},
-			expectedArgs: []string{"-f", "value"},
-		},
-		{
-			inputArgs:    []string{"-f=\"value\"", ""},
-			expectedArgs: []string{"-f", "\"value\""},
-		},
-		{
-			inputArgs:    []string{"-f", "10s"},
-			expectedArgs: []string{"-f", "10s"},
-		},
-		{
-			inputArgs:    []string{"-f", "-10s"},
-			expectedArgs: []string{"-f", "-10s"},
-		},
-		{
-			inputArgs:    []string{"-f=10", ""},
-			expectedArgs: []string{"-f", "10"},
-		},
-		{
-			inputArgs:    []string{"-f=10s", ""},
-			expectedArgs: []string{"-f", "10s"},
-		},
-		{
-			inputArgs:    []string{"-f=-10", ""},
-			expectedArgs: []string{"-f", "-10"},
-		},
-		{
-			inputArgs:    []string{"-f=-10s", ""},
-			expectedArgs: []string{"-f", "-10s"},
-		},
-		{
-			inputArgs:    []string{"-f=\"10\"", ""},
-			expectedArgs: []string{"-f", "\"10\""},
-		},
-		{
-			inputArgs:    []string{"-f=\"10s\"", ""},
-			expectedArgs: []string{"-f", "\"10s\""},
-		},
-		{
-			inputArgs:    []string{"-f=\"-10\"", ""},
-			expectedArgs: []string{"-f", "\"-10\""},
-		},
-		{
-			inputArgs:    []string{"-f=\"-10s\"", ""},
-			expectedArgs: []string{"-f", "\"-10s\""},
-		},
-		{
-			inputArgs:    []string{"-f10", ""},
-			expectedArgs: []string{"-f", "10"},
-		},
-	}
-	for _, tc := range testCases {
-		ctrl := gomock.NewController(t)
-		ecc := mocks.NewCommandCreator(ctrl)
-		ncc := mocks.NewNerdctlCmdCreator(ctrl)
-		ncsd := mocks.NewNerdctlCommandSystemDeps(ctrl)
-		logger := mocks.NewLogger(ctrl)
-		fs := afero.NewMemMapFs()
-		nc := newNerdctlCommand(ncc, ecc, ncsd, logger, fs, nil)
-		// the first return value is never used
-		_, flagKey, flagVal := nc.handleFlagArg(tc.inputArgs[0], tc.inputArgs[1])
-		assert.Equal(t, flagKey, tc.expectedArgs[0])
-		assert.Equal(t, flagVal, tc.expectedArgs[1])
-	}
-}
 
-func TestIsNumericArg(t *testing.T) {
-	t.Parallel()
 	testCases := []struct {
-		arg      string
-		expected bool
+		cmdName string
+		args    []string
+		want    bool
 	}{
-		{
-			arg:      "5",
-			expected: true,
-		},
-		{
-			arg:      "5s",
-			expected: true,
-		},
-		{
-			arg:      "-5",
-			expected: true,
-		},
-		{
-			arg:      "-5s",
-			expected: true,
-		},
-		{
-			arg:      "abc",
-			expected: false,
-		},
-		{
-			arg:      "-abc",
-			expected: false,
-		},
+		{cmdName: "pull", args: []string{"alpine"}, want: true},
+		{cmdName: "image build", args: []string{"."}, want: true},
+		{cmdName: "container run", args: []string{"alpine"}, want: true},
+		{cmdName: "image", args: []string{"push", "alpine"}, want: true},
+		{cmdName: "compose", args: []string{"-f", "compose.yaml", "up"}, want: true},
+		{cmdName: "image save", args: []string{"alpine"}, want: false},
+		{cmdName: "image", args: []string{"ls"}, want: false},
+		{cmdName: "compose", args: []string{"down"}, want: false},
+		{cmdName: "ps", args: []string{"-a"}, want: false},
 	}
+
 	for _, tc := range testCases {
-		assert.Equal(t, tc.expected, isNumericArg(tc.arg))
+		assert.Equal(t, tc.want, needsRegistryCredentials(tc.cmdName, tc.args), "%s %v", tc.cmdName, tc.args)
 	}
 }
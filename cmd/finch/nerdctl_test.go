@@ -12,8 +12,11 @@ import (
 	"github.com/runfinch/finch/pkg/command"
 	"github.com/runfinch/finch/pkg/config"
 	"github.com/runfinch/finch/pkg/mocks"
+	"github.com/runfinch/finch/pkg/path"
 )
 
+const mockFinchPath path.Finch = "/finch"
+
 var testStdoutRs = []command.Replacement{
 	{Source: "nerdctl", Target: "finch"},
 }
@@ -21,7 +24,7 @@ var testStdoutRs = []command.Replacement{
 func TestNerdctlCommandCreator_create(t *testing.T) {
 	t.Parallel()
 
-	cmd := newNerdctlCommandCreator(nil, nil, nil, nil, nil, nil).create("build", "build description")
+	cmd := newNerdctlCommandCreator(nil, nil, nil, nil, nil, nil, mockFinchPath).create("build", "build description")
 	assert.Equal(t, cmd.Name(), "build")
 	assert.Equal(t, cmd.DisableFlagParsing, true)
 }
@@ -113,7 +116,7 @@ func TestNerdctlCommand_shouldReplaceForHelp(t *testing.T) {
 			ncsd := mocks.NewNerdctlCommandSystemDeps(ctrl)
 			logger := mocks.NewLogger(ctrl)
 			assert.True(t, (newNerdctlCommand(ncc, ecc, ncsd, logger,
-				nil, &config.Finch{}).shouldReplaceForHelp(tc.cmdName, tc.args) == tc.expected))
+				nil, &config.Finch{}, mockFinchPath).shouldReplaceForHelp(tc.cmdName, tc.args) == tc.expected))
 		})
 	}
 }
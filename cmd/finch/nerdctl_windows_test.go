@@ -54,8 +54,6 @@ func TestNerdctlCommand_runAdaptor(t *testing.T) {
 				AddEmptyEnvLookUps(ncsd)
 				ncsd.EXPECT().GetWd().Return("C:\\workdir", nil)
 				ncsd.EXPECT().FilePathAbs("C:\\workdir").Return("C:\\workdir", nil)
-				ncsd.EXPECT().FilePathJoin(string(filepath.Separator), "mnt", "c", "workdir").Return(augmentedPath)
-				ncsd.EXPECT().FilePathToSlash(augmentedPath).Return(wslPath)
 				c := mocks.NewCommand(ctrl)
 				ncc.EXPECT().Create("shell", "--workdir", wslPath, limaInstanceName, "sudo", "-E", nerdctlCmdName, "info").Return(c)
 				c.EXPECT().Run()
@@ -74,7 +72,7 @@ func TestNerdctlCommand_runAdaptor(t *testing.T) {
 			logger := mocks.NewLogger(ctrl)
 			tc.mockSvc(ecc, ncc, logger, ctrl, ncsd)
 
-			assert.NoError(t, newNerdctlCommand(ncc, ecc, ncsd, logger, nil, &config.Finch{}).runAdapter(tc.cmd, tc.args))
+			assert.NoError(t, newNerdctlCommand(ncc, ecc, ncsd, logger, nil, &config.Finch{}, mockFinchPath).runAdapter(tc.cmd, tc.args))
 		})
 	}
 }
@@ -115,10 +113,6 @@ func TestNerdctlCommand_run(t *testing.T) {
 				ncsd.EXPECT().GetWd().Return("C:\\workdir", nil)
 				ncsd.EXPECT().FilePathAbs("C:\\workdir").Return("C:\\workdir", nil)
 				ncsd.EXPECT().FilePathAbs("C:\\Users").Return("C:\\Users", nil)
-				ncsd.EXPECT().FilePathJoin(string(filepath.Separator), "mnt", "c", "workdir").Return(augmentedPath)
-				ncsd.EXPECT().FilePathJoin(string(filepath.Separator), "mnt", "c", "Users").Return("\\mnt\\c\\Users")
-				ncsd.EXPECT().FilePathToSlash(augmentedPath).Return(wslPath)
-				ncsd.EXPECT().FilePathToSlash("\\mnt\\c\\Users").Return("/mnt/c/Users")
 
 				c := mocks.NewCommand(ctrl)
 				// alias substitution, build => image build
@@ -151,8 +145,6 @@ func TestNerdctlCommand_run(t *testing.T) {
 				logger.EXPECT().SetLevel(flog.Debug)
 				ncsd.EXPECT().GetWd().Return("C:\\workdir", nil)
 				ncsd.EXPECT().FilePathAbs("C:\\workdir").Return("C:\\workdir", nil)
-				ncsd.EXPECT().FilePathJoin(string(filepath.Separator), "mnt", "c", "workdir").Return(augmentedPath)
-				ncsd.EXPECT().FilePathToSlash(augmentedPath).Return(wslPath)
 
 				c := mocks.NewCommand(ctrl)
 				ncc.EXPECT().Create("shell", "--workdir", wslPath, limaInstanceName, "sudo", "-E", nerdctlCmdName, "pull", "test:tag").Return(c)
@@ -186,8 +178,6 @@ func TestNerdctlCommand_run(t *testing.T) {
 				ncsd.EXPECT().LookupEnv("ARG3")
 				ncsd.EXPECT().GetWd().Return("C:\\workdir", nil)
 				ncsd.EXPECT().FilePathAbs("C:\\workdir").Return("C:\\workdir", nil)
-				ncsd.EXPECT().FilePathJoin(string(filepath.Separator), "mnt", "c", "workdir").Return(augmentedPath)
-				ncsd.EXPECT().FilePathToSlash(augmentedPath).Return(wslPath)
 
 				ncc.EXPECT().Create("shell", "--workdir", wslPath, limaInstanceName, "sudo", "-E", nerdctlCmdName, "container", "run",
 					"-it", "--rm", "-e", "ARG0=val0", "alpine:latest", "env").Return(c)
@@ -220,8 +210,6 @@ func TestNerdctlCommand_run(t *testing.T) {
 				ncsd.EXPECT().LookupEnv("ARG3").Return("val3", true)
 				ncsd.EXPECT().GetWd().Return("C:\\workdir", nil)
 				ncsd.EXPECT().FilePathAbs("C:\\workdir").Return("C:\\workdir", nil)
-				ncsd.EXPECT().FilePathJoin(string(filepath.Separator), "mnt", "c", "workdir").Return(augmentedPath)
-				ncsd.EXPECT().FilePathToSlash(augmentedPath).Return(wslPath)
 				// alias substitution run=>container run
 				ncc.EXPECT().Create("shell", "--workdir", wslPath, limaInstanceName, "sudo", "-E", nerdctlCmdName, "container", "run",
 					"--rm", "-e", "ARG3=val3", "alpine:latest", "env").Return(c)
@@ -255,8 +243,6 @@ func TestNerdctlCommand_run(t *testing.T) {
 				ncsd.EXPECT().LookupEnv("ARG3").Return("val3", true)
 				ncsd.EXPECT().GetWd().Return("C:\\workdir", nil)
 				ncsd.EXPECT().FilePathAbs("C:\\workdir").Return("C:\\workdir", nil)
-				ncsd.EXPECT().FilePathJoin(string(filepath.Separator), "mnt", "c", "workdir").Return(augmentedPath)
-				ncsd.EXPECT().FilePathToSlash(augmentedPath).Return(wslPath)
 				// alias substitution run=>container run
 				ncc.EXPECT().Create("shell", "--workdir", wslPath, limaInstanceName, "sudo", "-E", nerdctlCmdName, "container", "run",
 					"--rm", "-e", "ARG3=val3", "alpine:latest", "env").Return(c)
@@ -292,8 +278,6 @@ func TestNerdctlCommand_run(t *testing.T) {
 				ncsd.EXPECT().LookupEnv("NOTSETARG")
 				ncsd.EXPECT().GetWd().Return("C:\\workdir", nil)
 				ncsd.EXPECT().FilePathAbs("C:\\workdir").Return("C:\\workdir", nil)
-				ncsd.EXPECT().FilePathJoin(string(filepath.Separator), "mnt", "c", "workdir").Return(augmentedPath)
-				ncsd.EXPECT().FilePathToSlash(augmentedPath).Return(wslPath)
 				ncc.EXPECT().Create("shell", "--workdir", wslPath, limaInstanceName,
 					"sudo", "-E", nerdctlCmdName, "container", "run", "--rm", "-e", "ARG1=val1", "alpine:latest", "env").Return(c)
 				c.EXPECT().Run()
@@ -329,8 +313,6 @@ func TestNerdctlCommand_run(t *testing.T) {
 				ncsd.EXPECT().LookupEnv("NOTSETARG")
 				ncsd.EXPECT().GetWd().Return("C:\\workdir", nil)
 				ncsd.EXPECT().FilePathAbs("C:\\workdir").Return("C:\\workdir", nil)
-				ncsd.EXPECT().FilePathJoin(string(filepath.Separator), "mnt", "c", "workdir").Return(augmentedPath)
-				ncsd.EXPECT().FilePathToSlash(augmentedPath).Return(wslPath)
 				ncc.EXPECT().Create("shell", "--workdir", wslPath, limaInstanceName,
 					"sudo", "-E", nerdctlCmdName, "container", "run", "--rm", "-e", "ARG1=val1", "alpine:latest", "env").Return(c)
 				c.EXPECT().Run()
@@ -365,8 +347,6 @@ func TestNerdctlCommand_run(t *testing.T) {
 				ncsd.EXPECT().LookupEnv("NOTSETARG")
 				ncsd.EXPECT().GetWd().Return("C:\\workdir", nil)
 				ncsd.EXPECT().FilePathAbs("C:\\workdir").Return("C:\\workdir", nil)
-				ncsd.EXPECT().FilePathJoin(string(filepath.Separator), "mnt", "c", "workdir").Return(augmentedPath)
-				ncsd.EXPECT().FilePathToSlash(augmentedPath).Return(wslPath)
 				ncc.EXPECT().Create("shell", "--workdir", wslPath, limaInstanceName,
 					"sudo", "-E", nerdctlCmdName, "container", "run", "--rm", "-e", "ARG2=val2", "alpine:latest", "env").Return(c)
 				c.EXPECT().Run()
@@ -420,8 +400,6 @@ func TestNerdctlCommand_run(t *testing.T) {
 				logger.EXPECT().Debugf(`Resolving special IP "host-gateway" to %q for host %q`, "192.168.5.2", "name")
 				ncsd.EXPECT().GetWd().Return("C:\\workdir", nil)
 				ncsd.EXPECT().FilePathAbs("C:\\workdir").Return("C:\\workdir", nil)
-				ncsd.EXPECT().FilePathJoin(string(filepath.Separator), "mnt", "c", "workdir").Return(augmentedPath)
-				ncsd.EXPECT().FilePathToSlash(augmentedPath).Return(wslPath)
 
 				c := mocks.NewCommand(ctrl)
 				ncc.EXPECT().Create("shell", "--workdir", wslPath, limaInstanceName, "sudo", "-E", nerdctlCmdName, "container", "run",
@@ -452,8 +430,6 @@ func TestNerdctlCommand_run(t *testing.T) {
 				AddEmptyEnvLookUps(ncsd)
 				ncsd.EXPECT().GetWd().Return("C:\\workdir", nil)
 				ncsd.EXPECT().FilePathAbs("C:\\workdir").Return("C:\\workdir", nil)
-				ncsd.EXPECT().FilePathJoin(string(filepath.Separator), "mnt", "c", "workdir").Return(augmentedPath)
-				ncsd.EXPECT().FilePathToSlash(augmentedPath).Return(wslPath)
 				c := mocks.NewCommand(ctrl)
 				ncc.EXPECT().Create("shell", "--workdir", wslPath, limaInstanceName, "sudo", "-E", nerdctlCmdName, "container", "run",
 					"--rm", "--add-host", "name:0.0.0.0", "alpine:latest").Return(c)
@@ -483,8 +459,6 @@ func TestNerdctlCommand_run(t *testing.T) {
 				AddEmptyEnvLookUps(ncsd)
 				ncsd.EXPECT().GetWd().Return("C:\\workdir", nil)
 				ncsd.EXPECT().FilePathAbs("C:\\workdir").Return("C:\\workdir", nil)
-				ncsd.EXPECT().FilePathJoin(string(filepath.Separator), "mnt", "c", "workdir").Return(augmentedPath)
-				ncsd.EXPECT().FilePathToSlash(augmentedPath).Return(wslPath)
 				c := mocks.NewCommand(ctrl)
 				ncc.EXPECT().Create("shell", "--workdir", wslPath, limaInstanceName, "sudo", "-E", nerdctlCmdName, "container", "run",
 					"--rm", "--add-host", "alpine:latest").Return(c)
@@ -517,8 +491,6 @@ func TestNerdctlCommand_run(t *testing.T) {
 				logger.EXPECT().Debugf(`Resolving special IP "host-gateway" to %q for host %q`, "192.168.5.2", "name")
 				ncsd.EXPECT().GetWd().Return("C:\\workdir", nil)
 				ncsd.EXPECT().FilePathAbs("C:\\workdir").Return("C:\\workdir", nil)
-				ncsd.EXPECT().FilePathJoin(string(filepath.Separator), "mnt", "c", "workdir").Return(augmentedPath)
-				ncsd.EXPECT().FilePathToSlash(augmentedPath).Return(wslPath)
 				c := mocks.NewCommand(ctrl)
 				ncc.EXPECT().Create("shell", "--workdir", wslPath, limaInstanceName, "sudo", "-E", nerdctlCmdName, "container", "run",
 					"--rm", "--add-host=name:192.168.5.2", "alpine:latest").Return(c)
@@ -548,8 +520,6 @@ func TestNerdctlCommand_run(t *testing.T) {
 				AddEmptyEnvLookUps(ncsd)
 				ncsd.EXPECT().GetWd().Return("C:\\workdir", nil)
 				ncsd.EXPECT().FilePathAbs("C:\\workdir").Return("C:\\workdir", nil)
-				ncsd.EXPECT().FilePathJoin(string(filepath.Separator), "mnt", "c", "workdir").Return(augmentedPath)
-				ncsd.EXPECT().FilePathToSlash(augmentedPath).Return(wslPath)
 				c := mocks.NewCommand(ctrl)
 				ncc.EXPECT().Create("shell", "--workdir", wslPath, limaInstanceName, "sudo", "-E", nerdctlCmdName, "container", "run",
 					"--rm", "--add-host=name:0.0.0.0", "alpine:latest").Return(c)
@@ -581,9 +551,7 @@ func TestNerdctlCommand_run(t *testing.T) {
 				logger.EXPECT().Debugf("Status of virtual machine: %s", "Running")
 				AddEmptyEnvLookUps(ncsd)
 				ncsd.EXPECT().GetWd().Return("C:\\workdir", nil).Times(1)
-				ncsd.EXPECT().FilePathAbs("C:\\workdir").Return("C:\\workdir", nil).Times(5)
-				ncsd.EXPECT().FilePathJoin(string(filepath.Separator), "mnt", "c", "workdir").Return(augmentedPath).Times(3)
-				ncsd.EXPECT().FilePathToSlash(augmentedPath).Return(wslPath).Times(3)
+				ncsd.EXPECT().FilePathAbs("C:\\workdir").Return("C:\\workdir", nil).Times(3)
 				c := mocks.NewCommand(ctrl)
 				ncc.EXPECT().Create("shell", "--workdir", wslPath, limaInstanceName, "sudo", "-E", nerdctlCmdName, "container", "run",
 					"--rm", "-v", "/mnt/c/workdir:/tmp1/tmp2:rro", "-v", "/mnt/c/workdir:/tmp1/tmp2/tmp3/tmp4:rro",
@@ -614,8 +582,6 @@ func TestNerdctlCommand_run(t *testing.T) {
 				AddEmptyEnvLookUps(ncsd)
 				ncsd.EXPECT().GetWd().Return("C:\\workdir", nil)
 				ncsd.EXPECT().FilePathAbs("C:\\workdir").Return("C:\\workdir", nil)
-				ncsd.EXPECT().FilePathJoin(string(filepath.Separator), "mnt", "c", "workdir").Return(augmentedPath)
-				ncsd.EXPECT().FilePathToSlash(augmentedPath).Return(wslPath)
 				ncc.EXPECT().RunWithReplacingStdout(
 					testStdoutRs, "shell", "--workdir", wslPath, limaInstanceName,
 					"sudo", "-E", nerdctlCmdName, "pull", "test:tag", "--help").Return(nil)
@@ -644,8 +610,6 @@ func TestNerdctlCommand_run(t *testing.T) {
 				AddEmptyEnvLookUps(ncsd)
 				ncsd.EXPECT().GetWd().Return("C:\\workdir", nil)
 				ncsd.EXPECT().FilePathAbs("C:\\workdir").Return("C:\\workdir", nil)
-				ncsd.EXPECT().FilePathJoin(string(filepath.Separator), "mnt", "c", "workdir").Return(augmentedPath)
-				ncsd.EXPECT().FilePathToSlash(augmentedPath).Return(wslPath)
 				ncc.EXPECT().RunWithReplacingStdout(
 					testStdoutRs, "shell", "--workdir", wslPath, limaInstanceName, "sudo", "-E", nerdctlCmdName, "pull", "test:tag", "--help").
 					Return(fmt.Errorf("failed to replace"))
@@ -683,8 +647,6 @@ func TestNerdctlCommand_run(t *testing.T) {
 				ncsd.EXPECT().LookupEnv("AWS_PROFILE").Return("", false)
 				ncsd.EXPECT().GetWd().Return("C:\\workdir", nil)
 				ncsd.EXPECT().FilePathAbs("C:\\workdir").Return("C:\\workdir", nil)
-				ncsd.EXPECT().FilePathJoin(string(filepath.Separator), "mnt", "c", "workdir").Return(augmentedPath)
-				ncsd.EXPECT().FilePathToSlash(augmentedPath).Return(wslPath)
 				c := mocks.NewCommand(ctrl)
 				ncc.EXPECT().Create("shell", "--workdir", wslPath, limaInstanceName, "sudo", "-E", "COSIGN_PASSWORD=test", nerdctlCmdName,
 					"push", "--sign=cosign", "test:tag").Return(c)
@@ -723,8 +685,6 @@ func TestNerdctlCommand_run(t *testing.T) {
 				ncsd.EXPECT().LookupEnv("AWS_PROFILE").Return("", false)
 				ncsd.EXPECT().GetWd().Return("C:\\workdir", nil)
 				ncsd.EXPECT().FilePathAbs("C:\\workdir").Return("C:\\workdir", nil)
-				ncsd.EXPECT().FilePathJoin(string(filepath.Separator), "mnt", "c", "workdir").Return(augmentedPath)
-				ncsd.EXPECT().FilePathToSlash(augmentedPath).Return(wslPath)
 				c := mocks.NewCommand(ctrl)
 				ncc.EXPECT().Create("shell", "--workdir", wslPath, limaInstanceName, "sudo", "-E", "COSIGN_PASSWORD=test", nerdctlCmdName,
 					"pull", "--verify=cosign", "test:tag").Return(c)
@@ -763,8 +723,6 @@ func TestNerdctlCommand_run(t *testing.T) {
 				ncsd.EXPECT().LookupEnv("AWS_PROFILE").Return("", false)
 				ncsd.EXPECT().GetWd().Return("C:\\workdir", nil)
 				ncsd.EXPECT().FilePathAbs("C:\\workdir").Return("C:\\workdir", nil)
-				ncsd.EXPECT().FilePathJoin(string(filepath.Separator), "mnt", "c", "workdir").Return(augmentedPath)
-				ncsd.EXPECT().FilePathToSlash(augmentedPath).Return(wslPath)
 				c := mocks.NewCommand(ctrl)
 				ncc.EXPECT().Create("shell", "--workdir", wslPath, limaInstanceName, "sudo", "-E", "COSIGN_PASSWORD=test",
 					nerdctlCmdName, "pull", "test:tag").Return(c)
@@ -800,8 +758,6 @@ func TestNerdctlCommand_run(t *testing.T) {
 				AddEmptyEnvLookUps(ncsd)
 				ncsd.EXPECT().GetWd().Return("C:\\workdir", nil)
 				ncsd.EXPECT().FilePathAbs("C:\\workdir").Return("C:\\workdir", nil)
-				ncsd.EXPECT().FilePathJoin(string(filepath.Separator), "mnt", "c", "workdir").Return(augmentedPath)
-				ncsd.EXPECT().FilePathToSlash(augmentedPath).Return(wslPath)
 				c := mocks.NewCommand(ctrl)
 				ncc.EXPECT().Create("shell", "--workdir", wslPath, limaInstanceName, "sudo", "-E", nerdctlCmdName, "container", "run",
 					"-p", "8080:8080", "--name", "myContainer", "--interactive=true", "--detach", "--rm=true",
@@ -825,7 +781,7 @@ func TestNerdctlCommand_run(t *testing.T) {
 			logger := mocks.NewLogger(ctrl)
 			fs := afero.NewMemMapFs()
 			tc.mockSvc(t, ecc, ncc, cmd, ncsd, logger, ctrl, fs)
-			assert.Equal(t, tc.wantErr, newNerdctlCommand(ncc, ecc, ncsd, logger, fs, tc.fc).run(tc.cmdName, tc.args))
+			assert.Equal(t, tc.wantErr, newNerdctlCommand(ncc, ecc, ncsd, logger, fs, tc.fc, mockFinchPath).run(tc.cmdName, tc.args))
 		})
 	}
 }
@@ -881,8 +837,6 @@ func TestNerdctlCommand_Run_withBindMounts(t *testing.T) {
 				AddEmptyEnvLookUps(ncsd)
 				ncsd.EXPECT().GetWd().Return("C:\\workdir", nil)
 				ncsd.EXPECT().FilePathAbs("C:\\workdir").Return("C:\\workdir", nil).Times(2)
-				ncsd.EXPECT().FilePathJoin(string(filepath.Separator), "mnt", "c", "workdir").Return(augmentedPath).Times(2)
-				ncsd.EXPECT().FilePathToSlash(augmentedPath).Return(wslPath).Times(2)
 
 				c := mocks.NewCommand(ctrl)
 				// alias substitution, run => container run
@@ -913,8 +867,6 @@ func TestNerdctlCommand_Run_withBindMounts(t *testing.T) {
 				AddEmptyEnvLookUps(ncsd)
 				ncsd.EXPECT().GetWd().Return("C:\\workdir", nil)
 				ncsd.EXPECT().FilePathAbs("C:\\workdir").Return("C:\\workdir", nil).Times(2)
-				ncsd.EXPECT().FilePathJoin(string(filepath.Separator), "mnt", "c", "workdir").Return(augmentedPath).Times(2)
-				ncsd.EXPECT().FilePathToSlash(augmentedPath).Return(wslPath).Times(2)
 
 				c := mocks.NewCommand(ctrl)
 				// alias substitution, run => container run
@@ -945,8 +897,6 @@ func TestNerdctlCommand_Run_withBindMounts(t *testing.T) {
 				AddEmptyEnvLookUps(ncsd)
 				ncsd.EXPECT().GetWd().Return("C:\\workdir", nil)
 				ncsd.EXPECT().FilePathAbs("C:\\workdir").Return("C:\\workdir", nil).Times(2)
-				ncsd.EXPECT().FilePathJoin(string(filepath.Separator), "mnt", "c", "workdir").Return(augmentedPath).Times(2)
-				ncsd.EXPECT().FilePathToSlash(augmentedPath).Return(wslPath).Times(2)
 
 				c := mocks.NewCommand(ctrl)
 				// alias substitution, run => container run
@@ -977,8 +927,6 @@ func TestNerdctlCommand_Run_withBindMounts(t *testing.T) {
 				AddEmptyEnvLookUps(ncsd)
 				ncsd.EXPECT().GetWd().Return("C:\\workdir", nil)
 				ncsd.EXPECT().FilePathAbs("C:\\workdir").Return("C:\\workdir", nil).Times(1)
-				ncsd.EXPECT().FilePathJoin(string(filepath.Separator), "mnt", "c", "workdir").Return(augmentedPath).Times(1)
-				ncsd.EXPECT().FilePathToSlash(augmentedPath).Return(wslPath).Times(1)
 
 				c := mocks.NewCommand(ctrl)
 				// alias substitution, run => container run
@@ -1009,8 +957,6 @@ func TestNerdctlCommand_Run_withBindMounts(t *testing.T) {
 				AddEmptyEnvLookUps(ncsd)
 				ncsd.EXPECT().GetWd().Return("C:\\workdir", nil)
 				ncsd.EXPECT().FilePathAbs("C:\\workdir").Return("C:\\workdir", nil).Times(1)
-				ncsd.EXPECT().FilePathJoin(string(filepath.Separator), "mnt", "c", "workdir").Return(augmentedPath).Times(1)
-				ncsd.EXPECT().FilePathToSlash(augmentedPath).Return(wslPath).Times(1)
 
 				c := mocks.NewCommand(ctrl)
 				// alias substitution, run => container run
@@ -1031,7 +977,7 @@ func TestNerdctlCommand_Run_withBindMounts(t *testing.T) {
 			logger := mocks.NewLogger(ctrl)
 			fs := afero.NewMemMapFs()
 			tc.mockSvc(t, ecc, ncc, ncsd, logger, ctrl, fs)
-			assert.Equal(t, tc.wantErr, newNerdctlCommand(ncc, ecc, ncsd, logger, fs, &config.Finch{}).run(tc.cmdName, tc.args))
+			assert.Equal(t, tc.wantErr, newNerdctlCommand(ncc, ecc, ncsd, logger, fs, &config.Finch{}, mockFinchPath).run(tc.cmdName, tc.args))
 		})
 	}
 }
@@ -1063,12 +1009,8 @@ func TestNerdctlCommand_run_CpCommand(t *testing.T) {
 				AddEmptyEnvLookUps(ncsd)
 				ncsd.EXPECT().GetWd().Return("C:\\workdir", nil)
 				ncsd.EXPECT().FilePathAbs("C:\\workdir").Return("C:\\workdir", nil)
-				ncsd.EXPECT().FilePathJoin(string(filepath.Separator), "mnt", "c", "workdir").Return(augmentedPath)
-				ncsd.EXPECT().FilePathToSlash(augmentedPath).Return(wslPath)
 
 				ncsd.EXPECT().FilePathAbs("C:\\workdir\\test").Return("C:\\workdir\\test", nil)
-				ncsd.EXPECT().FilePathJoin(string(filepath.Separator), "mnt", "c", "workdir\\test").Return(hostcopyPath)
-				ncsd.EXPECT().FilePathToSlash(hostcopyPath).Return(wslcopyPath)
 				c := mocks.NewCommand(ctrl)
 				ncc.EXPECT().Create("shell", "--workdir", wslPath, limaInstanceName,
 					"sudo", "-E", nerdctlCmdName, "container", "cp", wslcopyPath, "somecontainer:/tmp").Return(c)
@@ -1089,12 +1031,8 @@ func TestNerdctlCommand_run_CpCommand(t *testing.T) {
 				AddEmptyEnvLookUps(ncsd)
 				ncsd.EXPECT().GetWd().Return("C:\\workdir", nil)
 				ncsd.EXPECT().FilePathAbs("C:\\workdir").Return("C:\\workdir", nil)
-				ncsd.EXPECT().FilePathJoin(string(filepath.Separator), "mnt", "c", "workdir").Return(augmentedPath)
-				ncsd.EXPECT().FilePathToSlash(augmentedPath).Return(wslPath)
 
 				ncsd.EXPECT().FilePathAbs("C:\\workdir\\test").Return("C:\\workdir\\test", nil)
-				ncsd.EXPECT().FilePathJoin(string(filepath.Separator), "mnt", "c", "workdir\\test").Return(hostcopyPath)
-				ncsd.EXPECT().FilePathToSlash(hostcopyPath).Return(wslcopyPath)
 				c := mocks.NewCommand(ctrl)
 				ncc.EXPECT().Create("shell", "--workdir", wslPath, limaInstanceName,
 					"sudo", "-E", nerdctlCmdName, "container", "cp", "somecontainer:/tmp/test", wslcopyPath).Return(c)
@@ -1115,12 +1053,8 @@ func TestNerdctlCommand_run_CpCommand(t *testing.T) {
 				AddEmptyEnvLookUps(ncsd)
 				ncsd.EXPECT().GetWd().Return("C:\\workdir", nil)
 				ncsd.EXPECT().FilePathAbs("C:\\workdir").Return("C:\\workdir", nil)
-				ncsd.EXPECT().FilePathJoin(string(filepath.Separator), "mnt", "c", "workdir").Return(augmentedPath)
-				ncsd.EXPECT().FilePathToSlash(augmentedPath).Return(wslPath)
 
 				ncsd.EXPECT().FilePathAbs("C:\\workdir\\test").Return("C:\\workdir\\test", nil)
-				ncsd.EXPECT().FilePathJoin(string(filepath.Separator), "mnt", "c", "workdir\\test").Return(hostcopyPath)
-				ncsd.EXPECT().FilePathToSlash(hostcopyPath).Return(wslcopyPath)
 				c := mocks.NewCommand(ctrl)
 				ncc.EXPECT().Create("shell", "--workdir", wslPath, limaInstanceName,
 					"sudo", "-E", nerdctlCmdName, "container", "cp", "-L", "somecontainer:/tmp/test", wslcopyPath).Return(c)
@@ -1140,7 +1074,7 @@ func TestNerdctlCommand_run_CpCommand(t *testing.T) {
 			logger := mocks.NewLogger(ctrl)
 			tc.mockSvc(ecc, ncc, logger, ctrl, ncsd)
 
-			assert.NoError(t, newNerdctlCommand(ncc, ecc, ncsd, logger, nil, &config.Finch{}).run(tc.cmdName, tc.args))
+			assert.NoError(t, newNerdctlCommand(ncc, ecc, ncsd, logger, nil, &config.Finch{}, mockFinchPath).run(tc.cmdName, tc.args))
 		})
 	}
 }
@@ -1174,12 +1108,8 @@ func TestNerdctlCommand_run_BuildCommand(t *testing.T) {
 				AddEmptyEnvLookUps(ncsd)
 				ncsd.EXPECT().GetWd().Return("C:\\workdir", nil)
 				ncsd.EXPECT().FilePathAbs("C:\\workdir").Return("C:\\workdir", nil)
-				ncsd.EXPECT().FilePathJoin(string(filepath.Separator), "mnt", "c", "workdir").Return(augmentedPath)
-				ncsd.EXPECT().FilePathToSlash(augmentedPath).Return(wslPath)
 
 				ncsd.EXPECT().FilePathAbs("C:\\workdir\\buildcontext").Return("C:\\workdir\\buildcontext", nil)
-				ncsd.EXPECT().FilePathJoin(string(filepath.Separator), "mnt", "c", "workdir\\buildcontext").Return(buildContext)
-				ncsd.EXPECT().FilePathToSlash(buildContext).Return(wslBuildContextPath)
 				c := mocks.NewCommand(ctrl)
 				ncc.EXPECT().Create("shell", "--workdir", wslPath, limaInstanceName,
 					"sudo", "-E", nerdctlCmdName, "image", "build", wslBuildContextPath).Return(c)
@@ -1200,12 +1130,8 @@ func TestNerdctlCommand_run_BuildCommand(t *testing.T) {
 				AddEmptyEnvLookUps(ncsd)
 				ncsd.EXPECT().GetWd().Return("C:\\workdir", nil)
 				ncsd.EXPECT().FilePathAbs("C:\\workdir").Return("C:\\workdir", nil)
-				ncsd.EXPECT().FilePathJoin(string(filepath.Separator), "mnt", "c", "workdir").Return(augmentedPath)
-				ncsd.EXPECT().FilePathToSlash(augmentedPath).Return(wslPath)
 
 				ncsd.EXPECT().FilePathAbs("C:\\workdir\\buildcontext").Return("C:\\workdir\\buildcontext", nil).Times(2)
-				ncsd.EXPECT().FilePathJoin(string(filepath.Separator), "mnt", "c", "workdir\\buildcontext").Return(buildContext).Times(2)
-				ncsd.EXPECT().FilePathToSlash(buildContext).Return(wslBuildContextPath).Times(2)
 				c := mocks.NewCommand(ctrl)
 				ncc.EXPECT().Create("shell", "--workdir", wslPath, limaInstanceName,
 					"sudo", "-E", nerdctlCmdName, "image", "build", "-f", ContainsStr(wslBuildContextPath), wslBuildContextPath).Return(c)
@@ -1226,16 +1152,10 @@ func TestNerdctlCommand_run_BuildCommand(t *testing.T) {
 				AddEmptyEnvLookUps(ncsd)
 				ncsd.EXPECT().GetWd().Return("C:\\workdir", nil)
 				ncsd.EXPECT().FilePathAbs("C:\\workdir").Return("C:\\workdir", nil)
-				ncsd.EXPECT().FilePathJoin(string(filepath.Separator), "mnt", "c", "workdir").Return(augmentedPath)
-				ncsd.EXPECT().FilePathToSlash(augmentedPath).Return(wslPath)
 
 				ncsd.EXPECT().FilePathAbs("C:\\workdir\\buildcontext").Return("C:\\workdir\\buildcontext", nil)
-				ncsd.EXPECT().FilePathJoin(string(filepath.Separator), "mnt", "c", "workdir\\buildcontext").Return(buildContext)
-				ncsd.EXPECT().FilePathToSlash(buildContext).Return(wslBuildContextPath)
 
 				ncsd.EXPECT().FilePathAbs("C:\\workdir\\secret").Return("C:\\workdir\\secret", nil)
-				ncsd.EXPECT().FilePathJoin(string(filepath.Separator), "mnt", "c", "workdir\\secret").Return(secretPath)
-				ncsd.EXPECT().FilePathToSlash(secretPath).Return(wslSecretPath)
 				c := mocks.NewCommand(ctrl)
 				ncc.EXPECT().Create("shell", "--workdir", wslPath, limaInstanceName,
 					"sudo", "-E", nerdctlCmdName, "image", "build", "--secret",
@@ -1256,7 +1176,7 @@ func TestNerdctlCommand_run_BuildCommand(t *testing.T) {
 			logger := mocks.NewLogger(ctrl)
 			tc.mockSvc(ecc, ncc, logger, ctrl, ncsd)
 
-			assert.NoError(t, newNerdctlCommand(ncc, ecc, ncsd, logger, nil, &config.Finch{}).run(tc.cmdName, tc.args))
+			assert.NoError(t, newNerdctlCommand(ncc, ecc, ncsd, logger, nil, &config.Finch{}, mockFinchPath).run(tc.cmdName, tc.args))
 		})
 	}
 }
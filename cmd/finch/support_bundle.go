@@ -4,34 +4,75 @@
 package main
 
 import (
+	"bufio"
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/signal"
+	"os/user"
 	"runtime"
+	"strings"
+	"syscall"
+	"time"
 
+	"github.com/docker/go-units"
+	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 
 	"github.com/runfinch/finch/pkg/command"
+	"github.com/runfinch/finch/pkg/config"
 	"github.com/runfinch/finch/pkg/flog"
 	"github.com/runfinch/finch/pkg/support"
 )
 
-func newSupportBundleCommand(logger flog.Logger, builder support.BundleBuilder, ncc command.NerdctlCmdCreator) *cobra.Command {
+func newSupportBundleCommand(
+	logger flog.Logger,
+	builder support.BundleBuilder,
+	uploader support.BundleUploader,
+	ncc command.NerdctlCmdCreator,
+	fs afero.Fs,
+	fc *config.Finch,
+	stdout io.Writer,
+) *cobra.Command {
 	supportBundleCommand := &cobra.Command{
 		Use:   "support-bundle",
 		Short: "Support bundle management",
 	}
 	supportBundleCommand.AddCommand(
-		newSupportBundleGenerateCommand(logger, builder, ncc),
+		newSupportBundleGenerateCommand(logger, builder, uploader, ncc, fs, supportUploadURL(fc)),
+		newSupportBundleInspectCommand(logger, fs, stdout),
+		newSupportBundleScanCommand(logger, fs, stdout),
 	)
 	return supportBundleCommand
 }
 
-func newSupportBundleGenerateCommand(logger flog.Logger, builder support.BundleBuilder, ncc command.NerdctlCmdCreator) *cobra.Command {
+// supportUploadURL returns the URL support bundles are uploaded to by default, from finch.yaml.
+func supportUploadURL(fc *config.Finch) string {
+	if fc == nil {
+		return ""
+	}
+	return fc.Support.UploadURL
+}
+
+func newSupportBundleGenerateCommand(
+	logger flog.Logger,
+	builder support.BundleBuilder,
+	uploader support.BundleUploader,
+	ncc command.NerdctlCmdCreator,
+	fs afero.Fs,
+	uploadURL string,
+) *cobra.Command {
 	supportBundleGenerateCommand := &cobra.Command{
 		Use:   "generate",
 		Args:  cobra.NoArgs,
 		Short: "Generate support bundle",
 		Long:  "Generates a collection of logs and configs that can be uploaded to a Github issue to help debug issues.",
-		RunE:  newGenerateSupportBundleAction(logger, builder, ncc).runAdapter,
+		RunE: newGenerateSupportBundleAction(
+			logger, builder, uploader, ncc, fs, uploadURL, user.Current, os.Hostname,
+		).runAdapter,
 	}
 
 	includeUsage := `additional files to include in the support bundle, specified by absolute or relative path.` +
@@ -43,29 +84,84 @@ func newSupportBundleGenerateCommand(logger flog.Logger, builder support.BundleB
 		`If you specify a base name, all files matching that base name will be excluded. ` +
 		`If you specify an absolute or relative path, only exact matches will be excluded.` +
 		`To exclude journal logs for a service, prefix the file path with "service":".` +
-		`To exclude all journal logs, use "service:all"`
+		`To exclude all journal logs, use "service:all". ` +
+		`To exclude a diagnostic, use "diag:<name>", e.g. "diag:nerdctl-ps". To exclude all diagnostics, use "diag:all"`
 
 	supportBundleGenerateCommand.Flags().StringArray("include", []string{}, includeUsage)
 	supportBundleGenerateCommand.Flags().StringArray("exclude", []string{}, excludeUsage)
 	supportBundleGenerateCommand.Flags().IntP("num-lines", "n", 100, "max number of lines for journalctl services (default 100)")
+	supportBundleGenerateCommand.Flags().String("since", "",
+		`collect the journal logs of the services since a time, e.g. "2h" ago or "2024-01-02 15:04:05" in the local time zone`)
+	supportBundleGenerateCommand.Flags().String("until", "",
+		`collect the journal logs of the services until a time, e.g. "30m" ago or "2024-01-02 15:04:05" in the local time zone`)
+	supportBundleGenerateCommand.Flags().Bool("boot", false,
+		"collect the journal logs of the services since the current boot, instead of their last num-lines lines")
+	supportBundleGenerateCommand.Flags().String("journal-max-size", "",
+		"maximum size of the journal logs of each service, e.g. 5MiB. Their oldest lines are truncated first to fit (default 10MiB)")
+	supportBundleGenerateCommand.Flags().StringP("output", "o", "",
+		`path of the support bundle, or of its directory, or "-" to write it to the standard output `+
+			`(default "finch-support-<timestamp>" in the current directory)`)
+	supportBundleGenerateCommand.Flags().String("format", "",
+		`format of the support bundle, "zip" or "tar.gz" (default "zip", or the format of the extension of --output)`)
+	supportBundleGenerateCommand.Flags().String("max-size", "",
+		"maximum size of the support bundle before compression, e.g. 20MiB. The largest log files are truncated first to fit")
+	supportBundleGenerateCommand.Flags().Duration("timeout", support.DefaultTimeout,
+		"maximum time to collect the support bundle, after which a partial bundle is written, or 0 for no limit")
+	supportBundleGenerateCommand.Flags().Duration("item-timeout", support.DefaultItemTimeout,
+		"maximum time to collect each log, config or diagnostic of the support bundle")
+	supportBundleGenerateCommand.Flags().String("redaction-rules", "",
+		`file of redaction rules applied after the built-in ones (default "redaction-rules.yaml" in the finch directory, if it exists)`)
+	supportBundleGenerateCommand.Flags().String("upload", "",
+		"HTTPS URL to upload the support bundle to once confirmed, e.g. a presigned URL (default support.upload_url of finch.yaml)")
+	supportBundleGenerateCommand.Flags().BoolP("yes", "y", false, "upload the support bundle without asking for confirmation")
+	supportBundleGenerateCommand.Flags().Bool("ignore-findings", false,
+		"upload the support bundle even if its scan finds potential secrets in it")
 	return supportBundleGenerateCommand
 }
 
 type generateSupportBundleAction struct {
-	logger  flog.Logger
-	builder support.BundleBuilder
-	ncc     command.NerdctlCmdCreator
+	logger   flog.Logger
+	builder  support.BundleBuilder
+	uploader support.BundleUploader
+	ncc      command.NerdctlCmdCreator
+	fs       afero.Fs
+	// uploadURL is the URL the bundles are uploaded to if --upload is not set.
+	uploadURL   string
+	currentUser func() (*user.User, error)
+	hostname    func() (string, error)
+}
+
+// bundleUpload is the upload of the support bundle requested by the user.
+type bundleUpload struct {
+	url *url.URL
+	// yes skips the confirmation of the upload.
+	yes bool
+	// ignoreFindings uploads the bundle even if its scan finds potential secrets.
+	ignoreFindings bool
+	// in and out are where the confirmation is read from and asked on.
+	in  io.Reader
+	out io.Writer
 }
 
 func newGenerateSupportBundleAction(
 	logger flog.Logger,
 	builder support.BundleBuilder,
+	uploader support.BundleUploader,
 	ncc command.NerdctlCmdCreator,
+	fs afero.Fs,
+	uploadURL string,
+	currentUser func() (*user.User, error),
+	hostname func() (string, error),
 ) *generateSupportBundleAction {
 	return &generateSupportBundleAction{
-		logger:  logger,
-		builder: builder,
-		ncc:     ncc,
+		logger:      logger,
+		builder:     builder,
+		uploader:    uploader,
+		ncc:         ncc,
+		fs:          fs,
+		uploadURL:   uploadURL,
+		currentUser: currentUser,
+		hostname:    hostname,
 	}
 }
 
@@ -85,27 +181,240 @@ func (gsa *generateSupportBundleAction) runAdapter(cmd *cobra.Command, _ []strin
 	if logLines < 0 {
 		return fmt.Errorf("num-lines cannot be less than zero (provided value: %d)", logLines)
 	}
+	now := time.Now()
+	since, err := getJournalTimeFlag(cmd, "since", now)
+	if err != nil {
+		return err
+	}
+	until, err := getJournalTimeFlag(cmd, "until", now)
+	if err != nil {
+		return err
+	}
+	if !since.IsZero() && !until.IsZero() && since.After(until) {
+		return fmt.Errorf("since (%s) must be before until (%s)", since.Format(time.DateTime), until.Format(time.DateTime))
+	}
+	// The time window bounds the journal logs instead of the default number of lines, and journal-max-size their size.
+	if (!since.IsZero() || !until.IsZero()) && !cmd.Flags().Changed("num-lines") {
+		logLines = 0
+	}
+	boot, err := cmd.Flags().GetBool("boot")
+	if err != nil {
+		return err
+	}
+	journalMaxSize, err := getSizeFlag(cmd, "journal-max-size")
+	if err != nil {
+		return err
+	}
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		return err
+	}
+	format, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return err
+	}
+	if format != "" && format != support.FormatZip && format != support.FormatTarGz {
+		return fmt.Errorf("format must be %q or %q (provided value: %q)", support.FormatZip, support.FormatTarGz, format)
+	}
+	maxSize, err := getSizeFlag(cmd, "max-size")
+	if err != nil {
+		return err
+	}
+	timeout, err := cmd.Flags().GetDuration("timeout")
+	if err != nil {
+		return err
+	}
+	if timeout < 0 {
+		return fmt.Errorf("timeout cannot be less than zero (provided value: %s)", timeout)
+	}
+	itemTimeout, err := cmd.Flags().GetDuration("item-timeout")
+	if err != nil {
+		return err
+	}
+	if itemTimeout <= 0 {
+		return fmt.Errorf("item-timeout must be greater than zero (provided value: %s)", itemTimeout)
+	}
+	redactionRules, err := cmd.Flags().GetString("redaction-rules")
+	if err != nil {
+		return err
+	}
+	upload, err := gsa.getUpload(cmd, output)
+	if err != nil {
+		return err
+	}
 	cfg := &support.BundleCfg{
 		AdditionalFiles: additionalFiles,
 		ExcludeFiles:    excludeFiles,
 		LogLines:        logLines,
+		Since:           since,
+		Until:           until,
+		Boot:            boot,
+		JournalMaxSize:  journalMaxSize,
+		Output:          output,
+		Format:          format,
+		MaxSize:         maxSize,
+		Timeout:         timeout,
+		ItemTimeout:     itemTimeout,
+		RedactionRules:  redactionRules,
+	}
+	return gsa.run(cmd.Context(), cfg, upload)
+}
+
+// getUpload returns the upload of the support bundle requested by --upload or finch.yaml, or nil if there is none.
+func (gsa *generateSupportBundleAction) getUpload(cmd *cobra.Command, output string) (*bundleUpload, error) {
+	rawURL, err := cmd.Flags().GetString("upload")
+	if err != nil {
+		return nil, err
+	}
+	if rawURL == "" {
+		rawURL = gsa.uploadURL
+	}
+	yes, err := cmd.Flags().GetBool("yes")
+	if err != nil {
+		return nil, err
+	}
+	ignoreFindings, err := cmd.Flags().GetBool("ignore-findings")
+	if err != nil {
+		return nil, err
+	}
+	if rawURL == "" {
+		return nil, nil
+	}
+	if output == support.Stdout {
+		return nil, fmt.Errorf("cannot upload the support bundle when it is written to the standard output")
+	}
+	uploadURL, err := support.ParseUploadURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	return &bundleUpload{
+		url:            uploadURL,
+		yes:            yes,
+		ignoreFindings: ignoreFindings,
+		in:             cmd.InOrStdin(),
+		out:            cmd.ErrOrStderr(),
+	}, nil
+}
+
+// getSizeFlag returns the size in bytes of the flag, e.g. "20MiB", or 0 if it is not set.
+func getSizeFlag(cmd *cobra.Command, name string) (int64, error) {
+	value, err := cmd.Flags().GetString(name)
+	if err != nil || value == "" {
+		return 0, err
+	}
+	size, err := units.RAMInBytes(value)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+	if size <= 0 {
+		return 0, fmt.Errorf("%s must be greater than zero (provided value: %s)", name, value)
+	}
+	return size, nil
+}
+
+// getJournalTimeFlag returns the time of the flag, relative to now if it is a duration, or the zero time if it is not set.
+func getJournalTimeFlag(cmd *cobra.Command, name string, now time.Time) (time.Time, error) {
+	value, err := cmd.Flags().GetString(name)
+	if err != nil || value == "" {
+		return time.Time{}, err
+	}
+	t, err := support.ParseJournalTime(value, now)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse %s: %w", name, err)
 	}
-	return gsa.run(cfg)
+	return t, nil
 }
 
-func (gsa *generateSupportBundleAction) run(cfg *support.BundleCfg) error {
+func (gsa *generateSupportBundleAction) run(ctx context.Context, cfg *support.BundleCfg, upload *bundleUpload) error {
 	err := gsa.canCreateBundle()
 	if err != nil {
 		return err
 	}
+
+	// on Ctrl-C, the items being collected are stopped and a partial bundle is written,
+	// unless Ctrl-C is pressed again, which exits right away
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
 	gsa.logger.Info("Generating support bundle...")
-	bundleFile, err := gsa.builder.GenerateSupportBundle(cfg)
-	if err != nil {
+	bundleFile, err := gsa.builder.GenerateSupportBundle(ctx, cfg)
+	partial := errors.Is(err, support.ErrPartialBundle)
+	if err != nil && !partial {
 		return err
 	}
-	gsa.logger.Infof("Bundle created: %s", bundleFile)
+	switch {
+	case bundleFile == support.Stdout && partial:
+		gsa.logger.Warnln("Partial bundle written to the standard output")
+	case bundleFile == support.Stdout:
+		gsa.logger.Info("Bundle written to the standard output")
+	case partial:
+		gsa.logger.Warnf("Partial bundle created: %s", bundleFile)
+	default:
+		gsa.logger.Infof("Bundle created: %s", bundleFile)
+	}
 	gsa.logger.Info("Files posted on a Github issue can be read by anyone.")
 	gsa.logger.Info("Please ensure there is no sensitive information in the bundle before uploading.")
+	if bundleFile != support.Stdout {
+		gsa.logger.Infof("To scan it for sensitive information, run `finch support-bundle scan %s`", bundleFile)
+	}
 	gsa.logger.Info("By default, this bundle contains basic logs and configs for Finch.")
+	// a bundle interrupted by the user is not uploaded, unlike one which timed out
+	if upload != nil && ctx.Err() == nil {
+		if uploadErr := gsa.upload(ctx, bundleFile, upload); uploadErr != nil {
+			return uploadErr
+		}
+	}
+	// the error tells why the bundle is partial, and makes finch exit with an error
+	return err
+}
+
+// upload uploads the bundle, once the user confirmed it, unless --yes is set.
+// The bundle is scanned first, and is not uploaded if potential secrets are found in it, unless --ignore-findings is set.
+func (gsa *generateSupportBundleAction) upload(ctx context.Context, bundleFile string, upload *bundleUpload) error {
+	findings, err := newBundleScanner(gsa.logger, gsa.currentUser, gsa.hostname).ScanFile(gsa.fs, bundleFile)
+	if err != nil {
+		return err
+	}
+	if len(findings) > 0 {
+		if err := printFindings(upload.out, findings); err != nil {
+			return err
+		}
+		if !upload.ignoreFindings {
+			return fmt.Errorf("found %d potential secrets in %s, remove them, or use --ignore-findings to upload the bundle anyway",
+				len(findings), bundleFile)
+		}
+		gsa.logger.Warnf("Uploading %s with %d potential secrets, as --ignore-findings is set", bundleFile, len(findings))
+	}
+
+	if !upload.yes {
+		info, err := gsa.fs.Stat(bundleFile)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(upload.out, "Upload %s (%s) to %s? [y/N] ",
+			bundleFile, units.BytesSize(float64(info.Size())), support.DisplayUploadURL(upload.url))
+		answer, err := bufio.NewReader(upload.in).ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+		if answer = strings.ToLower(strings.TrimSpace(answer)); answer != "y" && answer != "yes" {
+			gsa.logger.Info("The bundle was not uploaded")
+			return nil
+		}
+	}
+
+	referenceID, verified, err := gsa.uploader.Upload(ctx, bundleFile, upload.url)
+	if err != nil {
+		return err
+	}
+	gsa.logger.Infof("Bundle uploaded, reference ID: %s", referenceID)
+	if !verified {
+		gsa.logger.Warnln("The upload endpoint returned no checksum, the uploaded bundle could not be verified")
+	}
+	gsa.logger.Info("Please share the reference ID with the support team.")
 	return nil
 }
@@ -0,0 +1,105 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/docker/go-units"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+
+	"github.com/runfinch/finch/pkg/flog"
+	"github.com/runfinch/finch/pkg/support"
+)
+
+func newSupportBundleInspectCommand(logger flog.Logger, fs afero.Fs, stdout io.Writer) *cobra.Command {
+	return &cobra.Command{
+		Use:   "inspect <bundle> [path...]",
+		Args:  cobra.MinimumNArgs(1),
+		Short: "Inspect a support bundle",
+		Long: "Lists the files of a support bundle with their sizes, or prints the files at the given paths, " +
+			"relative to the directory of the bundle, e.g. finch support-bundle inspect finch-support.zip logs/ha.stderr.log.",
+		RunE: newInspectSupportBundleAction(logger, fs, stdout).runAdapter,
+	}
+}
+
+type inspectSupportBundleAction struct {
+	logger flog.Logger
+	fs     afero.Fs
+	stdout io.Writer
+}
+
+func newInspectSupportBundleAction(logger flog.Logger, fs afero.Fs, stdout io.Writer) *inspectSupportBundleAction {
+	return &inspectSupportBundleAction{
+		logger: logger,
+		fs:     fs,
+		stdout: stdout,
+	}
+}
+
+func (isa *inspectSupportBundleAction) runAdapter(_ *cobra.Command, args []string) error {
+	return isa.run(args[0], args[1:])
+}
+
+func (isa *inspectSupportBundleAction) run(bundlePath string, filePaths []string) error {
+	bundle, err := support.ReadBundle(isa.fs, bundlePath)
+	if err != nil {
+		return err
+	}
+	if len(filePaths) > 0 {
+		return isa.printFiles(bundle, filePaths)
+	}
+	return isa.listFiles(bundle)
+}
+
+// printFiles prints the files of the bundle at filePaths, after checking they all exist.
+func (isa *inspectSupportBundleAction) printFiles(bundle *support.Bundle, filePaths []string) error {
+	var files []*support.BundleFile
+	for _, filePath := range filePaths {
+		f, ok := bundle.File(filePath)
+		if !ok {
+			return fmt.Errorf("%s is not in the support bundle", filePath)
+		}
+		files = append(files, f)
+	}
+	for _, f := range files {
+		if _, err := isa.stdout.Write(f.Content()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listFiles prints the files of the bundle with their sizes, and whether they were truncated according to its manifest.
+func (isa *inspectSupportBundleAction) listFiles(bundle *support.Bundle) error {
+	manifest, err := bundle.Manifest()
+	if err != nil {
+		isa.logger.Warnf("Could not read the manifest of the support bundle. Error: %s", err)
+	}
+	truncated := map[string]int64{}
+	if manifest != nil {
+		for _, item := range manifest.Items {
+			if item.Truncated {
+				truncated[item.Path] = item.OriginalSize
+			}
+		}
+	}
+
+	tw := tabwriter.NewWriter(isa.stdout, 0, 8, 3, ' ', 0)
+	fmt.Fprintln(tw, "PATH\tSIZE\tNOTES")
+	var total int64
+	for _, f := range bundle.Files {
+		total += f.Size
+		notes := ""
+		if originalSize, ok := truncated[f.Path]; ok {
+			notes = "truncated from " + units.BytesSize(float64(originalSize))
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", f.Path, units.BytesSize(float64(f.Size)), notes)
+	}
+	fmt.Fprintf(tw, "%d files\t%s\t\n", len(bundle.Files), units.BytesSize(float64(total)))
+	return tw.Flush()
+}
@@ -0,0 +1,98 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/runfinch/finch/pkg/mocks"
+)
+
+// writeTestBundle writes a zip support bundle with the files to bundlePath.
+func writeTestBundle(t *testing.T, fs afero.Fs, bundlePath string, files [][2]string) {
+	t.Helper()
+
+	var b bytes.Buffer
+	zw := zip.NewWriter(&b)
+	_, err := zw.Create("finch-support/")
+	require.NoError(t, err)
+	for _, f := range files {
+		w, err := zw.Create("finch-support/" + f[0])
+		require.NoError(t, err)
+		_, err = w.Write([]byte(f[1]))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	require.NoError(t, afero.WriteFile(fs, bundlePath, b.Bytes(), 0o644))
+}
+
+func TestNewSupportBundleInspectCommand(t *testing.T) {
+	t.Parallel()
+
+	cmd := newSupportBundleInspectCommand(nil, nil, nil)
+	assert.Equal(t, "inspect", cmd.Name())
+}
+
+func TestInspectSupportBundleAction_run(t *testing.T) {
+	t.Parallel()
+
+	files := [][2]string{
+		{"logs/ha.stderr.log", "started\n"},
+		{"configs/finch.yaml", "cpus: 2\n"},
+		{"manifest.json", `{"items":[{"path":"logs/ha.stderr.log","truncated":true,"originalSize":2048}]}`},
+	}
+
+	testCases := []struct {
+		name       string
+		filePaths  []string
+		wantOutput string
+		wantErr    string
+	}{
+		{
+			name: "lists the files",
+			wantOutput: "PATH                 SIZE   NOTES\n" +
+				"logs/ha.stderr.log   8B     truncated from 2KiB\n" +
+				"configs/finch.yaml   8B     \n" +
+				"manifest.json        78B    \n" +
+				"3 files              94B    \n",
+		},
+		{
+			name:       "prints the files",
+			filePaths:  []string{"configs/finch.yaml", "logs/ha.stderr.log"},
+			wantOutput: "cpus: 2\nstarted\n",
+		},
+		{
+			name:      "prints no files if one is missing",
+			filePaths: []string{"configs/finch.yaml", "logs/missing.log"},
+			wantErr:   "logs/missing.log is not in the support bundle",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			logger := mocks.NewLogger(ctrl)
+			fs := afero.NewMemMapFs()
+			writeTestBundle(t, fs, "finch-support.zip", files)
+			stdout := new(bytes.Buffer)
+
+			err := newInspectSupportBundleAction(logger, fs, stdout).run("finch-support.zip", tc.filePaths)
+			if tc.wantErr != "" {
+				assert.EqualError(t, err, tc.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.wantOutput, stdout.String())
+		})
+	}
+}
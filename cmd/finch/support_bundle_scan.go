@@ -0,0 +1,104 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"strings"
+
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+
+	"github.com/runfinch/finch/pkg/flog"
+	"github.com/runfinch/finch/pkg/support"
+)
+
+func newSupportBundleScanCommand(logger flog.Logger, fs afero.Fs, stdout io.Writer) *cobra.Command {
+	return &cobra.Command{
+		Use:   "scan <bundle>",
+		Args:  cobra.ExactArgs(1),
+		Short: "Scan a support bundle for sensitive information",
+		Long: "Scans a support bundle for potential secrets: AWS keys, tokens, private keys, high entropy strings, " +
+			"the current username and the hostname. It exits with a non-zero status if it finds any, " +
+			"so that it can gate uploads in scripts.",
+		RunE: newScanSupportBundleAction(logger, fs, stdout, user.Current, os.Hostname).runAdapter,
+	}
+}
+
+type scanSupportBundleAction struct {
+	logger      flog.Logger
+	fs          afero.Fs
+	stdout      io.Writer
+	currentUser func() (*user.User, error)
+	hostname    func() (string, error)
+}
+
+func newScanSupportBundleAction(
+	logger flog.Logger,
+	fs afero.Fs,
+	stdout io.Writer,
+	currentUser func() (*user.User, error),
+	hostname func() (string, error),
+) *scanSupportBundleAction {
+	return &scanSupportBundleAction{
+		logger:      logger,
+		fs:          fs,
+		stdout:      stdout,
+		currentUser: currentUser,
+		hostname:    hostname,
+	}
+}
+
+func (ssa *scanSupportBundleAction) runAdapter(_ *cobra.Command, args []string) error {
+	return ssa.run(args[0])
+}
+
+func (ssa *scanSupportBundleAction) run(bundlePath string) error {
+	scanner := newBundleScanner(ssa.logger, ssa.currentUser, ssa.hostname)
+	findings, err := scanner.ScanFile(ssa.fs, bundlePath)
+	if err != nil {
+		return err
+	}
+	if len(findings) == 0 {
+		ssa.logger.Infof("No sensitive information found in %s", bundlePath)
+		return nil
+	}
+	if err := printFindings(ssa.stdout, findings); err != nil {
+		return err
+	}
+	return fmt.Errorf("found %d potential secrets in %s, remove them before uploading the bundle", len(findings), bundlePath)
+}
+
+// newBundleScanner returns a scanner of support bundles detecting the current username and the hostname as well.
+func newBundleScanner(
+	logger flog.Logger,
+	currentUser func() (*user.User, error),
+	hostname func() (string, error),
+) *support.Scanner {
+	username := ""
+	if u, err := currentUser(); err == nil {
+		// the username is DOMAIN\user on Windows, which only appears in the logs as user, e.g. in C:\Users\user
+		username = u.Username[strings.LastIndex(u.Username, `\`)+1:]
+	} else {
+		logger.Warnf("Could not get the current user, the bundle is not scanned for the username. Error: %s", err)
+	}
+	host, err := hostname()
+	if err != nil {
+		logger.Warnf("Could not get the hostname, the bundle is not scanned for it. Error: %s", err)
+	}
+	return support.NewScanner(username, host)
+}
+
+// printFindings prints the findings of a scan, one per line, with their match masked.
+func printFindings(w io.Writer, findings []support.Finding) error {
+	for _, f := range findings {
+		if _, err := fmt.Fprintf(w, "%s:%d: %s: %s\n", f.Path, f.Line, f.Detector, f.Masked()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
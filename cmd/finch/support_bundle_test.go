@@ -0,0 +1,245 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os/user"
+	"strings"
+	"testing"
+
+	"github.com/docker/go-units"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/runfinch/finch/pkg/config"
+	"github.com/runfinch/finch/pkg/mocks"
+)
+
+func TestNewSupportBundleGenerateCommand(t *testing.T) {
+	t.Parallel()
+
+	cmd := newSupportBundleGenerateCommand(nil, nil, nil, nil, nil, "")
+	assert.Equal(t, "generate", cmd.Name())
+	assert.NotNil(t, cmd.Flags().Lookup("upload"))
+	assert.Equal(t, "y", cmd.Flags().Lookup("yes").Shorthand)
+}
+
+func TestSupportUploadURL(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "", supportUploadURL(nil))
+	fc := &config.Finch{SharedSettings: config.SharedSettings{Support: config.SupportSettings{UploadURL: "https://example.com"}}}
+	assert.Equal(t, "https://example.com", supportUploadURL(fc))
+}
+
+func TestGenerateSupportBundleAction_getUpload(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name      string
+		args      []string
+		uploadURL string
+		wantURL   string
+		wantYes   bool
+		wantErr   string
+	}{
+		{
+			name: "no upload",
+		},
+		{
+			name:    "upload flag",
+			args:    []string{"--upload", "https://example.com/bundle.zip?sig=secret", "-y"},
+			wantURL: "https://example.com/bundle.zip?sig=secret",
+			wantYes: true,
+		},
+		{
+			name:      "upload URL of finch.yaml",
+			uploadURL: "https://example.com/default.zip",
+			wantURL:   "https://example.com/default.zip",
+		},
+		{
+			name:      "upload flag overrides finch.yaml",
+			args:      []string{"--upload", "https://example.com/bundle.zip"},
+			uploadURL: "https://example.com/default.zip",
+			wantURL:   "https://example.com/bundle.zip",
+		},
+		{
+			name:    "http URL",
+			args:    []string{"--upload", "http://example.com/bundle.zip"},
+			wantErr: "invalid upload URL http://example.com/bundle.zip, must be an https URL",
+		},
+		{
+			name:    "standard output",
+			args:    []string{"--upload", "https://example.com/bundle.zip", "--output", "-"},
+			wantErr: "cannot upload the support bundle when it is written to the standard output",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			cmd := newSupportBundleGenerateCommand(nil, nil, nil, nil, nil, tc.uploadURL)
+			require.NoError(t, cmd.ParseFlags(tc.args))
+			output, err := cmd.Flags().GetString("output")
+			require.NoError(t, err)
+			gsa := newGenerateSupportBundleAction(nil, nil, nil, nil, nil, tc.uploadURL, nil, nil)
+
+			upload, err := gsa.getUpload(cmd, output)
+			switch {
+			case tc.wantErr != "":
+				assert.EqualError(t, err, tc.wantErr)
+			case tc.wantURL == "":
+				assert.NoError(t, err)
+				assert.Nil(t, upload)
+			default:
+				require.NoError(t, err)
+				assert.Equal(t, tc.wantURL, upload.url.String())
+				assert.Equal(t, tc.wantYes, upload.yes)
+			}
+		})
+	}
+}
+
+func TestGenerateSupportBundleAction_upload(t *testing.T) {
+	t.Parallel()
+
+	uploadURL, err := url.Parse("https://example.com/bundle.zip?sig=secret")
+	require.NoError(t, err)
+	clean := [][2]string{{"logs/ha.stderr.log", "started\n"}}
+	secrets := [][2]string{{"logs/ha.stderr.log", "started by alice\n"}}
+	const finding = "logs/ha.stderr.log:1: username: a****\n"
+
+	testCases := []struct {
+		name           string
+		files          [][2]string
+		yes            bool
+		ignoreFindings bool
+		input          string
+		mockSvc        func(*mocks.Logger, *mocks.BundleUploader)
+		wantOutput     string
+		wantPrompt     bool
+		wantErr        string
+	}{
+		{
+			name:  "confirmed",
+			files: clean,
+			input: "y\n",
+			mockSvc: func(logger *mocks.Logger, uploader *mocks.BundleUploader) {
+				uploader.EXPECT().Upload(gomock.Any(), "finch-support.zip", uploadURL).Return("request-id", true, nil)
+				logger.EXPECT().Infof("Bundle uploaded, reference ID: %s", "request-id")
+				logger.EXPECT().Info("Please share the reference ID with the support team.")
+			},
+			wantPrompt: true,
+		},
+		{
+			name:  "declined",
+			files: clean,
+			input: "\n",
+			mockSvc: func(logger *mocks.Logger, _ *mocks.BundleUploader) {
+				logger.EXPECT().Info("The bundle was not uploaded")
+			},
+			wantPrompt: true,
+		},
+		{
+			name:  "no input",
+			files: clean,
+			input: "",
+			mockSvc: func(logger *mocks.Logger, _ *mocks.BundleUploader) {
+				logger.EXPECT().Info("The bundle was not uploaded")
+			},
+			wantPrompt: true,
+		},
+		{
+			name:  "confirmed by --yes, not verified",
+			files: clean,
+			yes:   true,
+			mockSvc: func(logger *mocks.Logger, uploader *mocks.BundleUploader) {
+				uploader.EXPECT().Upload(gomock.Any(), "finch-support.zip", uploadURL).Return("abc123", false, nil)
+				logger.EXPECT().Infof("Bundle uploaded, reference ID: %s", "abc123")
+				logger.EXPECT().Warnln("The upload endpoint returned no checksum, the uploaded bundle could not be verified")
+				logger.EXPECT().Info("Please share the reference ID with the support team.")
+			},
+		},
+		{
+			name:  "upload failed",
+			files: clean,
+			input: "YES\n",
+			mockSvc: func(_ *mocks.Logger, uploader *mocks.BundleUploader) {
+				uploader.EXPECT().Upload(gomock.Any(), "finch-support.zip", uploadURL).Return("", false, errors.New("403 Forbidden"))
+			},
+			wantPrompt: true,
+			wantErr:    "403 Forbidden",
+		},
+		{
+			name:       "potential secrets found, even with --yes",
+			files:      secrets,
+			yes:        true,
+			mockSvc:    func(*mocks.Logger, *mocks.BundleUploader) {},
+			wantOutput: finding,
+			wantErr: "found 1 potential secrets in finch-support.zip, " +
+				"remove them, or use --ignore-findings to upload the bundle anyway",
+		},
+		{
+			name:           "potential secrets ignored",
+			files:          secrets,
+			ignoreFindings: true,
+			input:          "y\n",
+			mockSvc: func(logger *mocks.Logger, uploader *mocks.BundleUploader) {
+				logger.EXPECT().Warnf("Uploading %s with %d potential secrets, as --ignore-findings is set", "finch-support.zip", 1)
+				uploader.EXPECT().Upload(gomock.Any(), "finch-support.zip", uploadURL).Return("request-id", true, nil)
+				logger.EXPECT().Infof("Bundle uploaded, reference ID: %s", "request-id")
+				logger.EXPECT().Info("Please share the reference ID with the support team.")
+			},
+			wantOutput: finding,
+			wantPrompt: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			logger := mocks.NewLogger(ctrl)
+			uploader := mocks.NewBundleUploader(ctrl)
+			tc.mockSvc(logger, uploader)
+			fs := afero.NewMemMapFs()
+			writeTestBundle(t, fs, "finch-support.zip", tc.files)
+			info, err := fs.Stat("finch-support.zip")
+			require.NoError(t, err)
+			currentUser := func() (*user.User, error) { return &user.User{Username: "alice"}, nil }
+			hostname := func() (string, error) { return "alices-laptop", nil }
+			gsa := newGenerateSupportBundleAction(logger, nil, uploader, nil, fs, "", currentUser, hostname)
+
+			var out bytes.Buffer
+			upload := &bundleUpload{
+				url:            uploadURL,
+				yes:            tc.yes,
+				ignoreFindings: tc.ignoreFindings,
+				in:             strings.NewReader(tc.input),
+				out:            &out,
+			}
+			err = gsa.upload(context.Background(), "finch-support.zip", upload)
+			if tc.wantErr != "" {
+				assert.EqualError(t, err, tc.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			wantOutput := tc.wantOutput
+			if tc.wantPrompt {
+				wantOutput += fmt.Sprintf("Upload finch-support.zip (%s) to https://example.com/bundle.zip? [y/N] ",
+					units.BytesSize(float64(info.Size())))
+			}
+			assert.Equal(t, wantOutput, out.String())
+		})
+	}
+}
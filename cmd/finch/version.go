@@ -4,11 +4,9 @@
 package main
 
 import (
-	"bytes"
 	"errors"
 	"fmt"
 	"io"
-	"text/template"
 
 	"github.com/spf13/cobra"
 
@@ -52,7 +50,7 @@ func newVersionCommand(limaCmdCreator command.NerdctlCmdCreator, logger flog.Log
 		RunE:  newVersionAction(limaCmdCreator, logger, stdOut).runAdapter,
 	}
 
-	versionCommand.Flags().StringP("format", "f", "", "Format the output using the given Go template, e.g, '{{json .}}'")
+	addFormatFlag(versionCommand, "f")
 
 	return versionCommand
 }
@@ -111,7 +109,7 @@ func newVersionAction(creator command.NerdctlCmdCreator, logger flog.Logger, std
 }
 
 func (va *versionAction) runAdapter(cmd *cobra.Command, _ []string) error {
-	format, err := cmd.Flags().GetString("format")
+	format, err := getFormatFlag(cmd)
 	if err != nil {
 		return err
 	}
@@ -130,21 +128,8 @@ func (va *versionAction) run(format string) error {
 	return nil
 }
 
-func newVersionTemplate(templateFormat string) (*template.Template, error) {
-	switch templateFormat {
-	case "":
-		templateFormat = defaultVersionTemplate
-	case templates.JSONFormatKey:
-		templateFormat = templates.JSONFormat
-	}
-
-	tmpl := templates.New("version")
-	tmpl, err := tmpl.Parse(templateFormat)
-	if err != nil {
-		return nil, err
-	}
-
-	return tmpl, nil
+func newVersionTemplate(format string) (*templates.Format, error) {
+	return templates.Parse("version", format, defaultVersionTemplate)
 }
 
 func createFinchVersionOutput(nerdctlVersion NerdctlVersionOutput) FinchVersionOutput {
@@ -158,16 +143,6 @@ func createFinchVersionOutput(nerdctlVersion NerdctlVersionOutput) FinchVersionO
 	return finchVersionOutput
 }
 
-func (va *versionAction) showVersionMessage(tmpl *template.Template, nerdctlVersion NerdctlVersionOutput) error {
-	var b bytes.Buffer
-
-	finchVersionOutput := createFinchVersionOutput(nerdctlVersion)
-	if err := tmpl.Execute(&b, finchVersionOutput); err != nil {
-		return err
-	}
-	if _, err := fmt.Fprintln(va.stdOut, b.String()); err != nil {
-		return err
-	}
-
-	return nil
+func (va *versionAction) showVersionMessage(format *templates.Format, nerdctlVersion NerdctlVersionOutput) error {
+	return format.Execute(va.stdOut, createFinchVersionOutput(nerdctlVersion))
 }
@@ -7,6 +7,7 @@ package main
 
 import (
 	"bytes"
+	"errors"
 	"strings"
 	"testing"
 
@@ -291,6 +292,48 @@ func TestVersionAction_run(t *testing.T) {
 			},
 			format: "{{.Server}}",
 		},
+		{
+			name:    "with --format table",
+			wantErr: errors.New(`the version output has no default table format, use "table" followed by the template of a row`),
+			cmd: func(_ *testing.T) *cobra.Command {
+				c := &cobra.Command{
+					Use: "version",
+				}
+				c.Flags().StringP("format", "f", "", "Format the output using the given Go template, e.g, '{{json .}}'")
+
+				return c
+			},
+			mockSvc: func(ncc *mocks.NerdctlCmdCreator, _ *mocks.Logger, ctrl *gomock.Controller) {
+				command := mocks.NewCommand(ctrl)
+				ncc.EXPECT().CreateWithoutStdio("version", "--format", "json").Return(command)
+				command.EXPECT().Output().Return([]byte(nerdctlMockVersion), nil)
+			},
+			postRunCheck: func(t *testing.T, stdout []byte) {
+				assert.Equal(t, "Finch version:\t\n", string(stdout))
+			},
+			format: "table",
+		},
+		{
+			name:    "with --format table and a row template",
+			wantErr: nil,
+			cmd: func(_ *testing.T) *cobra.Command {
+				c := &cobra.Command{
+					Use: "version",
+				}
+				c.Flags().StringP("format", "f", "", "Format the output using the given Go template, e.g, '{{json .}}'")
+
+				return c
+			},
+			mockSvc: func(ncc *mocks.NerdctlCmdCreator, _ *mocks.Logger, ctrl *gomock.Controller) {
+				command := mocks.NewCommand(ctrl)
+				ncc.EXPECT().CreateWithoutStdio("version", "--format", "json").Return(command)
+				command.EXPECT().Output().Return([]byte(nerdctlMockVersion), nil)
+			},
+			postRunCheck: func(t *testing.T, stdout []byte) {
+				assert.Equal(t, "OS        VERSION\nlinux     v1.0.0\n", string(stdout))
+			},
+			format: `table {{.Client.NerdctlClient.Os}}\t{{.Client.NerdctlClient.Version}}`,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -19,6 +19,7 @@ import (
 	"github.com/runfinch/finch/pkg/config"
 	credserver "github.com/runfinch/finch/pkg/credserver"
 	"github.com/runfinch/finch/pkg/flog"
+	"github.com/runfinch/finch/pkg/lima"
 	"github.com/runfinch/finch/pkg/path"
 
 	"github.com/spf13/afero"
@@ -30,6 +31,33 @@ const (
 	virtualMachineRootCmd = "vm"
 )
 
+// vmStatusHint returns how to recover the instance from status, or "" if there is nothing to recover from.
+func vmStatusHint(status lima.VMStatus) string {
+	switch status {
+	case lima.Broken:
+		return fmt.Sprintf("run `finch %s status` to see why, then `finch %s stop --force` to stop it, "+
+			"or `finch %s remove --force` to remove it", virtualMachineRootCmd, virtualMachineRootCmd, virtualMachineRootCmd)
+	case lima.Installing:
+		return fmt.Sprintf("wait for the installation to complete, or run `finch %s remove --force` to remove it",
+			virtualMachineRootCmd)
+	case lima.Uninitialized:
+		return fmt.Sprintf("run `finch %s remove --force` and `finch %s init` to create it again",
+			virtualMachineRootCmd, virtualMachineRootCmd)
+	default:
+		return ""
+	}
+}
+
+// unhealthyVMError returns the error of the commands that cannot act on the instance with status, or nil
+// if the instance is running, stopped or does not exist.
+func unhealthyVMError(status lima.VMStatus) error {
+	hint := vmStatusHint(status)
+	if hint == "" {
+		return nil
+	}
+	return fmt.Errorf("the instance %q is %s, %s", limaInstanceName, strings.ToLower(status.String()), hint)
+}
+
 // Used by the actions that call VM start to ensure that the in-VM config file options are applied after boot.
 type postVMStartInitAction struct {
 	creator        command.NerdctlCmdCreator
@@ -119,6 +147,7 @@ func virtualMachineCommands(
 			fp.LimaInstancePath(),
 			fc,
 		),
+		fc,
 		fp,
 		fs,
 		disk.NewUserDataDiskManager(ncc, ecc, &afero.OsFs{}, fp, finchRootPath, fc, logger),
@@ -19,7 +19,7 @@ import (
 	"github.com/runfinch/finch/pkg/path"
 )
 
-func newDiskVMCommand(creator command.NerdctlCmdCreator, logger flog.Logger) *cobra.Command {
+func newDiskVMCommand(creator command.NerdctlCmdCreator, logger flog.Logger, fs afero.Fs) *cobra.Command {
 	diskCmd := &cobra.Command{
 		Use:   "disk",
 		Short: "Manage virtual machine disk operations",
@@ -27,7 +27,7 @@ func newDiskVMCommand(creator command.NerdctlCmdCreator, logger flog.Logger) *co
 
 	diskCmd.AddCommand(
 		newVMDiskResizeCommand(creator, logger),
-		newVMDiskInfoCommand(creator, logger),
+		newVMDiskInfoCommand(creator, logger, fs),
 	)
 
 	return diskCmd
@@ -39,6 +39,7 @@ func newVirtualMachineCommand(
 	optionalDepGroups []*dependency.Group,
 	lca config.LimaConfigApplier,
 	nca config.NerdctlConfigApplier,
+	fc *config.Finch,
 	fp path.Finch,
 	fs afero.Fs,
 	diskManager disk.UserDataDiskManager,
@@ -52,11 +53,11 @@ func newVirtualMachineCommand(
 		newStartVMCommand(limaCmdCreator, logger, optionalDepGroups, lca, nca, fs, fp.LimaSSHPrivateKeyPath(), diskManager),
 		newStopVMCommand(limaCmdCreator, diskManager, logger),
 		newRemoveVMCommand(limaCmdCreator, diskManager, logger),
-		newStatusVMCommand(limaCmdCreator, logger, os.Stdout),
+		newStatusVMCommand(limaCmdCreator, logger, fc, fs, os.Stdout),
 		newInitVMCommand(limaCmdCreator, logger, optionalDepGroups, lca, nca, fp.BaseYamlFilePath(), fs,
 			fp.LimaSSHPrivateKeyPath(), diskManager),
 		newSettingsVMCommand(logger, lca, fs, os.Stdout),
-		newDiskVMCommand(limaCmdCreator, logger),
+		newDiskVMCommand(limaCmdCreator, logger, fs),
 	)
 
 	return virtualMachineCommand
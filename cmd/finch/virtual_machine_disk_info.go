@@ -6,37 +6,74 @@
 package main
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
 	"strings"
 
+	"github.com/lima-vm/lima/pkg/store/filenames"
+	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 
 	"github.com/runfinch/finch/pkg/command"
 	"github.com/runfinch/finch/pkg/flog"
+	"github.com/runfinch/finch/pkg/templates"
 )
 
-func newVMDiskInfoCommand(creator command.NerdctlCmdCreator, logger flog.Logger) *cobra.Command {
+func newVMDiskInfoCommand(creator command.NerdctlCmdCreator, logger flog.Logger, fs afero.Fs) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "info",
 		Short: "Display information about the virtual machine disk",
-		RunE:  newDiskInfoAction(creator, logger).runAdapter,
+		Long: "Display information about the virtual machine disk. With --format, the space used on the host " +
+			"by the disk is available as well, e.g. finch vm disk info --format '{{.Name}} {{.Size}} {{.Usage}}'.",
+		RunE: newDiskInfoAction(creator, logger, fs, os.Stdout).runAdapter,
 	}
+	addFormatFlag(cmd, "")
 	return cmd
 }
 
+// vmDiskOutput is the output of finch vm disk info when --format is set, as reported by limactl disk ls --json.
+// Its keys are capitalized like the ones of finch vm status, and still decode the lowerCamel keys of limactl,
+// as JSON keys are matched case-insensitively.
+type vmDiskOutput struct {
+	Name        string `json:"Name"`
+	Size        int64  `json:"Size"`
+	Format      string `json:"Format"`
+	Dir         string `json:"Dir"`
+	Instance    string `json:"Instance"`
+	InstanceDir string `json:"InstanceDir"`
+	MountPoint  string `json:"MountPoint"`
+	// Usage is the space used on the host by the disk in bytes, which is less than its size as the disk is sparse.
+	Usage int64 `json:"Usage"`
+}
+
 type diskInfoAction struct {
 	creator command.NerdctlCmdCreator
 	logger  flog.Logger
+	fs      afero.Fs
+	stdout  io.Writer
 }
 
-func newDiskInfoAction(creator command.NerdctlCmdCreator, logger flog.Logger) *diskInfoAction {
+func newDiskInfoAction(creator command.NerdctlCmdCreator, logger flog.Logger, fs afero.Fs, stdout io.Writer) *diskInfoAction {
 	return &diskInfoAction{
 		creator: creator,
 		logger:  logger,
+		fs:      fs,
+		stdout:  stdout,
 	}
 }
 
-func (dia *diskInfoAction) runAdapter(_ *cobra.Command, _ []string) error {
+func (dia *diskInfoAction) runAdapter(cmd *cobra.Command, _ []string) error {
+	format, err := getFormatFlag(cmd)
+	if err != nil {
+		return err
+	}
+	if format != "" {
+		return dia.runWithFormat(format)
+	}
 	return dia.run()
 }
 
@@ -51,6 +88,40 @@ func (dia *diskInfoAction) run() error {
 		return fmt.Errorf("no disk information found for virtual machine %q", limaInstanceName)
 	}
 
-	fmt.Print(string(output))
-	return nil
+	_, err = fmt.Fprint(dia.stdout, string(output))
+	return err
+}
+
+func (dia *diskInfoAction) runWithFormat(format string) error {
+	tmpl, err := templates.Parse("disk", format, "")
+	if err != nil {
+		return err
+	}
+
+	limaCmd := dia.creator.CreateWithoutStdio("disk", "ls", "--json", limaInstanceName)
+	output, err := limaCmd.Output()
+	if err != nil {
+		return fmt.Errorf("failed to get disk information: %w", err)
+	}
+
+	// limactl prints one JSON object per disk
+	var disks []any
+	dec := json.NewDecoder(bytes.NewReader(output))
+	for dec.More() {
+		var disk vmDiskOutput
+		if err := dec.Decode(&disk); err != nil {
+			return fmt.Errorf("failed to JSON-unmarshal the disk information: %w", err)
+		}
+		if fi, err := dia.fs.Stat(filepath.Join(disk.Dir, filenames.DataDisk)); err == nil {
+			disk.Usage = allocatedSize(fi)
+		} else {
+			dia.logger.Debugf("Failed to read the usage of disk %q: %v", disk.Name, err)
+		}
+		disks = append(disks, disk)
+	}
+	if len(disks) == 0 {
+		return fmt.Errorf("no disk information found for virtual machine %q", limaInstanceName)
+	}
+
+	return tmpl.Execute(dia.stdout, disks...)
 }
@@ -0,0 +1,99 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build darwin
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/runfinch/finch/pkg/mocks"
+)
+
+func TestDiskInfoAction_run(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name       string
+		format     string
+		wantErr    error
+		wantOutput string
+		mockSvc    func(*mocks.NerdctlCmdCreator, *mocks.Logger, afero.Fs, *gomock.Controller)
+	}{
+		{
+			name:       "prints the output of limactl disk ls",
+			wantOutput: "NAME     SIZE       FORMAT    LOCATION\nfinch    50GiB      raw       /lima/_disks/finch\n",
+			mockSvc: func(ncc *mocks.NerdctlCmdCreator, _ *mocks.Logger, _ afero.Fs, ctrl *gomock.Controller) {
+				cmd := mocks.NewCommand(ctrl)
+				ncc.EXPECT().CreateWithoutStdio("disk", "ls", limaInstanceName).Return(cmd)
+				cmd.EXPECT().CombinedOutput().Return(
+					[]byte("NAME     SIZE       FORMAT    LOCATION\nfinch    50GiB      raw       /lima/_disks/finch\n"), nil)
+			},
+		},
+		{
+			name:   "json format with the usage of the disk",
+			format: "json",
+			wantOutput: `{"Name":"finch","Size":53687091200,"Format":"raw","Dir":"/lima/_disks/finch","Instance":"finch",` +
+				`"InstanceDir":"/lima/finch","MountPoint":"/mnt/lima-finch","Usage":4096}` + "\n",
+			mockSvc: func(ncc *mocks.NerdctlCmdCreator, _ *mocks.Logger, fs afero.Fs, ctrl *gomock.Controller) {
+				cmd := mocks.NewCommand(ctrl)
+				ncc.EXPECT().CreateWithoutStdio("disk", "ls", "--json", limaInstanceName).Return(cmd)
+				cmd.EXPECT().Output().Return([]byte(`{"name":"finch","size":53687091200,"format":"raw",`+
+					`"dir":"/lima/_disks/finch","instance":"finch","instanceDir":"/lima/finch","mountPoint":"/mnt/lima-finch"}`+"\n"), nil)
+				require.NoError(t, afero.WriteFile(fs, "/lima/_disks/finch/datadisk", make([]byte, 4096), 0o644))
+			},
+		},
+		{
+			name:       "template format without the disk file",
+			format:     "{{.Name}} {{.Usage}}",
+			wantOutput: "finch 0\n",
+			mockSvc: func(ncc *mocks.NerdctlCmdCreator, logger *mocks.Logger, _ afero.Fs, ctrl *gomock.Controller) {
+				cmd := mocks.NewCommand(ctrl)
+				ncc.EXPECT().CreateWithoutStdio("disk", "ls", "--json", limaInstanceName).Return(cmd)
+				cmd.EXPECT().Output().Return([]byte(`{"name":"finch","dir":"/lima/_disks/finch"}`), nil)
+				logger.EXPECT().Debugf("Failed to read the usage of disk %q: %v", "finch", gomock.Any())
+			},
+		},
+		{
+			name:    "no disk",
+			format:  "json",
+			wantErr: errors.New(`no disk information found for virtual machine "finch"`),
+			mockSvc: func(ncc *mocks.NerdctlCmdCreator, _ *mocks.Logger, _ afero.Fs, ctrl *gomock.Controller) {
+				cmd := mocks.NewCommand(ctrl)
+				ncc.EXPECT().CreateWithoutStdio("disk", "ls", "--json", limaInstanceName).Return(cmd)
+				cmd.EXPECT().Output().Return([]byte(""), nil)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			ncc := mocks.NewNerdctlCmdCreator(ctrl)
+			logger := mocks.NewLogger(ctrl)
+			fs := afero.NewMemMapFs()
+			stdout := bytes.Buffer{}
+			tc.mockSvc(ncc, logger, fs, ctrl)
+
+			dia := newDiskInfoAction(ncc, logger, fs, &stdout)
+			var err error
+			if tc.format == "" {
+				err = dia.run()
+			} else {
+				err = dia.runWithFormat(tc.format)
+			}
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantOutput, stdout.String())
+		})
+	}
+}
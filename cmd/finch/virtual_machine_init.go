@@ -107,6 +107,7 @@ func (iva *initVMAction) run() error {
 
 	iva.logger.Info("Initializing and starting Finch virtual machine...")
 	logs, err := limaCmd.CombinedOutput()
+	lima.InvalidateCache(limaInstanceName)
 	if err != nil {
 		iva.logger.SetFormatter(flog.TextWithoutTruncation)
 		iva.logger.Errorf("Finch virtual machine failed to start, debug logs:\n%s", logs)
@@ -139,6 +140,6 @@ func (iva *initVMAction) assertVMIsNonexistent() error {
 	case lima.Running:
 		return fmt.Errorf("the instance %q is already running", limaInstanceName)
 	default:
-		return nil
+		return unhealthyVMError(status)
 	}
 }
@@ -74,7 +74,7 @@ func (rva *removeVMAction) assertVMIsStopped(creator command.NerdctlCmdCreator,
 		return fmt.Errorf("the instance %q is running, run `finch %s stop` to stop the instance first",
 			limaInstanceName, virtualMachineRootCmd)
 	default:
-		return nil
+		return unhealthyVMError(status)
 	}
 }
 
@@ -98,6 +98,7 @@ func (rva *removeVMAction) removeVM(force bool) error {
 		rva.logger.Info("Removing existing Finch virtual machine...")
 	}
 	logs, err := limaCmd.CombinedOutput()
+	lima.InvalidateCache(limaInstanceName)
 	if err != nil {
 		rva.logger.Errorf("Finch virtual machine failed to remove, debug logs:\n%s", logs)
 		return err
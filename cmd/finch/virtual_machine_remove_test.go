@@ -132,8 +132,9 @@ func TestRemoveVMAction_run(t *testing.T) {
 			force: false,
 		},
 		{
-			name:    "unknown VM status",
-			wantErr: errors.New("unrecognized system status"),
+			name: "broken VM",
+			wantErr: errors.New("the instance \"finch\" is broken, run `finch vm status` to see why, " +
+				"then `finch vm stop --force` to stop it, or `finch vm remove --force` to remove it"),
 			mockSvc: func(logger *mocks.Logger, creator *mocks.NerdctlCmdCreator, _ *mocks.UserDataDiskManager, ctrl *gomock.Controller) {
 				getVMStatusC := mocks.NewCommand(ctrl)
 				creator.EXPECT().CreateWithoutStdio("ls", "-f", "{{.Status}}", limaInstanceName).Return(getVMStatusC)
@@ -142,6 +143,17 @@ func TestRemoveVMAction_run(t *testing.T) {
 			},
 			force: false,
 		},
+		{
+			name:    "unknown VM status",
+			wantErr: errors.New("unrecognized system status"),
+			mockSvc: func(logger *mocks.Logger, creator *mocks.NerdctlCmdCreator, _ *mocks.UserDataDiskManager, ctrl *gomock.Controller) {
+				getVMStatusC := mocks.NewCommand(ctrl)
+				creator.EXPECT().CreateWithoutStdio("ls", "-f", "{{.Status}}", limaInstanceName).Return(getVMStatusC)
+				getVMStatusC.EXPECT().Output().Return([]byte("Suspended"), nil)
+				logger.EXPECT().Debugf("Status of virtual machine: %s", "Suspended")
+			},
+			force: false,
+		},
 		{
 			name:    "status command returns an error",
 			wantErr: errors.New("get status error"),
@@ -90,6 +90,7 @@ func (sva *startVMAction) run() error {
 	limaCmd := sva.creator.CreateWithoutStdio("start", limaInstanceName)
 	sva.logger.Info("Starting existing Finch virtual machine...")
 	logs, err := limaCmd.CombinedOutput()
+	lima.InvalidateCache(limaInstanceName)
 	if err != nil {
 		sva.logger.SetFormatter(flog.TextWithoutTruncation)
 		sva.logger.Errorf("Finch virtual machine failed to start, debug logs:\n%s", logs)
@@ -112,6 +113,6 @@ func (sva *startVMAction) assertVMIsStopped(creator command.NerdctlCmdCreator, l
 	case lima.Running:
 		return fmt.Errorf("the instance %q is already running", limaInstanceName)
 	default:
-		return nil
+		return unhealthyVMError(status)
 	}
 }
@@ -199,8 +199,9 @@ func TestStartVMAction_run(t *testing.T) {
 			},
 		},
 		{
-			name:    "unknown VM status",
-			wantErr: errors.New("unrecognized system status"),
+			name: "broken VM",
+			wantErr: errors.New("the instance \"finch\" is broken, run `finch vm status` to see why, " +
+				"then `finch vm stop --force` to stop it, or `finch vm remove --force` to remove it"),
 			groups: func(_ *gomock.Controller) []*dependency.Group {
 				return nil
 			},
@@ -217,6 +218,25 @@ func TestStartVMAction_run(t *testing.T) {
 				logger.EXPECT().Debugf("Status of virtual machine: %s", "Broken")
 			},
 		},
+		{
+			name:    "unknown VM status",
+			wantErr: errors.New("unrecognized system status"),
+			groups: func(_ *gomock.Controller) []*dependency.Group {
+				return nil
+			},
+			mockSvc: func(
+				ncc *mocks.NerdctlCmdCreator,
+				logger *mocks.Logger,
+				_ *mocks.LimaConfigApplier,
+				_ *mocks.UserDataDiskManager,
+				ctrl *gomock.Controller,
+			) {
+				getVMStatusC := mocks.NewCommand(ctrl)
+				ncc.EXPECT().CreateWithoutStdio("ls", "-f", "{{.Status}}", limaInstanceName).Return(getVMStatusC)
+				getVMStatusC.EXPECT().Output().Return([]byte("Suspended"), nil)
+				logger.EXPECT().Debugf("Status of virtual machine: %s", "Suspended")
+			},
+		},
 		{
 			name:    "status command returns an error",
 			wantErr: errors.New("get status error"),
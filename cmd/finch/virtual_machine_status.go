@@ -6,56 +6,181 @@
 package main
 
 import (
-	"fmt"
 	"io"
+	"strings"
+	"time"
 
 	"github.com/runfinch/finch/pkg/command"
+	"github.com/runfinch/finch/pkg/config"
 	"github.com/runfinch/finch/pkg/flog"
 	"github.com/runfinch/finch/pkg/lima"
+	"github.com/runfinch/finch/pkg/templates"
+	"github.com/runfinch/finch/pkg/version"
 
+	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 )
 
-func newStatusVMCommand(limaCmdCreator command.NerdctlCmdCreator, logger flog.Logger, stdout io.Writer) *cobra.Command {
+func newStatusVMCommand(
+	limaCmdCreator command.NerdctlCmdCreator,
+	logger flog.Logger,
+	fc *config.Finch,
+	fs afero.Fs,
+	stdout io.Writer,
+) *cobra.Command {
 	statusVMCommand := &cobra.Command{
 		Use:   "status",
 		Short: "Status of the virtual machine",
-		RunE:  newStatusVMAction(limaCmdCreator, logger, stdout).runAdapter,
+		Long: "Status of the virtual machine. With --format, the details of the virtual machine are available as well, " +
+			"e.g. finch vm status --format json, or finch vm status --format '{{.CPUs}} {{.Memory}}'.",
+		RunE: newStatusVMAction(limaCmdCreator, logger, fc, fs, stdout).runAdapter,
 	}
+	addFormatFlag(statusVMCommand, "")
 
 	return statusVMCommand
 }
 
+const defaultVMStatusFormat = "{{.Status}}"
+
+// vmStatusOutput is the output of finch vm status.
+// The fields following Hint are only set when --format is set, as reading them takes longer than reading the status.
+type vmStatusOutput struct {
+	Name   string `json:"Name"`
+	Status string `json:"Status"`
+	// Reasons are the errors reported by Lima for the instance, e.g. why it is broken.
+	Reasons []string `json:"Reasons,omitempty"`
+	// Hint is how to recover the instance, if it is broken for example.
+	Hint   string `json:"Hint,omitempty"`
+	VMType string `json:"VMType,omitempty"`
+	Arch   string `json:"Arch,omitempty"`
+	CPUs   int    `json:"CPUs,omitempty"`
+	// Memory is the memory of the virtual machine in bytes.
+	Memory int64 `json:"Memory,omitempty"`
+	// DiskSize is the size of the disk of the virtual machine in bytes.
+	DiskSize int64 `json:"DiskSize,omitempty"`
+	// DiskUsage is the space used on the host by the disk of the virtual machine in bytes.
+	DiskUsage int64 `json:"DiskUsage,omitempty"`
+	SSHPort   int   `json:"SSHPort,omitempty"`
+	// StartedAt and Uptime are only set if the virtual machine is running.
+	StartedAt    *time.Time `json:"StartedAt,omitempty"`
+	Uptime       string     `json:"Uptime,omitempty"`
+	Mounts       []vmMount  `json:"Mounts,omitempty"`
+	Rosetta      bool       `json:"Rosetta"`
+	FinchVersion string     `json:"FinchVersion"`
+	LimaVersion  string     `json:"LimaVersion,omitempty"`
+}
+
+// vmMount is a directory of the host mounted in the virtual machine.
+type vmMount struct {
+	Location   string `json:"Location"`
+	MountPoint string `json:"MountPoint"`
+	Writable   bool   `json:"Writable"`
+}
+
 type statusVMAction struct {
 	creator command.NerdctlCmdCreator
 	logger  flog.Logger
+	fc      *config.Finch
+	fs      afero.Fs
 	stdout  io.Writer
+	now     func() time.Time
 }
 
-func newStatusVMAction(creator command.NerdctlCmdCreator, logger flog.Logger, stdout io.Writer) *statusVMAction {
-	return &statusVMAction{creator: creator, logger: logger, stdout: stdout}
+func newStatusVMAction(
+	creator command.NerdctlCmdCreator,
+	logger flog.Logger,
+	fc *config.Finch,
+	fs afero.Fs,
+	stdout io.Writer,
+) *statusVMAction {
+	return &statusVMAction{creator: creator, logger: logger, fc: fc, fs: fs, stdout: stdout, now: time.Now}
 }
 
-func (sva *statusVMAction) runAdapter(_ *cobra.Command, _ []string) error {
-	return sva.run()
+func (sva *statusVMAction) runAdapter(cmd *cobra.Command, _ []string) error {
+	format, err := getFormatFlag(cmd)
+	if err != nil {
+		return err
+	}
+	return sva.run(format)
 }
 
-func (sva *statusVMAction) run() error {
-	status, err := lima.GetVMStatus(sva.creator, sva.logger, limaInstanceName)
+func (sva *statusVMAction) run(format string) error {
+	tmpl, err := templates.Parse("status", format, defaultVMStatusFormat)
 	if err != nil {
 		return err
 	}
-	switch status {
-	case lima.Running:
-		_, err = fmt.Fprintln(sva.stdout, "Running")
-		return err
-	case lima.Nonexistent:
-		_, err = fmt.Fprintln(sva.stdout, "Nonexistent")
+	state, err := lima.GetVMState(sva.creator, sva.logger, limaInstanceName)
+	if err != nil {
 		return err
-	case lima.Stopped:
-		_, err = fmt.Fprintln(sva.stdout, "Stopped")
+	}
+	output := vmStatusOutput{
+		Name:    limaInstanceName,
+		Status:  state.Status.String(),
+		Reasons: state.Reasons,
+		Hint:    vmStatusHint(state.Status),
+	}
+	if format != "" {
+		if err := sva.addDetails(&output, state.Status); err != nil {
+			return err
+		}
+	}
+	if len(output.Reasons) > 0 {
+		sva.logger.Warnf("The instance %q is %s: %s", limaInstanceName, output.Status, strings.Join(output.Reasons, "; "))
+	}
+	if output.Hint != "" {
+		sva.logger.Warnf("To recover the instance, %s", output.Hint)
+	}
+	return tmpl.Execute(sva.stdout, output)
+}
+
+// addDetails sets the details of the virtual machine, as reported by limactl ls --json,
+// or as configured in the finch config if the virtual machine does not exist.
+func (sva *statusVMAction) addDetails(output *vmStatusOutput, status lima.VMStatus) error {
+	output.FinchVersion = version.Version
+	output.LimaVersion = lima.LimaVersion
+	addConfiguredDetails(output, sva.fc)
+
+	inst, err := lima.GetInstance(sva.creator, limaInstanceName)
+	if err != nil || inst == nil {
 		return err
-	default:
-		return fmt.Errorf("instance state of %q is unknown", limaInstanceName)
 	}
+	output.VMType = string(inst.VMType)
+	output.Arch = inst.Arch
+	output.CPUs = inst.CPUs
+	output.Memory = inst.Memory
+	output.DiskSize = inst.Disk
+	output.SSHPort = inst.SSHLocalPort
+	if inst.LimaVersion != "" {
+		output.LimaVersion = inst.LimaVersion
+	}
+	if inst.Config != nil {
+		output.Mounts = nil
+		for _, m := range inst.Config.Mounts {
+			mount := vmMount{Location: m.Location, MountPoint: m.Location, Writable: m.Writable != nil && *m.Writable}
+			if m.MountPoint != nil {
+				mount.MountPoint = *m.MountPoint
+			}
+			output.Mounts = append(output.Mounts, mount)
+		}
+		output.Rosetta = inst.Config.Rosetta.Enabled != nil && *inst.Config.Rosetta.Enabled
+	}
+
+	output.DiskUsage = 0
+	for _, f := range inst.DiskFiles() {
+		if fi, err := sva.fs.Stat(f); err == nil {
+			output.DiskUsage += allocatedSize(fi)
+		}
+	}
+
+	// the PID file of the host agent is written when the virtual machine starts
+	if status == lima.Running {
+		if fi, err := sva.fs.Stat(inst.HostAgentPIDFile()); err == nil {
+			startedAt := fi.ModTime()
+			output.StartedAt = &startedAt
+			output.Uptime = sva.now().Sub(startedAt).Round(time.Second).String()
+		} else {
+			sva.logger.Debugf("Failed to read the start time of the virtual machine: %v", err)
+		}
+	}
+	return nil
 }
@@ -0,0 +1,47 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build darwin
+
+package main
+
+import (
+	"os"
+	"syscall"
+
+	"github.com/docker/go-units"
+
+	"github.com/runfinch/finch/pkg/config"
+)
+
+// addConfiguredDetails sets the details of the virtual machine configured in the finch config.
+func addConfiguredDetails(output *vmStatusOutput, fc *config.Finch) {
+	if fc == nil {
+		return
+	}
+	if fc.VMType != nil {
+		output.VMType = string(*fc.VMType)
+	}
+	if fc.CPUs != nil {
+		output.CPUs = *fc.CPUs
+	}
+	if fc.Memory != nil {
+		if memory, err := units.RAMInBytes(*fc.Memory); err == nil {
+			output.Memory = memory
+		}
+	}
+	for _, d := range fc.AdditionalDirectories {
+		if d.Path != nil {
+			output.Mounts = append(output.Mounts, vmMount{Location: *d.Path, MountPoint: *d.Path, Writable: true})
+		}
+	}
+	output.Rosetta = fc.Rosetta != nil && *fc.Rosetta
+}
+
+// allocatedSize returns the space used by the file, which is less than its size for the sparse disk images.
+func allocatedSize(fi os.FileInfo) int64 {
+	if st, ok := fi.Sys().(*syscall.Stat_t); ok {
+		return st.Blocks * 512
+	}
+	return fi.Size()
+}
@@ -9,18 +9,25 @@ import (
 	"bytes"
 	"errors"
 	"testing"
+	"time"
 
+	"github.com/lima-vm/lima/pkg/limayaml"
+	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 
+	"github.com/runfinch/finch/pkg/config"
 	"github.com/runfinch/finch/pkg/mocks"
 )
 
+const vmStateFormat = `{{.Status}}{{range .Errors}}{{"\n"}}{{.}}{{end}}`
+
 func TestNewStatusVMCommand(t *testing.T) {
 	t.Parallel()
 
-	cmd := newStatusVMCommand(nil, nil, nil)
+	cmd := newStatusVMCommand(nil, nil, nil, nil, nil)
 	assert.Equal(t, cmd.Name(), "status")
 }
 
@@ -40,9 +47,13 @@ func TestStatusVMAction_runAdapter(t *testing.T) {
 	}{
 		{
 			name: "should get nonexistent vm status",
-			command: &cobra.Command{
-				Use: "status",
-			},
+			command: func() *cobra.Command {
+				c := &cobra.Command{
+					Use: "status",
+				}
+				addFormatFlag(c, "")
+				return c
+			}(),
 			args: []string{},
 			mockSvc: func(
 				ncc *mocks.NerdctlCmdCreator,
@@ -51,7 +62,7 @@ func TestStatusVMAction_runAdapter(t *testing.T) {
 				ctrl *gomock.Controller,
 			) {
 				getVMStatusC := mocks.NewCommand(ctrl)
-				ncc.EXPECT().CreateWithoutStdio("ls", "-f", "{{.Status}}", limaInstanceName).Return(getVMStatusC)
+				ncc.EXPECT().CreateWithoutStdio("ls", "-f", vmStateFormat, limaInstanceName).Return(getVMStatusC)
 				getVMStatusC.EXPECT().Output().Return([]byte(""), nil)
 				logger.EXPECT().Debugf("Status of virtual machine: %s", "")
 			},
@@ -70,7 +81,7 @@ func TestStatusVMAction_runAdapter(t *testing.T) {
 
 			tc.mockSvc(ncc, logger, lca, ctrl)
 
-			assert.NoError(t, newStatusVMAction(ncc, logger, &stdout).runAdapter(tc.command, tc.args))
+			assert.NoError(t, newStatusVMAction(ncc, logger, nil, afero.NewMemMapFs(), &stdout).runAdapter(tc.command, tc.args))
 		})
 	}
 }
@@ -80,6 +91,7 @@ func TestStatusVMAction_run(t *testing.T) {
 
 	testCases := []struct {
 		name             string
+		format           string
 		wantErr          error
 		wantStatusOutput string
 		mockSvc          func(
@@ -100,11 +112,70 @@ func TestStatusVMAction_run(t *testing.T) {
 				ctrl *gomock.Controller,
 			) {
 				getVMStatusC := mocks.NewCommand(ctrl)
-				ncc.EXPECT().CreateWithoutStdio("ls", "-f", "{{.Status}}", limaInstanceName).Return(getVMStatusC)
+				ncc.EXPECT().CreateWithoutStdio("ls", "-f", vmStateFormat, limaInstanceName).Return(getVMStatusC)
 				getVMStatusC.EXPECT().Output().Return([]byte("Running"), nil)
 				logger.EXPECT().Debugf("Status of virtual machine: %s", "Running")
 			},
 		},
+		{
+			name:    "running VM with json format",
+			format:  "json",
+			wantErr: nil,
+			wantStatusOutput: `{"Name":"finch","Status":"Running","VMType":"vz","Arch":"aarch64","CPUs":4,"Memory":4294967296,` +
+				`"DiskSize":53687091200,"SSHPort":53412,"Mounts":[{"Location":"~","MountPoint":"~","Writable":true}],` +
+				`"Rosetta":true,"FinchVersion":"","LimaVersion":"1.2.2"}` + "\n",
+			mockSvc: func(
+				ncc *mocks.NerdctlCmdCreator,
+				logger *mocks.Logger,
+				_ *mocks.LimaConfigApplier,
+				ctrl *gomock.Controller,
+			) {
+				getVMStatusC := mocks.NewCommand(ctrl)
+				ncc.EXPECT().CreateWithoutStdio("ls", "-f", vmStateFormat, limaInstanceName).Return(getVMStatusC)
+				getVMStatusC.EXPECT().Output().Return([]byte("Running"), nil)
+				logger.EXPECT().Debugf("Status of virtual machine: %s", "Running")
+				getInstanceC := mocks.NewCommand(ctrl)
+				ncc.EXPECT().CreateWithoutStdio("ls", "--json", limaInstanceName).Return(getInstanceC)
+				getInstanceC.EXPECT().Output().Return([]byte(`{"name":"finch","status":"Running","dir":"/lima/finch",`+
+					`"vmType":"vz","arch":"aarch64","cpus":4,"memory":4294967296,"disk":53687091200,"sshLocalPort":53412,`+
+					`"hostAgentPID":123,"limaVersion":"1.2.2","config":{"mounts":[{"location":"~","writable":true}],`+
+					`"rosetta":{"enabled":true}}}`+"\n"), nil)
+				logger.EXPECT().Debugf("Failed to read the start time of the virtual machine: %v", gomock.Any())
+			},
+		},
+		{
+			name:             "running VM with table format",
+			format:           `table {{.Name}}\t{{.Status | lower}}`,
+			wantErr:          nil,
+			wantStatusOutput: "NAME      STATUS\nfinch     running\n",
+			mockSvc: func(
+				ncc *mocks.NerdctlCmdCreator,
+				logger *mocks.Logger,
+				_ *mocks.LimaConfigApplier,
+				ctrl *gomock.Controller,
+			) {
+				getVMStatusC := mocks.NewCommand(ctrl)
+				ncc.EXPECT().CreateWithoutStdio("ls", "-f", vmStateFormat, limaInstanceName).Return(getVMStatusC)
+				getVMStatusC.EXPECT().Output().Return([]byte("Running"), nil)
+				logger.EXPECT().Debugf("Status of virtual machine: %s", "Running")
+				getInstanceC := mocks.NewCommand(ctrl)
+				ncc.EXPECT().CreateWithoutStdio("ls", "--json", limaInstanceName).Return(getInstanceC)
+				getInstanceC.EXPECT().Output().Return([]byte(`{"name":"finch","status":"Running"}`), nil)
+				logger.EXPECT().Debugf("Failed to read the start time of the virtual machine: %v", gomock.Any())
+			},
+		},
+		{
+			name:    "invalid format",
+			format:  "{{.Status",
+			wantErr: errors.New(`template: status:1: unclosed action`),
+			mockSvc: func(
+				_ *mocks.NerdctlCmdCreator,
+				_ *mocks.Logger,
+				_ *mocks.LimaConfigApplier,
+				_ *gomock.Controller,
+			) {
+			},
+		},
 		{
 			name:             "stopped VM",
 			wantErr:          nil,
@@ -116,7 +187,7 @@ func TestStatusVMAction_run(t *testing.T) {
 				ctrl *gomock.Controller,
 			) {
 				getVMStatusC := mocks.NewCommand(ctrl)
-				ncc.EXPECT().CreateWithoutStdio("ls", "-f", "{{.Status}}", limaInstanceName).Return(getVMStatusC)
+				ncc.EXPECT().CreateWithoutStdio("ls", "-f", vmStateFormat, limaInstanceName).Return(getVMStatusC)
 				getVMStatusC.EXPECT().Output().Return([]byte("Stopped"), nil)
 				logger.EXPECT().Debugf("Status of virtual machine: %s", "Stopped")
 			},
@@ -132,15 +203,37 @@ func TestStatusVMAction_run(t *testing.T) {
 				ctrl *gomock.Controller,
 			) {
 				getVMStatusC := mocks.NewCommand(ctrl)
-				ncc.EXPECT().CreateWithoutStdio("ls", "-f", "{{.Status}}", limaInstanceName).Return(getVMStatusC)
+				ncc.EXPECT().CreateWithoutStdio("ls", "-f", vmStateFormat, limaInstanceName).Return(getVMStatusC)
 				getVMStatusC.EXPECT().Output().Return([]byte(""), nil)
 				logger.EXPECT().Debugf("Status of virtual machine: %s", "")
 			},
 		},
 		{
-			name:             "unknown VM status",
-			wantErr:          errors.New("unrecognized system status"),
-			wantStatusOutput: "",
+			name:             "broken VM",
+			wantErr:          nil,
+			wantStatusOutput: "Broken\n",
+			mockSvc: func(
+				ncc *mocks.NerdctlCmdCreator,
+				logger *mocks.Logger,
+				_ *mocks.LimaConfigApplier,
+				ctrl *gomock.Controller,
+			) {
+				getVMStatusC := mocks.NewCommand(ctrl)
+				ncc.EXPECT().CreateWithoutStdio("ls", "-f", vmStateFormat, limaInstanceName).Return(getVMStatusC)
+				getVMStatusC.EXPECT().Output().Return([]byte("Broken\nhost agent is running but driver is not"), nil)
+				logger.EXPECT().Debugf("Status of virtual machine: %s", "Broken")
+				logger.EXPECT().Warnf("The instance %q is %s: %s", limaInstanceName, "Broken",
+					"host agent is running but driver is not")
+				logger.EXPECT().Warnf("To recover the instance, %s", "run `finch vm status` to see why, "+
+					"then `finch vm stop --force` to stop it, or `finch vm remove --force` to remove it")
+			},
+		},
+		{
+			name:   "broken VM with json format",
+			format: "json",
+			wantStatusOutput: `{"Name":"finch","Status":"Broken","Reasons":["host agent is running but driver is not"],` +
+				`"Hint":"run ` + "`finch vm status`" + ` to see why, then ` + "`finch vm stop --force`" + ` to stop it, ` +
+				`or ` + "`finch vm remove --force`" + ` to remove it","VMType":"vz","Rosetta":false,"FinchVersion":""}` + "\n",
 			mockSvc: func(
 				ncc *mocks.NerdctlCmdCreator,
 				logger *mocks.Logger,
@@ -148,9 +241,48 @@ func TestStatusVMAction_run(t *testing.T) {
 				ctrl *gomock.Controller,
 			) {
 				getVMStatusC := mocks.NewCommand(ctrl)
-				ncc.EXPECT().CreateWithoutStdio("ls", "-f", "{{.Status}}", limaInstanceName).Return(getVMStatusC)
-				getVMStatusC.EXPECT().Output().Return([]byte("Broken"), nil)
+				ncc.EXPECT().CreateWithoutStdio("ls", "-f", vmStateFormat, limaInstanceName).Return(getVMStatusC)
+				getVMStatusC.EXPECT().Output().Return([]byte("Broken\nhost agent is running but driver is not"), nil)
 				logger.EXPECT().Debugf("Status of virtual machine: %s", "Broken")
+				logger.EXPECT().Warnf(gomock.Any(), gomock.Any()).Times(2)
+				getInstanceC := mocks.NewCommand(ctrl)
+				ncc.EXPECT().CreateWithoutStdio("ls", "--json", limaInstanceName).Return(getInstanceC)
+				getInstanceC.EXPECT().Output().Return([]byte(`{"name":"finch","status":"Broken","dir":"/lima/finch","vmType":"vz"}`), nil)
+			},
+		},
+		{
+			name:             "installing VM",
+			wantErr:          nil,
+			wantStatusOutput: "Installing\n",
+			mockSvc: func(
+				ncc *mocks.NerdctlCmdCreator,
+				logger *mocks.Logger,
+				_ *mocks.LimaConfigApplier,
+				ctrl *gomock.Controller,
+			) {
+				getVMStatusC := mocks.NewCommand(ctrl)
+				ncc.EXPECT().CreateWithoutStdio("ls", "-f", vmStateFormat, limaInstanceName).Return(getVMStatusC)
+				getVMStatusC.EXPECT().Output().Return([]byte("Installing"), nil)
+				logger.EXPECT().Debugf("Status of virtual machine: %s", "Installing")
+				logger.EXPECT().Warnf("To recover the instance, %s",
+					"wait for the installation to complete, or run `finch vm remove --force` to remove it")
+			},
+		},
+		{
+			name:             "unknown VM status",
+			wantErr:          nil,
+			wantStatusOutput: "Unknown\n",
+			mockSvc: func(
+				ncc *mocks.NerdctlCmdCreator,
+				logger *mocks.Logger,
+				_ *mocks.LimaConfigApplier,
+				ctrl *gomock.Controller,
+			) {
+				getVMStatusC := mocks.NewCommand(ctrl)
+				ncc.EXPECT().CreateWithoutStdio("ls", "-f", vmStateFormat, limaInstanceName).Return(getVMStatusC)
+				getVMStatusC.EXPECT().Output().Return([]byte("Suspended"), nil)
+				logger.EXPECT().Debugf("Status of virtual machine: %s", "Suspended")
+				logger.EXPECT().Warnf("The instance %q is %s: %s", limaInstanceName, "Unknown", `unrecognized status "Suspended"`)
 			},
 		},
 		{
@@ -164,7 +296,7 @@ func TestStatusVMAction_run(t *testing.T) {
 				ctrl *gomock.Controller,
 			) {
 				getVMStatusC := mocks.NewCommand(ctrl)
-				ncc.EXPECT().CreateWithoutStdio("ls", "-f", "{{.Status}}", limaInstanceName).Return(getVMStatusC)
+				ncc.EXPECT().CreateWithoutStdio("ls", "-f", vmStateFormat, limaInstanceName).Return(getVMStatusC)
 				getVMStatusC.EXPECT().Output().Return([]byte("Broken"), errors.New("get status error"))
 			},
 		},
@@ -182,8 +314,97 @@ func TestStatusVMAction_run(t *testing.T) {
 
 			tc.mockSvc(ncc, logger, lca, ctrl)
 
-			err := newStatusVMAction(ncc, logger, &stdout).run()
-			assert.Equal(t, err, tc.wantErr)
+			err := newStatusVMAction(ncc, logger, nil, afero.NewMemMapFs(), &stdout).run(tc.format)
+			if tc.wantErr != nil {
+				assert.EqualError(t, err, tc.wantErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.wantStatusOutput, stdout.String())
+		})
+	}
+}
+
+func TestStatusVMAction_run_details(t *testing.T) {
+	t.Parallel()
+
+	startedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	vz := limayaml.VZ
+	testCases := []struct {
+		name             string
+		format           string
+		fc               *config.Finch
+		wantStatusOutput string
+		mockSvc          func(*mocks.NerdctlCmdCreator, *mocks.Logger, afero.Fs, *gomock.Controller)
+	}{
+		{
+			name:             "uptime and disk usage of a running VM",
+			format:           `{{.StartedAt.Format "15:04:05"}} {{.Uptime}} {{.DiskUsage}}`,
+			wantStatusOutput: "03:04:05 1h30m0s 3072\n",
+			mockSvc: func(ncc *mocks.NerdctlCmdCreator, logger *mocks.Logger, fs afero.Fs, ctrl *gomock.Controller) {
+				getVMStatusC := mocks.NewCommand(ctrl)
+				ncc.EXPECT().CreateWithoutStdio("ls", "-f", vmStateFormat, limaInstanceName).Return(getVMStatusC)
+				getVMStatusC.EXPECT().Output().Return([]byte("Running"), nil)
+				logger.EXPECT().Debugf("Status of virtual machine: %s", "Running")
+				getInstanceC := mocks.NewCommand(ctrl)
+				ncc.EXPECT().CreateWithoutStdio("ls", "--json", limaInstanceName).Return(getInstanceC)
+				getInstanceC.EXPECT().Output().Return([]byte(`{"name":"finch","status":"Running","dir":"/lima/finch"}`), nil)
+
+				require.NoError(t, afero.WriteFile(fs, "/lima/finch/ha.pid", []byte("123"), 0o644))
+				require.NoError(t, fs.Chtimes("/lima/finch/ha.pid", startedAt, startedAt))
+				require.NoError(t, afero.WriteFile(fs, "/lima/finch/basedisk", make([]byte, 1024), 0o644))
+				require.NoError(t, afero.WriteFile(fs, "/lima/finch/diffdisk", make([]byte, 2048), 0o644))
+			},
+		},
+		{
+			name:             "stopped VM has no uptime",
+			format:           `{{.Status}} {{.Uptime}}`,
+			wantStatusOutput: "Stopped \n",
+			mockSvc: func(ncc *mocks.NerdctlCmdCreator, logger *mocks.Logger, fs afero.Fs, ctrl *gomock.Controller) {
+				getVMStatusC := mocks.NewCommand(ctrl)
+				ncc.EXPECT().CreateWithoutStdio("ls", "-f", vmStateFormat, limaInstanceName).Return(getVMStatusC)
+				getVMStatusC.EXPECT().Output().Return([]byte("Stopped"), nil)
+				logger.EXPECT().Debugf("Status of virtual machine: %s", "Stopped")
+				getInstanceC := mocks.NewCommand(ctrl)
+				ncc.EXPECT().CreateWithoutStdio("ls", "--json", limaInstanceName).Return(getInstanceC)
+				getInstanceC.EXPECT().Output().Return([]byte(`{"name":"finch","status":"Stopped","dir":"/lima/finch"}`), nil)
+
+				require.NoError(t, afero.WriteFile(fs, "/lima/finch/ha.pid", []byte("123"), 0o644))
+			},
+		},
+		{
+			name:   "nonexistent VM is described by the finch config",
+			format: "{{.Status}} {{.VMType}}",
+			fc: &config.Finch{
+				SystemSettings: config.SystemSettings{SharedSystemSettings: config.SharedSystemSettings{VMType: &vz}},
+			},
+			wantStatusOutput: "Nonexistent vz\n",
+			mockSvc: func(ncc *mocks.NerdctlCmdCreator, logger *mocks.Logger, _ afero.Fs, ctrl *gomock.Controller) {
+				getVMStatusC := mocks.NewCommand(ctrl)
+				ncc.EXPECT().CreateWithoutStdio("ls", "-f", vmStateFormat, limaInstanceName).Return(getVMStatusC)
+				getVMStatusC.EXPECT().Output().Return([]byte(""), nil)
+				logger.EXPECT().Debugf("Status of virtual machine: %s", "")
+				getInstanceC := mocks.NewCommand(ctrl)
+				ncc.EXPECT().CreateWithoutStdio("ls", "--json", limaInstanceName).Return(getInstanceC)
+				getInstanceC.EXPECT().Output().Return([]byte(""), nil)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			logger := mocks.NewLogger(ctrl)
+			ncc := mocks.NewNerdctlCmdCreator(ctrl)
+			fs := afero.NewMemMapFs()
+			stdout := bytes.Buffer{}
+			tc.mockSvc(ncc, logger, fs, ctrl)
+
+			sva := newStatusVMAction(ncc, logger, tc.fc, fs, &stdout)
+			sva.now = func() time.Time { return startedAt.Add(90 * time.Minute) }
+			require.NoError(t, sva.run(tc.format))
 			assert.Equal(t, tc.wantStatusOutput, stdout.String())
 		})
 	}
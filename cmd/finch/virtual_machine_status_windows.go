@@ -0,0 +1,25 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build windows
+
+package main
+
+import (
+	"os"
+
+	"github.com/runfinch/finch/pkg/config"
+)
+
+// addConfiguredDetails sets the details of the virtual machine configured in the finch config.
+func addConfiguredDetails(output *vmStatusOutput, fc *config.Finch) {
+	if fc != nil && fc.VMType != nil {
+		output.VMType = string(*fc.VMType)
+	}
+}
+
+// allocatedSize returns the space used by the file. The disk of WSL distributions is not in the instance
+// directory, so only the size of the files is reported.
+func allocatedSize(fi os.FileInfo) int64 {
+	return fi.Size()
+}
@@ -73,7 +73,7 @@ func (sva *stopVMAction) assertVMIsRunning(creator command.NerdctlCmdCreator, lo
 	case lima.Stopped:
 		return fmt.Errorf("the instance %q is already stopped", limaInstanceName)
 	default:
-		return nil
+		return unhealthyVMError(status)
 	}
 }
 
@@ -100,6 +100,7 @@ func (sva *stopVMAction) stopVM(force bool) error {
 	_ = sva.diskManager.DetachUserDataDisk()
 
 	logs, err := limaCmd.CombinedOutput()
+	lima.InvalidateCache(limaInstanceName)
 	if err != nil {
 		sva.logger.Errorf("Finch virtual machine failed to stop, debug logs:\n%s", logs)
 		return err
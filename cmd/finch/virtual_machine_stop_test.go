@@ -144,8 +144,9 @@ func TestStopVMAction_run(t *testing.T) {
 			force: false,
 		},
 		{
-			name:    "unknown VM status",
-			wantErr: errors.New("unrecognized system status"),
+			name: "broken VM",
+			wantErr: errors.New("the instance \"finch\" is broken, run `finch vm status` to see why, " +
+				"then `finch vm stop --force` to stop it, or `finch vm remove --force` to remove it"),
 			mockSvc: func(logger *mocks.Logger, creator *mocks.NerdctlCmdCreator, ctrl *gomock.Controller, _ *mocks.UserDataDiskManager) {
 				getVMStatusC := mocks.NewCommand(ctrl)
 				creator.EXPECT().CreateWithoutStdio("ls", "-f", "{{.Status}}", limaInstanceName).Return(getVMStatusC)
@@ -154,6 +155,17 @@ func TestStopVMAction_run(t *testing.T) {
 			},
 			force: false,
 		},
+		{
+			name:    "unknown VM status",
+			wantErr: errors.New("unrecognized system status"),
+			mockSvc: func(logger *mocks.Logger, creator *mocks.NerdctlCmdCreator, ctrl *gomock.Controller, _ *mocks.UserDataDiskManager) {
+				getVMStatusC := mocks.NewCommand(ctrl)
+				creator.EXPECT().CreateWithoutStdio("ls", "-f", "{{.Status}}", limaInstanceName).Return(getVMStatusC)
+				getVMStatusC.EXPECT().Output().Return([]byte("Suspended"), nil)
+				logger.EXPECT().Debugf("Status of virtual machine: %s", "Suspended")
+			},
+			force: false,
+		},
 		{
 			name:    "status command returns an error",
 			wantErr: errors.New("get status error"),
@@ -25,6 +25,7 @@ func newVirtualMachineCommand(
 	optionalDepGroups []*dependency.Group,
 	lca config.LimaConfigApplier,
 	nca config.NerdctlConfigApplier,
+	fc *config.Finch,
 	fp path.Finch,
 	fs afero.Fs,
 	diskManager disk.UserDataDiskManager,
@@ -38,7 +39,7 @@ func newVirtualMachineCommand(
 		newStartVMCommand(limaCmdCreator, logger, optionalDepGroups, lca, nca, fs, fp.LimaSSHPrivateKeyPath(), diskManager),
 		newStopVMCommand(limaCmdCreator, diskManager, logger),
 		newRemoveVMCommand(limaCmdCreator, diskManager, logger),
-		newStatusVMCommand(limaCmdCreator, logger, os.Stdout),
+		newStatusVMCommand(limaCmdCreator, logger, fc, fs, os.Stdout),
 		newInitVMCommand(limaCmdCreator, logger, optionalDepGroups, lca, nca, fp.BaseYamlFilePath(), fs,
 			fp.LimaSSHPrivateKeyPath(), diskManager),
 		newSettingsVMCommand(logger, lca, fs, os.Stdout),
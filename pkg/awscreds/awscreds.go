@@ -0,0 +1,235 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package awscreds resolves AWS credentials on the host so that they can be passed to credential helpers
+// running elsewhere, e.g. ecr-login inside the VM.
+package awscreds
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/ssocreds"
+	"github.com/spf13/afero"
+
+	"github.com/runfinch/finch/pkg/flog"
+	"github.com/runfinch/finch/pkg/system"
+)
+
+// expiryWindow is how long before their expiry cached credentials are considered stale,
+// so that they don't expire while the command using them is running.
+const expiryWindow = 5 * time.Minute
+
+// defaultProfile is the profile used by the SDK when AWS_PROFILE is not set.
+const defaultProfile = "default"
+
+// chainEnv are the environment variables read by the default credential chain of the SDK.
+// Cached credentials are only used while they are unchanged, as they may select other credentials.
+var chainEnv = []string{
+	"AWS_PROFILE",
+	"AWS_ACCESS_KEY_ID",
+	"AWS_SECRET_ACCESS_KEY",
+	"AWS_SESSION_TOKEN",
+	"AWS_REGION",
+	"AWS_DEFAULT_REGION",
+	"AWS_CONFIG_FILE",
+	"AWS_SHARED_CREDENTIALS_FILE",
+	"AWS_ROLE_ARN",
+	"AWS_ROLE_SESSION_NAME",
+	"AWS_WEB_IDENTITY_TOKEN_FILE",
+	"AWS_CONTAINER_CREDENTIALS_FULL_URI",
+	"AWS_CONTAINER_CREDENTIALS_RELATIVE_URI",
+	"AWS_CONTAINER_AUTHORIZATION_TOKEN",
+	"AWS_EC2_METADATA_DISABLED",
+}
+
+// LoadConfigFunc loads the AWS configuration. It has the same semantics as config.LoadDefaultConfig.
+type LoadConfigFunc func(ctx context.Context, optFns ...func(*awsconfig.LoadOptions) error) (aws.Config, error)
+
+// SystemDeps contains the system dependencies for Resolver.
+//
+//go:generate mockgen -copyright_file=../../copyright_header -destination=../mocks/pkg_awscreds_system_deps.go -package=mocks -mock_names SystemDeps=AWSCredsSystemDeps . SystemDeps
+type SystemDeps interface {
+	system.EnvGetter
+}
+
+// Resolver resolves AWS credentials with the default credential chain of the SDK,
+// which covers environment variables, shared config and credentials files, SSO, web identity and process providers,
+// honoring AWS_PROFILE and AWS_REGION.
+//
+// Credentials that expire are cached in a file until shortly before their expiry, or until the environment
+// or the shared config and credentials files change, so that providers like credential_process
+// or assume role with MFA are not invoked for every command. The region is never cached.
+type Resolver struct {
+	load       LoadConfigFunc
+	fs         afero.Fs
+	cachePath  string
+	systemDeps SystemDeps
+	logger     flog.Logger
+	now        func() time.Time
+}
+
+// NewResolver creates a new Resolver caching credentials at cachePath. Credentials are not cached if cachePath is empty.
+func NewResolver(load LoadConfigFunc, fs afero.Fs, cachePath string, systemDeps SystemDeps, logger flog.Logger) *Resolver {
+	return &Resolver{
+		load:       load,
+		fs:         fs,
+		cachePath:  cachePath,
+		systemDeps: systemDeps,
+		logger:     logger,
+		now:        time.Now,
+	}
+}
+
+// cacheEntry is the cached form of credentials, keyed by the hash of the inputs of the credential chain.
+type cacheEntry struct {
+	Credentials aws.Credentials `json:"credentials"`
+}
+
+// Env returns the environment variables that pass the resolved credentials and region to a process.
+func (r *Resolver) Env(ctx context.Context) ([]string, error) {
+	// the config is loaded every time, as loading it does not retrieve the credentials,
+	// so that the region is the current one
+	cfg, err := r.load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	creds, err := r.resolve(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	env := []string{
+		fmt.Sprintf("AWS_ACCESS_KEY_ID=%s", creds.AccessKeyID),
+		fmt.Sprintf("AWS_SECRET_ACCESS_KEY=%s", creds.SecretAccessKey),
+	}
+	if creds.SessionToken != "" {
+		env = append(env, fmt.Sprintf("AWS_SESSION_TOKEN=%s", creds.SessionToken))
+	}
+	if cfg.Region != "" {
+		env = append(env, fmt.Sprintf("AWS_REGION=%s", cfg.Region))
+	}
+	return env, nil
+}
+
+func (r *Resolver) resolve(ctx context.Context, cfg aws.Config) (aws.Credentials, error) {
+	profile := r.systemDeps.Env("AWS_PROFILE")
+	if profile == "" {
+		profile = defaultProfile
+	}
+
+	key := r.cacheKey()
+	cache := r.readCache()
+	if entry, ok := cache[key]; ok && !r.expired(entry.Credentials) {
+		r.logger.Debugf("Using cached AWS credentials for profile %q from %s, expiring at %s",
+			profile, entry.Credentials.Source, entry.Credentials.Expires.Format(time.RFC3339))
+		return entry.Credentials, nil
+	}
+
+	if cfg.Credentials == nil {
+		return aws.Credentials{}, errors.New("no AWS credentials provider found")
+	}
+	creds, err := cfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		return aws.Credentials{}, fmt.Errorf("failed to retrieve AWS credentials: %w", err)
+	}
+	r.logger.Debugf("Using AWS credentials for profile %q from %s", profile, creds.Source)
+
+	// Credentials that don't expire are cheap to load, and caching them would only copy long-lived secrets.
+	// SSO credentials are not cached either, as the SDK caches the SSO token they are retrieved with.
+	if creds.CanExpire && creds.Source != ssocreds.ProviderName {
+		cache[key] = cacheEntry{Credentials: creds}
+	} else {
+		delete(cache, key)
+	}
+	if err := r.writeCache(cache); err != nil {
+		r.logger.Debugf("Failed to cache AWS credentials: %v", err)
+	}
+	return creds, nil
+}
+
+// cacheKey returns the key of the cached credentials, which is the hash of the inputs of the credential chain:
+// its environment variables, and the modification times and sizes of the shared config and credentials files.
+func (r *Resolver) cacheKey() string {
+	inputs := struct {
+		Env   map[string]string    `json:"env"`
+		Files map[string]fileStamp `json:"files"`
+	}{Env: map[string]string{}, Files: map[string]fileStamp{}}
+	for _, name := range chainEnv {
+		inputs.Env[name] = r.systemDeps.Env(name)
+	}
+	configFile := cmp.Or(inputs.Env["AWS_CONFIG_FILE"], awsconfig.DefaultSharedConfigFilename())
+	credentialsFile := cmp.Or(inputs.Env["AWS_SHARED_CREDENTIALS_FILE"], awsconfig.DefaultSharedCredentialsFilename())
+	for _, file := range []string{configFile, credentialsFile} {
+		if info, err := r.fs.Stat(file); err == nil {
+			inputs.Files[file] = fileStamp{ModTime: info.ModTime().UnixNano(), Size: info.Size()}
+		}
+	}
+
+	// the inputs are hashed, as they hold the credentials of the environment
+	b, _ := json.Marshal(inputs)
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// fileStamp identifies the version of a file.
+type fileStamp struct {
+	ModTime int64 `json:"modTime"`
+	Size    int64 `json:"size"`
+}
+
+func (r *Resolver) expired(creds aws.Credentials) bool {
+	return !creds.CanExpire || !creds.Expires.After(r.now().Add(expiryWindow))
+}
+
+// readCache reads the credentials cache, ignoring a missing or malformed file.
+func (r *Resolver) readCache() map[string]cacheEntry {
+	cache := map[string]cacheEntry{}
+	if r.cachePath == "" {
+		return cache
+	}
+	b, err := afero.ReadFile(r.fs, r.cachePath)
+	if err != nil {
+		return cache
+	}
+	if err := json.Unmarshal(b, &cache); err != nil {
+		r.logger.Debugf("Ignoring malformed AWS credentials cache %q: %v", r.cachePath, err)
+		return map[string]cacheEntry{}
+	}
+	return cache
+}
+
+func (r *Resolver) writeCache(cache map[string]cacheEntry) error {
+	if r.cachePath == "" {
+		return nil
+	}
+	for key, entry := range cache {
+		if r.expired(entry.Credentials) {
+			delete(cache, key)
+		}
+	}
+	if len(cache) == 0 {
+		if err := r.fs.Remove(r.cachePath); err != nil && !errors.Is(err, afero.ErrFileNotFound) {
+			return err
+		}
+		return nil
+	}
+
+	b, err := json.Marshal(cache)
+	if err != nil {
+		return err
+	}
+	if err := afero.WriteFile(r.fs, r.cachePath, b, 0o600); err != nil {
+		return err
+	}
+	// the file may have been created with other permissions, which WriteFile keeps
+	return r.fs.Chmod(r.cachePath, 0o600)
+}
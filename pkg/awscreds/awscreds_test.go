@@ -0,0 +1,271 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package awscreds
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/runfinch/finch/pkg/mocks"
+)
+
+const cachePath = "/home/user/.finch/aws-credentials-cache.json"
+
+var now = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func loadConfig(creds aws.Credentials, region string, err error) LoadConfigFunc {
+	return func(_ context.Context, _ ...func(*awsconfig.LoadOptions) error) (aws.Config, error) {
+		if err != nil {
+			return aws.Config{}, err
+		}
+		return aws.Config{
+			Region: region,
+			Credentials: aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+				return creds, nil
+			}),
+		}, nil
+	}
+}
+
+// expectEnv sets the environment variables of the credential chain.
+func expectEnv(deps *mocks.AWSCredsSystemDeps, env map[string]string) {
+	deps.EXPECT().Env(gomock.Any()).DoAndReturn(func(name string) string {
+		return env[name]
+	}).AnyTimes()
+}
+
+func TestResolver_Env(t *testing.T) {
+	t.Parallel()
+
+	processCreds := aws.Credentials{
+		AccessKeyID:     "ASIAEXAMPLE",
+		SecretAccessKey: "secret",
+		SessionToken:    "token",
+		Source:          "ProcessProvider",
+		CanExpire:       true,
+		Expires:         now.Add(time.Hour),
+	}
+	cachedCreds := aws.Credentials{
+		AccessKeyID:     "ASIACACHED",
+		SecretAccessKey: "cached-secret",
+		SessionToken:    "cached-token",
+		Source:          "AssumeRoleProvider",
+		CanExpire:       true,
+		Expires:         now.Add(time.Hour),
+	}
+
+	testCases := []struct {
+		name    string
+		load    LoadConfigFunc
+		env     map[string]string
+		mockSvc func(t *testing.T, fs afero.Fs, key string, logger *mocks.Logger)
+		want    []string
+		wantErr string
+		// wantCache returns the expected cache, given the key of the credentials.
+		wantCache func(key string) map[string]cacheEntry
+	}{
+		{
+			name: "expiring credentials are resolved and cached",
+			load: loadConfig(processCreds, "us-west-2", nil),
+			env:  map[string]string{"AWS_PROFILE": "dev"},
+			mockSvc: func(_ *testing.T, _ afero.Fs, _ string, logger *mocks.Logger) {
+				logger.EXPECT().Debugf("Using AWS credentials for profile %q from %s", "dev", "ProcessProvider")
+			},
+			want: []string{
+				"AWS_ACCESS_KEY_ID=ASIAEXAMPLE",
+				"AWS_SECRET_ACCESS_KEY=secret",
+				"AWS_SESSION_TOKEN=token",
+				"AWS_REGION=us-west-2",
+			},
+			wantCache: func(key string) map[string]cacheEntry {
+				return map[string]cacheEntry{key: {Credentials: processCreds}}
+			},
+		},
+		{
+			name: "cached credentials are used until they expire, with the current region",
+			load: loadConfig(aws.Credentials{}, "eu-west-1", nil),
+			env:  map[string]string{"AWS_REGION": "eu-west-1"},
+			mockSvc: func(t *testing.T, fs afero.Fs, key string, logger *mocks.Logger) {
+				writeCache(t, fs, map[string]cacheEntry{key: {Credentials: cachedCreds}})
+				logger.EXPECT().Debugf("Using cached AWS credentials for profile %q from %s, expiring at %s",
+					defaultProfile, "AssumeRoleProvider", cachedCreds.Expires.Format(time.RFC3339))
+			},
+			want: []string{
+				"AWS_ACCESS_KEY_ID=ASIACACHED",
+				"AWS_SECRET_ACCESS_KEY=cached-secret",
+				"AWS_SESSION_TOKEN=cached-token",
+				"AWS_REGION=eu-west-1",
+			},
+			wantCache: func(key string) map[string]cacheEntry {
+				return map[string]cacheEntry{key: {Credentials: cachedCreds}}
+			},
+		},
+		{
+			name: "credentials cached for other inputs are not used",
+			load: loadConfig(processCreds, "", nil),
+			env:  map[string]string{"AWS_ACCESS_KEY_ID": "ASIAOTHER"},
+			mockSvc: func(t *testing.T, fs afero.Fs, _ string, logger *mocks.Logger) {
+				writeCache(t, fs, map[string]cacheEntry{"other": {Credentials: cachedCreds}})
+				logger.EXPECT().Debugf("Using AWS credentials for profile %q from %s", defaultProfile, "ProcessProvider")
+			},
+			want: []string{
+				"AWS_ACCESS_KEY_ID=ASIAEXAMPLE",
+				"AWS_SECRET_ACCESS_KEY=secret",
+				"AWS_SESSION_TOKEN=token",
+			},
+			wantCache: func(key string) map[string]cacheEntry {
+				return map[string]cacheEntry{"other": {Credentials: cachedCreds}, key: {Credentials: processCreds}}
+			},
+		},
+		{
+			name: "credentials about to expire are refreshed",
+			load: loadConfig(processCreds, "", nil),
+			mockSvc: func(t *testing.T, fs afero.Fs, key string, logger *mocks.Logger) {
+				stale := cachedCreds
+				stale.Expires = now.Add(time.Minute)
+				writeCache(t, fs, map[string]cacheEntry{key: {Credentials: stale}})
+				logger.EXPECT().Debugf("Using AWS credentials for profile %q from %s", defaultProfile, "ProcessProvider")
+			},
+			want: []string{
+				"AWS_ACCESS_KEY_ID=ASIAEXAMPLE",
+				"AWS_SECRET_ACCESS_KEY=secret",
+				"AWS_SESSION_TOKEN=token",
+			},
+			wantCache: func(key string) map[string]cacheEntry {
+				return map[string]cacheEntry{key: {Credentials: processCreds}}
+			},
+		},
+		{
+			name: "static credentials are not cached",
+			load: loadConfig(aws.Credentials{
+				AccessKeyID:     "AKIAEXAMPLE",
+				SecretAccessKey: "secret",
+				Source:          "SharedConfigCredentials",
+			}, "", nil),
+			mockSvc: func(_ *testing.T, _ afero.Fs, _ string, logger *mocks.Logger) {
+				logger.EXPECT().Debugf("Using AWS credentials for profile %q from %s", defaultProfile, "SharedConfigCredentials")
+			},
+			want: []string{
+				"AWS_ACCESS_KEY_ID=AKIAEXAMPLE",
+				"AWS_SECRET_ACCESS_KEY=secret",
+			},
+		},
+		{
+			name: "SSO credentials are not cached",
+			load: loadConfig(aws.Credentials{
+				AccessKeyID:     "ASIASSO",
+				SecretAccessKey: "secret",
+				SessionToken:    "token",
+				Source:          "SSOProvider",
+				CanExpire:       true,
+				Expires:         now.Add(time.Hour),
+			}, "", nil),
+			mockSvc: func(_ *testing.T, _ afero.Fs, _ string, logger *mocks.Logger) {
+				logger.EXPECT().Debugf("Using AWS credentials for profile %q from %s", defaultProfile, "SSOProvider")
+			},
+			want: []string{
+				"AWS_ACCESS_KEY_ID=ASIASSO",
+				"AWS_SECRET_ACCESS_KEY=secret",
+				"AWS_SESSION_TOKEN=token",
+			},
+		},
+		{
+			name: "malformed cache is ignored",
+			load: loadConfig(processCreds, "", nil),
+			mockSvc: func(t *testing.T, fs afero.Fs, _ string, logger *mocks.Logger) {
+				require.NoError(t, afero.WriteFile(fs, cachePath, []byte("{"), 0o600))
+				logger.EXPECT().Debugf("Ignoring malformed AWS credentials cache %q: %v", cachePath, gomock.Any())
+				logger.EXPECT().Debugf("Using AWS credentials for profile %q from %s", defaultProfile, "ProcessProvider")
+			},
+			want: []string{
+				"AWS_ACCESS_KEY_ID=ASIAEXAMPLE",
+				"AWS_SECRET_ACCESS_KEY=secret",
+				"AWS_SESSION_TOKEN=token",
+			},
+			wantCache: func(key string) map[string]cacheEntry {
+				return map[string]cacheEntry{key: {Credentials: processCreds}}
+			},
+		},
+		{
+			name:    "config fails to load",
+			load:    loadConfig(aws.Credentials{}, "", errors.New("failed to get shared config profile, dev")),
+			env:     map[string]string{"AWS_PROFILE": "dev"},
+			mockSvc: func(*testing.T, afero.Fs, string, *mocks.Logger) {},
+			wantErr: "failed to load AWS config: failed to get shared config profile, dev",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			fs := afero.NewMemMapFs()
+			deps := mocks.NewAWSCredsSystemDeps(ctrl)
+			logger := mocks.NewLogger(ctrl)
+			expectEnv(deps, tc.env)
+
+			r := NewResolver(tc.load, fs, cachePath, deps, logger)
+			r.now = func() time.Time { return now }
+			key := r.cacheKey()
+			tc.mockSvc(t, fs, key, logger)
+			got, err := r.Env(context.Background())
+			if tc.wantErr != "" {
+				assert.EqualError(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+
+			b, err := afero.ReadFile(fs, cachePath)
+			if tc.wantCache == nil {
+				assert.ErrorIs(t, err, afero.ErrFileNotFound)
+				return
+			}
+			require.NoError(t, err)
+			var cache map[string]cacheEntry
+			require.NoError(t, json.Unmarshal(b, &cache))
+			assert.Equal(t, tc.wantCache(key), cache)
+		})
+	}
+}
+
+func TestResolver_cacheKey(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	fs := afero.NewMemMapFs()
+	deps := mocks.NewAWSCredsSystemDeps(ctrl)
+	env := map[string]string{"AWS_PROFILE": "dev", "AWS_CONFIG_FILE": "/home/user/.aws/config"}
+	expectEnv(deps, env)
+	require.NoError(t, afero.WriteFile(fs, "/home/user/.aws/config", []byte("[profile dev]\n"), 0o600))
+	r := NewResolver(nil, fs, cachePath, deps, nil)
+
+	key := r.cacheKey()
+	assert.Equal(t, key, r.cacheKey(), "the key is stable")
+	assert.NotContains(t, key, "dev", "the inputs are hashed")
+
+	require.NoError(t, fs.Chtimes("/home/user/.aws/config", now, now))
+	changed := r.cacheKey()
+	assert.NotEqual(t, key, changed, "the key changes with the config file")
+
+	env["AWS_SECRET_ACCESS_KEY"] = "secret"
+	assert.NotEqual(t, changed, r.cacheKey(), "the key changes with the environment")
+}
+
+func writeCache(t *testing.T, fs afero.Fs, cache map[string]cacheEntry) {
+	b, err := json.Marshal(cache)
+	require.NoError(t, err)
+	require.NoError(t, afero.WriteFile(fs, cachePath, b, 0o600))
+}
@@ -4,7 +4,10 @@
 // Package command invokes external commands.
 package command
 
-import "io"
+import (
+	"io"
+	"os"
+)
 
 // Creator creates a Command. The semantics of the parameters are the same as those of exec.Command.
 //
@@ -31,4 +34,6 @@ type Command interface {
 	Wait() error
 	Output() ([]byte, error)
 	CombinedOutput() ([]byte, error)
+	// Signal sends a signal to the started command.
+	Signal(sig os.Signal) error
 }
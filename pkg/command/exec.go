@@ -4,7 +4,9 @@
 package command
 
 import (
+	"errors"
 	"io"
+	"os"
 	"os/exec"
 )
 
@@ -63,3 +65,10 @@ func (c *execCmd) SetStderr(stderr io.Writer) {
 func (c *execCmd) StdinPipe() (io.WriteCloser, error) {
 	return c.Cmd.StdinPipe()
 }
+
+func (c *execCmd) Signal(sig os.Signal) error {
+	if c.Process == nil {
+		return errors.New("the command has not been started")
+	}
+	return c.Process.Signal(sig)
+}
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"os/exec"
+	"syscall"
 )
 
 type exitError struct {
@@ -34,3 +35,36 @@ func (e *exitError) Error() string {
 func (e *exitError) Unwrap() error {
 	return e.wrapped
 }
+
+// ExitCodeError reports the exit code of a command whose stdio is connected to the stdio of the current process.
+// The command has already shown its errors to the user, so finch should exit with the same code without printing anything.
+type ExitCodeError struct {
+	code int
+}
+
+// NewExitCodeError creates a new ExitCodeError.
+func NewExitCodeError(code int) *ExitCodeError {
+	return &ExitCodeError{code: code}
+}
+
+// ExitCode returns the exit code of the command.
+func (e *ExitCodeError) ExitCode() int {
+	return e.code
+}
+
+func (e *ExitCodeError) Error() string {
+	return fmt.Sprintf("exit status %d", e.code)
+}
+
+// toExitCodeError converts err to an *ExitCodeError if it is of type *exec.ExitError.
+// As with shells, a command killed by a signal exits with 128 + the signal number.
+func toExitCodeError(err error) error {
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return err
+	}
+	if ws, ok := exitErr.Sys().(syscall.WaitStatus); ok && ws.Signaled() {
+		return NewExitCodeError(128 + int(ws.Signal()))
+	}
+	return NewExitCodeError(exitErr.ExitCode())
+}
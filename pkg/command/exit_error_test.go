@@ -55,3 +55,19 @@ func TestExitError_Unwrap(t *testing.T) {
 	got := newExitError(want).Unwrap()
 	assert.Equal(t, got, want)
 }
+
+func TestToExitCodeError(t *testing.T) {
+	t.Parallel()
+
+	err := errors.New("I am not an *exec.ExitError")
+	assert.Equal(t, err, toExitCodeError(err))
+	assert.Nil(t, toExitCodeError(nil))
+}
+
+func TestExitCodeError(t *testing.T) {
+	t.Parallel()
+
+	err := NewExitCodeError(130)
+	assert.Equal(t, 130, err.ExitCode())
+	assert.Equal(t, "exit status 130", err.Error())
+}
@@ -17,6 +17,8 @@ import (
 //go:generate mockgen -copyright_file=../../copyright_header -destination=../mocks/command_nerdctl_cmd_creator.go -package=mocks -mock_names NerdctlCmdCreator=NerdctlCmdCreator . NerdctlCmdCreator
 type NerdctlCmdCreator interface {
 	// Create creates a new Lima command and connects the stdio of it to the stdio of the current process.
+	// While it runs, the signals received by the current process are forwarded to it,
+	// and Run returns an *ExitCodeError carrying its exact exit code if it exits with a non-zero code.
 	Create(args ...string) Command
 	// CreateWithoutStdio creates a new Lima command without connecting the stdio of it to the stdio of the current process.
 	// It is usually used when either Output() or CombinedOutput() instead of Run() needs to be invoked on the returned command.
@@ -53,7 +55,10 @@ type NerdctlCmdCreatorSystemDeps interface {
 }
 
 func (ncc *nerdctlCmdCreator) Create(args ...string) Command {
-	return ncc.create(ncc.systemDeps.Stdin(), ncc.systemDeps.Stdout(), ncc.systemDeps.Stderr(), args...)
+	return newSignalForwardingCmd(
+		ncc.create(ncc.systemDeps.Stdin(), ncc.systemDeps.Stdout(), ncc.systemDeps.Stderr(), args...),
+		ncc.logger,
+	)
 }
 
 func (ncc *nerdctlCmdCreator) CreateWithoutStdio(args ...string) Command {
@@ -71,7 +76,7 @@ func (ncc *nerdctlCmdCreator) RunWithReplacingStdout(rs []Replacement, args ...s
 		// but we decide it's fine to omit it and just return the error now because:
 		// - stderr should be enough for the user to debug and retry the command.
 		// - The control flow is simpler.
-		return err
+		return toExitCodeError(err)
 	}
 	_, err = ncc.systemDeps.Stdout().Write(ncc.replaceBytes(buf.Bytes(), rs))
 	if err != nil {
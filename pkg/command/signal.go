@@ -0,0 +1,65 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package command
+
+import (
+	"os"
+	"os/signal"
+	"slices"
+
+	"github.com/runfinch/finch/pkg/flog"
+)
+
+// signalForwardingCmd is a Command whose Run forwards the signals sent to the current process only to the command
+// and converts its exit status to an *ExitCodeError.
+type signalForwardingCmd struct {
+	Command
+	logger flog.Logger
+	// inTerminalForeground reports whether the terminal signals are sent to the command by the terminal.
+	inTerminalForeground func() bool
+}
+
+var _ Command = (*signalForwardingCmd)(nil)
+
+func newSignalForwardingCmd(cmd Command, logger flog.Logger) *signalForwardingCmd {
+	return &signalForwardingCmd{Command: cmd, logger: logger, inTerminalForeground: inTerminalForeground}
+}
+
+func (c *signalForwardingCmd) Run() error {
+	// Subscribe before starting the command so that no signal is missed,
+	// which also keeps the signals from terminating the current process while the command handles them.
+	handled := slices.Concat(terminalSignals, forwardedSignals)
+	sigs := make(chan os.Signal, len(handled))
+	signal.Notify(sigs, handled...)
+	defer signal.Stop(sigs)
+
+	if err := c.Start(); err != nil {
+		return err
+	}
+
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case sig := <-sigs:
+				if !slices.Contains(forwardedSignals, sig) {
+					continue
+				}
+				// the command, which is in the same process group, already received the signal from the terminal
+				if slices.Contains(terminalSignals, sig) && c.inTerminalForeground() {
+					continue
+				}
+				if err := c.Signal(sig); err != nil {
+					c.logger.Debugf("failed to forward signal %q: %v", sig, err)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	err := c.Wait()
+	close(done)
+	return toExitCodeError(err)
+}
@@ -0,0 +1,34 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !windows
+
+package command
+
+import (
+	"os"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// terminalSignals are sent by the terminal to its whole foreground process group, e.g. on Ctrl+C,
+// so the commands created by NerdctlCmdCreator.Create already receive them when the current process
+// is in the foreground of its terminal. They are only forwarded otherwise, e.g. when sent by kill to a background finch.
+var terminalSignals = []os.Signal{syscall.SIGINT}
+
+// forwardedSignals are the signals relayed to the commands created by NerdctlCmdCreator.Create.
+// SIGWINCH is forwarded even from the terminal, as the command only reads the new size of the terminal on it.
+var forwardedSignals = []os.Signal{syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP, syscall.SIGWINCH}
+
+// inTerminalForeground reports whether the current process is in the foreground process group of its
+// controlling terminal, to which the terminal sends the terminal signals.
+func inTerminalForeground() bool {
+	tty, err := os.Open("/dev/tty")
+	if err != nil {
+		return false
+	}
+	defer func() { _ = tty.Close() }()
+	pgrp, err := unix.IoctlGetInt(int(tty.Fd()), unix.TIOCGPGRP)
+	return err == nil && pgrp == unix.Getpgrp()
+}
@@ -0,0 +1,131 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !windows
+
+package command
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+
+	"github.com/runfinch/finch/pkg/flog"
+)
+
+func TestSignalForwardingCmd_Run(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		script string
+		want   error
+	}{
+		{
+			name:   "successful command",
+			script: "exit 0",
+			want:   nil,
+		},
+		{
+			name:   "exit code is preserved",
+			script: "exit 3",
+			want:   NewExitCodeError(3),
+		},
+		{
+			name:   "command killed by a signal exits with 128 + signal",
+			script: "kill -TERM $$",
+			want:   NewExitCodeError(128 + int(syscall.SIGTERM)),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			cmd := newSignalForwardingCmd(NewExecCmdCreator().Create("sh", "-c", tc.script), flog.NewLogrus())
+			assert.Equal(t, tc.want, cmd.Run())
+		})
+	}
+}
+
+func TestSignalForwardingCmd_Run_startError(t *testing.T) {
+	t.Parallel()
+
+	cmd := newSignalForwardingCmd(NewExecCmdCreator().Create("/does/not/exist"), flog.NewLogrus())
+	err := cmd.Run()
+	var exitErr *ExitCodeError
+	assert.False(t, errors.As(err, &exitErr))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+//nolint:paralleltest // The signals are sent to the test process.
+func TestSignalForwardingCmd_Run_forwardsSignals(t *testing.T) {
+	for _, sig := range []syscall.Signal{syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP, syscall.SIGWINCH} {
+		t.Run(unix.SignalName(sig), func(t *testing.T) {
+			r, w := io.Pipe()
+			inner := NewExecCmdCreator().Create("sh", "-c",
+				fmt.Sprintf(`trap 'exit 7' %s; echo ready; while :; do sleep 0.1; done`, strings.TrimPrefix(unix.SignalName(sig), "SIG")))
+			inner.SetStdout(w)
+			cmd := newSignalForwardingCmd(inner, flog.NewLogrus())
+			// e.g. finch runs in the background, or without a terminal, and the signal is sent by kill
+			cmd.inTerminalForeground = func() bool { return false }
+
+			errCh := make(chan error, 1)
+			go func() {
+				errCh <- cmd.Run()
+				_ = w.Close()
+			}()
+
+			line, err := bufio.NewReader(r).ReadString('\n')
+			require.NoError(t, err)
+			require.Equal(t, "ready\n", line)
+			require.NoError(t, syscall.Kill(os.Getpid(), sig))
+
+			assert.Equal(t, NewExitCodeError(7), <-errCh)
+		})
+	}
+}
+
+//nolint:paralleltest // The signal is sent to the test process.
+func TestSignalForwardingCmd_Run_terminalSignalsNotForwarded(t *testing.T) {
+	r, w := io.Pipe()
+	inner := NewExecCmdCreator().Create("sh", "-c",
+		`n=0; trap 'n=$((n+1))' INT; trap 'echo $n; exit 7' TERM; echo $$; while :; do sleep 0.1; done`)
+	inner.SetStdout(w)
+	cmd := newSignalForwardingCmd(inner, flog.NewLogrus())
+	cmd.inTerminalForeground = func() bool { return true }
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- cmd.Run()
+		_ = w.Close()
+	}()
+
+	out := bufio.NewReader(r)
+	line, err := out.ReadString('\n')
+	require.NoError(t, err)
+	pid, err := strconv.Atoi(strings.TrimSpace(line))
+	require.NoError(t, err)
+
+	// a Ctrl+C sends SIGINT to both the command and the test process, which are in the same process group
+	require.NoError(t, syscall.Kill(pid, syscall.SIGINT))
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGINT))
+	time.Sleep(200 * time.Millisecond)
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGTERM))
+
+	line, err = out.ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, "1\n", line, "the command received SIGINT once")
+	assert.Equal(t, NewExitCodeError(7), <-errCh)
+}
@@ -0,0 +1,25 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build windows
+
+package command
+
+import (
+	"os"
+)
+
+// terminalSignals are delivered to every process attached to the console, e.g. on Ctrl+C,
+// including the commands created by NerdctlCmdCreator.Create.
+// Receiving them only keeps the current process alive until the command exits.
+var terminalSignals = []os.Signal{os.Interrupt}
+
+// forwardedSignals are the signals relayed to the commands created by NerdctlCmdCreator.Create.
+// None is on Windows, where signals cannot be sent to processes.
+var forwardedSignals = []os.Signal{}
+
+// inTerminalForeground reports whether the terminal signals were already delivered to the commands,
+// which is always the case on Windows.
+func inTerminalForeground() bool {
+	return true
+}
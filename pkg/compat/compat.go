@@ -0,0 +1,256 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package compat checks docker command lines against a catalog of the docker CLI commands and flags
+// that finch does not fully support.
+package compat
+
+import (
+	_ "embed"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Status is how well finch supports a docker command or flag.
+type Status string
+
+const (
+	// Partial means that finch accepts the command or flag, but it behaves differently than with docker.
+	Partial Status = "partial"
+	// Unsupported means that finch fails, or ignores the command or flag.
+	Unsupported Status = "unsupported"
+)
+
+// Entry is an item of the compatibility catalog.
+type Entry struct {
+	// Commands are the docker commands the entry applies to, e.g. run or "network create".
+	Commands []string `yaml:"commands"`
+	// Flags are the names of the flag, including its aliases. The entry applies to the whole command if it is empty.
+	Flags []string `yaml:"flags"`
+	// Value is the value of the flag the entry applies to. The entry applies to every value if it is empty.
+	Value  string `yaml:"value"`
+	Status Status `yaml:"status"`
+	Note   string `yaml:"note"`
+}
+
+// Finding is a command or flag of a command line that finch does not fully support.
+type Finding struct {
+	// Command is the docker command, e.g. run.
+	Command string `json:"Command"`
+	// Flag is the flag as it is named in the command line, or "" if the finding is about the command.
+	Flag string `json:"Flag"`
+	// Value is the value of the flag, if any.
+	Value  string `json:"Value"`
+	Status Status `json:"Status"`
+	Note   string `json:"Note"`
+}
+
+// String returns the command line of the finding, e.g. run --network host.
+func (f Finding) String() string {
+	s := f.Command
+	if f.Flag != "" {
+		s += " " + f.Flag
+	}
+	if f.Value != "" {
+		s += " " + f.Value
+	}
+	return s
+}
+
+// Catalog is the list of the docker CLI commands and flags that finch does not fully support.
+type Catalog struct {
+	entries []Entry
+}
+
+//go:embed catalog.yaml
+var catalogYAML []byte
+
+// DefaultCatalog returns the catalog maintained with finch.
+var DefaultCatalog = sync.OnceValue(func() *Catalog {
+	c, err := ParseCatalog(catalogYAML)
+	if err != nil {
+		// the catalog is embedded, so it is validated by the tests
+		panic(err)
+	}
+	return c
+})
+
+// ParseCatalog parses a catalog written in YAML.
+func ParseCatalog(data []byte) (*Catalog, error) {
+	var entries []Entry
+	if err := yaml.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse the compatibility catalog: %w", err)
+	}
+	for i, e := range entries {
+		if len(e.Commands) == 0 {
+			return nil, fmt.Errorf("entry %d of the compatibility catalog has no command", i)
+		}
+		if e.Status != Partial && e.Status != Unsupported {
+			return nil, fmt.Errorf("entry %d of the compatibility catalog has an invalid status %q", i, e.Status)
+		}
+	}
+	return &Catalog{entries: entries}, nil
+}
+
+// Check reports the commands and flags of the docker command line args that finch does not fully support,
+// e.g. docker run --gpus all alpine. The leading "docker" is optional.
+func (c *Catalog) Check(args []string) []Finding {
+	cmd := parse(args)
+	if cmd.name == "" {
+		return nil
+	}
+
+	var findings []Finding
+	for _, name := range cmd.commands() {
+		if e := c.lookup(name, "", ""); e != nil {
+			findings = append(findings, Finding{Command: name, Status: e.Status, Note: e.Note})
+		}
+	}
+	for _, f := range cmd.flags {
+		if e := c.lookup(cmd.name, f.name, f.value); e != nil {
+			findings = append(findings, Finding{Command: cmd.name, Flag: f.name, Value: f.value, Status: e.Status, Note: e.Note})
+		}
+	}
+	return findings
+}
+
+// lookup returns the entry of the command name, of its flag if it is not empty, or nil if there is none.
+func (c *Catalog) lookup(name, flag, value string) *Entry {
+	for i, e := range c.entries {
+		if !slices.Contains(e.Commands, name) {
+			continue
+		}
+		if flag == "" && len(e.Flags) == 0 {
+			return &c.entries[i]
+		}
+		if flag != "" && slices.Contains(e.Flags, flag) && (e.Value == "" || e.Value == value) {
+			return &c.entries[i]
+		}
+	}
+	return nil
+}
+
+// managementCommands are the docker commands grouping subcommands, e.g. docker network create.
+var managementCommands = []string{
+	"builder", "buildx", "checkpoint", "compose", "config", "container", "context", "image", "manifest",
+	"network", "node", "plugin", "secret", "service", "stack", "swarm", "system", "trust", "volume",
+}
+
+// commandAliases maps the docker commands to the shorter form they are listed as in the catalog.
+var commandAliases = map[string]string{
+	"container run":    "run",
+	"container create": "create",
+	"container exec":   "exec",
+	"container start":  "start",
+	"container attach": "attach",
+	"image build":      "build",
+}
+
+// containerCommands are the commands whose arguments following the image are the command run in the container.
+var containerCommands = []string{"run", "create", "exec"}
+
+// boolFlags are the flags that do not take a value, so that the argument following them is not mistaken for one.
+var boolFlags = []string{
+	"-a", "-d", "-i", "-t", "-P", "-q", "-s",
+	"--detach", "--interactive", "--tty", "--rm", "--privileged", "--init", "--read-only", "--sig-proxy",
+	"--oom-kill-disable", "--no-healthcheck", "--publish-all", "--no-cache", "--load", "--push", "--quiet",
+	"--all", "--no-trunc", "--size", "--latest", "--follow", "--timestamps", "--force", "--help", "--debug",
+	"--build", "--remove-orphans", "--no-stream", "--squash", "--compress", "--force-rm", "--tls", "--tlsverify",
+}
+
+// globalValueFlags are the flags of the docker CLI preceding the command that take a value.
+var globalValueFlags = []string{"-c", "--context", "-H", "--host", "--config", "-l", "--log-level"}
+
+type flag struct {
+	name  string
+	value string
+}
+
+type commandLine struct {
+	// name is the docker command, e.g. run or "network create".
+	name  string
+	flags []flag
+}
+
+// commands returns the command and, for a subcommand, its management command, e.g. buildx and "buildx bake".
+func (cl commandLine) commands() []string {
+	group, _, ok := strings.Cut(cl.name, " ")
+	if !ok {
+		return []string{cl.name}
+	}
+	return []string{group, cl.name}
+}
+
+// parse splits a docker command line into its command and flags.
+func parse(args []string) commandLine {
+	if len(args) > 0 && (args[0] == "docker" || args[0] == "finch") {
+		args = args[1:]
+	}
+
+	// global flags
+	i := 0
+	for ; i < len(args) && strings.HasPrefix(args[i], "-"); i++ {
+		if slices.Contains(globalValueFlags, args[i]) {
+			i++
+		}
+	}
+	if i >= len(args) {
+		return commandLine{}
+	}
+
+	cl := commandLine{name: args[i]}
+	i++
+	if slices.Contains(managementCommands, cl.name) && i < len(args) && !strings.HasPrefix(args[i], "-") {
+		cl.name += " " + args[i]
+		i++
+	}
+	if alias, ok := commandAliases[cl.name]; ok {
+		cl.name = alias
+	}
+
+	for ; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			break
+		}
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			if slices.Contains(containerCommands, cl.name) {
+				// the arguments following the image belong to the command run in the container
+				break
+			}
+			continue
+		}
+
+		name, value, hasValue := strings.Cut(arg, "=")
+		if !strings.HasPrefix(name, "--") && len(name) > 2 && !hasValue {
+			// grouped short flags, e.g. -it, or a short flag concatenated to its value, e.g. -p80:80
+			if allBool(name) {
+				for _, c := range name[1:] {
+					cl.flags = append(cl.flags, flag{name: "-" + string(c)})
+				}
+				continue
+			}
+			name, value, hasValue = name[:2], name[2:], true
+		}
+		if !hasValue && !slices.Contains(boolFlags, name) && i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+			value = args[i+1]
+			i++
+		}
+		cl.flags = append(cl.flags, flag{name: name, value: value})
+	}
+	return cl
+}
+
+// allBool reports whether the grouped short flags are all boolean flags, e.g. -it.
+func allBool(group string) bool {
+	for _, c := range group[1:] {
+		if !slices.Contains(boolFlags, "-"+string(c)) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,134 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package compat
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultCatalog(t *testing.T) {
+	t.Parallel()
+
+	_, err := ParseCatalog(catalogYAML)
+	require.NoError(t, err)
+}
+
+func TestParseCatalog_invalid(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{
+			name:    "no command",
+			data:    "- status: partial",
+			wantErr: "entry 0 of the compatibility catalog has no command",
+		},
+		{
+			name:    "invalid status",
+			data:    "- commands: [run]\n  status: supported",
+			wantErr: `entry 0 of the compatibility catalog has an invalid status "supported"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := ParseCatalog([]byte(tc.data))
+			assert.EqualError(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestCatalog_Check(t *testing.T) {
+	t.Parallel()
+
+	catalog, err := ParseCatalog([]byte(`
+- commands: [swarm]
+  status: unsupported
+  note: swarm
+- commands: [buildx]
+  status: partial
+  note: buildx
+- commands: [buildx bake]
+  status: unsupported
+  note: bake
+- commands: [run, create]
+  flags: [--gpus]
+  status: partial
+  note: gpus
+- commands: [run]
+  flags: [--network, --net]
+  value: host
+  status: partial
+  note: host network
+- commands: [run]
+  flags: [--link]
+  status: unsupported
+  note: link
+`))
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name string
+		args []string
+		want []Finding
+	}{
+		{
+			name: "flags of run",
+			args: []string{"docker", "run", "--gpus", "all", "-it", "--network", "host", "--link=db", "alpine"},
+			want: []Finding{
+				{Command: "run", Flag: "--gpus", Value: "all", Status: Partial, Note: "gpus"},
+				{Command: "run", Flag: "--network", Value: "host", Status: Partial, Note: "host network"},
+				{Command: "run", Flag: "--link", Value: "db", Status: Unsupported, Note: "link"},
+			},
+		},
+		{
+			name: "flag value that is not listed",
+			args: []string{"run", "--net=bridge", "alpine"},
+		},
+		{
+			name: "arguments of the container command are not checked",
+			args: []string{"docker", "container", "run", "--rm", "alpine", "sh", "--link", "x"},
+		},
+		{
+			name: "global flags and management commands",
+			args: []string{"docker", "--context", "remote", "swarm", "init"},
+			want: []Finding{{Command: "swarm", Status: Unsupported, Note: "swarm"}},
+		},
+		{
+			name: "subcommand of a partially supported command",
+			args: []string{"buildx", "bake", "-f", "bake.hcl"},
+			want: []Finding{
+				{Command: "buildx", Status: Partial, Note: "buildx"},
+				{Command: "buildx bake", Status: Unsupported, Note: "bake"},
+			},
+		},
+		{
+			name: "no command",
+			args: []string{"docker", "--debug"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.want, catalog.Check(tc.args))
+		})
+	}
+}
+
+func TestFinding_String(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "run --network host", Finding{Command: "run", Flag: "--network", Value: "host"}.String())
+	assert.Equal(t, "swarm", Finding{Command: "swarm"}.String())
+}
@@ -12,6 +12,7 @@ package config
 import (
 	"errors"
 	"fmt"
+	"net"
 	"path/filepath"
 
 	"github.com/lima-vm/lima/pkg/limayaml"
@@ -36,10 +37,19 @@ type SharedSystemSettings struct {
 
 // SharedSettings represents settings shared by all Finch configurations.
 type SharedSettings struct {
-	Snapshotters []string                   `yaml:"snapshotters,omitempty"`
-	CredsHelpers []string                   `yaml:"creds_helpers,omitempty"`
-	Experimental SharedExperimentalSettings `yaml:"experimental,omitempty"`
-	DockerCompat bool                       `yaml:"dockercompat,omitempty"`
+	Snapshotters  []string                   `yaml:"snapshotters,omitempty"`
+	CredsHelpers  []string                   `yaml:"creds_helpers,omitempty"`
+	Experimental  SharedExperimentalSettings `yaml:"experimental,omitempty"`
+	DockerCompat  bool                       `yaml:"dockercompat,omitempty"`
+	HostGatewayIP string                     `yaml:"host_gateway_ip,omitempty"`
+	HostAliases   bool                       `yaml:"host_aliases,omitempty"`
+	Support       SupportSettings            `yaml:"support,omitempty"`
+}
+
+// SupportSettings represents the settings of the support bundles.
+type SupportSettings struct {
+	// UploadURL is the HTTPS URL the support bundles are uploaded to, once confirmed, e.g. a presigned URL.
+	UploadURL string `yaml:"upload_url,omitempty"`
 }
 
 // SharedExperimentalSettings represents available experimental settings shared
@@ -162,9 +172,21 @@ func Load(
 		return nil, err
 	}
 
+	if err := validateSharedSettings(&defCfg.SharedSettings); err != nil {
+		return nil, fmt.Errorf("failed to validate config file: %w", err)
+	}
+
 	if err := validate(defCfg, log, systemDeps, mem); err != nil {
 		return nil, fmt.Errorf("failed to validate config file: %w", err)
 	}
 
 	return defCfg, nil
 }
+
+// validateSharedSettings validates the settings shared by all platforms.
+func validateSharedSettings(cfg *SharedSettings) error {
+	if cfg.HostGatewayIP != "" && net.ParseIP(cfg.HostGatewayIP) == nil {
+		return fmt.Errorf("specified host_gateway_ip (%q) is not a valid IP address", cfg.HostGatewayIP)
+	}
+	return nil
+}
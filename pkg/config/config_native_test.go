@@ -6,6 +6,8 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/spf13/afero"
@@ -92,6 +94,68 @@ func platformLoadTests(t *testing.T) []loadTestCase {
 			},
 			wantErr: nil,
 		},
+		{
+			name: "config file contains host gateway settings",
+			path: "/config.yaml",
+			mockSvc: func(
+				fs afero.Fs,
+				_ *mocks.Logger,
+				_ *mocks.LoadSystemDeps,
+				_ *mocks.Memory,
+				_ *mocks.CommandCreator,
+				_ *gomock.Controller,
+			) {
+				data := "host_gateway_ip: 10.4.0.1\nhost_aliases: true\n"
+				require.NoError(t, afero.WriteFile(fs, "/config.yaml", []byte(data), 0o600))
+			},
+			want: &Finch{
+				SharedSettings: SharedSettings{
+					HostGatewayIP: "10.4.0.1",
+					HostAliases:   true,
+				},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "config file contains support settings",
+			path: "/config.yaml",
+			mockSvc: func(
+				fs afero.Fs,
+				_ *mocks.Logger,
+				_ *mocks.LoadSystemDeps,
+				_ *mocks.Memory,
+				_ *mocks.CommandCreator,
+				_ *gomock.Controller,
+			) {
+				data := "support:\n  upload_url: https://example.com/upload\n"
+				require.NoError(t, afero.WriteFile(fs, "/config.yaml", []byte(data), 0o600))
+			},
+			want: &Finch{
+				SharedSettings: SharedSettings{
+					Support: SupportSettings{UploadURL: "https://example.com/upload"},
+				},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "config file contains an invalid host gateway IP",
+			path: "/config.yaml",
+			mockSvc: func(
+				fs afero.Fs,
+				_ *mocks.Logger,
+				_ *mocks.LoadSystemDeps,
+				_ *mocks.Memory,
+				_ *mocks.CommandCreator,
+				_ *gomock.Controller,
+			) {
+				require.NoError(t, afero.WriteFile(fs, "/config.yaml", []byte("host_gateway_ip: gateway"), 0o600))
+			},
+			want: nil,
+			wantErr: fmt.Errorf(
+				"failed to validate config file: %w",
+				errors.New(`specified host_gateway_ip ("gateway") is not a valid IP address`),
+			),
+		},
 		{
 			name: "config file does not exist",
 			path: "/config.yaml",
@@ -10,7 +10,9 @@ import (
 	"fmt"
 
 	"github.com/lima-vm/lima/pkg/limayaml"
+	"github.com/spf13/afero"
 	"github.com/xorcare/pointer"
+	"gopkg.in/yaml.v3"
 )
 
 // configureVirtualizationFramework changes settings that will only apply to the VM after a new init.
@@ -84,3 +86,26 @@ func (lca *limaConfigApplier) configureMounts(limaCfg *limayaml.LimaYAML) *limay
 	}
 	return limaCfg
 }
+
+// LimaMounts returns the host directories shared into the VM,
+// which are the mounts of the base Lima config at baseYamlFilePath followed by the additional_directories.
+// Locations are returned as written in the config, so they may start with "~".
+func LimaMounts(fs afero.Fs, cfg *Finch, baseYamlFilePath string) ([]string, error) {
+	b, err := afero.ReadFile(fs, baseYamlFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the base lima config file: %w", err)
+	}
+	var baseCfg limayaml.LimaYAML
+	if err := yaml.Unmarshal(b, &baseCfg); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the base lima config file: %w", err)
+	}
+
+	var overrideCfg limayaml.LimaYAML
+	(&limaConfigApplier{cfg: cfg}).configureMounts(&overrideCfg)
+
+	var locations []string
+	for _, m := range append(baseCfg.Mounts, overrideCfg.Mounts...) {
+		locations = append(locations, m.Location)
+	}
+	return locations, nil
+}
@@ -757,3 +757,23 @@ mountType: "reverse-sshfs"`), 0o600)
 		})
 	}
 }
+
+func TestLimaMounts(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/os/finch.yaml",
+		[]byte("mounts:\n  - location: \"~\"\n    writable: true\n  - location: \"/tmp/lima\"\n"), 0o600))
+	cfg := &Finch{
+		SystemSettings: SystemSettings{
+			AdditionalDirectories: []AdditionalDirectory{{Path: pointer.String("/Volumes")}},
+		},
+	}
+
+	got, err := LimaMounts(fs, cfg, "/os/finch.yaml")
+	require.NoError(t, err)
+	require.Equal(t, []string{"~", "/tmp/lima", "/Volumes"}, got)
+
+	_, err = LimaMounts(fs, cfg, "/missing.yaml")
+	require.EqualError(t, err, "failed to read the base lima config file: open /missing.yaml: file does not exist")
+}
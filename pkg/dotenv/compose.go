@@ -0,0 +1,339 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dotenv
+
+import (
+	"fmt"
+	"strings"
+)
+
+// composeParser implements the compose dialect:
+//
+//   - an optional `export ` prefix before the key,
+//   - single quoted values, taken literally and allowed to span lines,
+//   - double quoted values, allowed to span lines, with \n, \r, \t, \\, \" and \$ escapes and interpolation,
+//   - unquoted values, trimmed, with interpolation and inline comments introduced by whitespace followed by '#',
+//   - $VAR, ${VAR} and the ${VAR:-default}, ${VAR-default}, ${VAR:?err}, ${VAR?err}, ${VAR:+alt}, ${VAR+alt} forms,
+//     with $$ producing a literal '$'.
+//
+// Interpolated variables are resolved through the lookup function first and then through the variables
+// defined earlier in the file, which matches compose-go.
+type composeParser struct {
+	src    string
+	pos    int
+	line   int
+	lookup LookupFunc
+	keys   []string
+	vars   map[string]string
+}
+
+func parseCompose(src string, lookup LookupFunc) ([]string, error) {
+	p := &composeParser{
+		src:    strings.ReplaceAll(src, "\r\n", "\n"),
+		line:   1,
+		lookup: lookup,
+		vars:   make(map[string]string),
+	}
+	if err := p.parse(); err != nil {
+		return nil, err
+	}
+
+	envs := make([]string, 0, len(p.keys))
+	for _, k := range p.keys {
+		envs = append(envs, fmt.Sprintf("%s=%s", k, p.vars[k]))
+	}
+	return envs, nil
+}
+
+func (p *composeParser) errorf(format string, a ...any) error {
+	return &ParseError{Line: p.line, Msg: fmt.Sprintf(format, a...)}
+}
+
+func (p *composeParser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *composeParser) peek() byte {
+	return p.src[p.pos]
+}
+
+func (p *composeParser) next() byte {
+	c := p.src[p.pos]
+	p.pos++
+	if c == '\n' {
+		p.line++
+	}
+	return c
+}
+
+func (p *composeParser) skipBlanks() {
+	for !p.eof() && (p.peek() == ' ' || p.peek() == '\t') {
+		p.next()
+	}
+}
+
+// restOfLine consumes and returns everything up to, but excluding, the next newline.
+func (p *composeParser) restOfLine() string {
+	start := p.pos
+	for !p.eof() && p.peek() != '\n' {
+		p.next()
+	}
+	return p.src[start:p.pos]
+}
+
+func (p *composeParser) set(key, val string) {
+	if _, ok := p.vars[key]; !ok {
+		p.keys = append(p.keys, key)
+	}
+	p.vars[key] = val
+}
+
+func (p *composeParser) parse() error {
+	for {
+		// skip whitespace, empty lines and comments until the next statement
+		for !p.eof() && (strings.IndexByte(" \t\n", p.peek()) >= 0 || p.peek() == '#') {
+			if p.peek() == '#' {
+				p.restOfLine()
+				continue
+			}
+			p.next()
+		}
+		if p.eof() {
+			return nil
+		}
+		if err := p.parseStatement(); err != nil {
+			return err
+		}
+	}
+}
+
+func (p *composeParser) parseStatement() error {
+	if rest := p.src[p.pos:]; strings.HasPrefix(rest, "export ") || strings.HasPrefix(rest, "export\t") {
+		p.pos += len("export")
+		p.skipBlanks()
+	}
+
+	start := p.pos
+	for !p.eof() && p.peek() != '=' && p.peek() != '\n' {
+		p.next()
+	}
+	key := strings.TrimRight(p.src[start:p.pos], whiteSpaces)
+	if err := p.validateKey(key); err != nil {
+		return err
+	}
+
+	if p.eof() || p.peek() == '\n' {
+		// only a variable name was given; inherit its value from the environment if there is one
+		if v, ok := p.lookup(key); ok {
+			p.set(key, v)
+		}
+		return nil
+	}
+
+	p.next() // consume '='
+	p.skipBlanks()
+	val, err := p.parseValue()
+	if err != nil {
+		return err
+	}
+	p.set(key, val)
+	return nil
+}
+
+func (p *composeParser) validateKey(key string) error {
+	if key == "" {
+		return p.errorf("no variable name")
+	}
+	for _, c := range key {
+		isValid := c == '_' || c == '.' || c == '-' ||
+			(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+		if !isValid {
+			return p.errorf("unexpected character %q in variable name %q", c, key)
+		}
+	}
+	return nil
+}
+
+func (p *composeParser) parseValue() (string, error) {
+	if p.eof() || p.peek() == '\n' {
+		return "", nil
+	}
+
+	switch quote := p.peek(); quote {
+	case '\'', '"':
+		startLine := p.line
+		p.next()
+		start := p.pos
+		for {
+			if p.eof() {
+				return "", &ParseError{Line: startLine, Msg: fmt.Sprintf("unterminated quoted value, missing closing %c", quote)}
+			}
+			c := p.next()
+			if c == '\\' && quote == '"' && !p.eof() {
+				p.next()
+				continue
+			}
+			if c == quote {
+				break
+			}
+		}
+		raw := p.src[start : p.pos-1]
+
+		// only whitespace and a comment may follow the closing quote
+		if trailing := strings.TrimLeft(p.restOfLine(), whiteSpaces); trailing != "" && !strings.HasPrefix(trailing, "#") {
+			return "", p.errorf("unexpected characters %q after quoted value", trailing)
+		}
+		if quote == '\'' {
+			return raw, nil
+		}
+		return p.expand(raw, true)
+	default:
+		raw := p.restOfLine()
+		for i := 1; i < len(raw); i++ {
+			if raw[i] == '#' && (raw[i-1] == ' ' || raw[i-1] == '\t') {
+				raw = raw[:i]
+				break
+			}
+		}
+		return p.expand(strings.TrimRight(raw, whiteSpaces), false)
+	}
+}
+
+// resolve looks up a variable referenced by an interpolation.
+func (p *composeParser) resolve(key string) (string, bool) {
+	if v, ok := p.lookup(key); ok {
+		return v, true
+	}
+	v, ok := p.vars[key]
+	return v, ok
+}
+
+// expand performs interpolation on s and, when escapes is true, interprets backslash escapes.
+func (p *composeParser) expand(s string, escapes bool) (string, error) {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\\' && escapes && i+1 < len(s):
+			i++
+			switch s[i] {
+			case 'n':
+				sb.WriteByte('\n')
+			case 'r':
+				sb.WriteByte('\r')
+			case 't':
+				sb.WriteByte('\t')
+			case '\\', '"', '$':
+				sb.WriteByte(s[i])
+			default:
+				// unknown escapes are preserved as written
+				sb.WriteByte('\\')
+				sb.WriteByte(s[i])
+			}
+		case c == '$' && i+1 < len(s) && s[i+1] == '$':
+			sb.WriteByte('$')
+			i++
+		case c == '$' && i+1 < len(s) && s[i+1] == '{':
+			end := matchingBrace(s, i+2)
+			if end < 0 {
+				return "", p.errorf("unterminated variable reference in %q", s)
+			}
+			v, err := p.substitute(s[i+2 : end])
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(v)
+			i = end
+		case c == '$' && i+1 < len(s) && isNameStart(s[i+1]):
+			j := i + 1
+			for j < len(s) && isNameChar(s[j]) {
+				j++
+			}
+			v, _ := p.resolve(s[i+1 : j])
+			sb.WriteString(v)
+			i = j - 1
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String(), nil
+}
+
+// substitute evaluates the body of a ${...} expression.
+func (p *composeParser) substitute(expr string) (string, error) {
+	n := 0
+	for n < len(expr) && isNameChar(expr[n]) {
+		n++
+	}
+	name, op := expr[:n], expr[n:]
+	if name == "" || !isNameStart(name[0]) {
+		return "", p.errorf("invalid variable reference ${%s}", expr)
+	}
+	val, ok := p.resolve(name)
+	if op == "" {
+		return val, nil
+	}
+
+	colon := strings.HasPrefix(op, ":")
+	op = strings.TrimPrefix(op, ":")
+	if op == "" {
+		return "", p.errorf("invalid variable reference ${%s}", expr)
+	}
+	// with a colon, an empty variable is treated like an unset one
+	set := ok && (!colon || val != "")
+	arg := op[1:]
+
+	switch op[0] {
+	case '-':
+		if set {
+			return val, nil
+		}
+		return p.expand(arg, false)
+	case '+':
+		if set {
+			return p.expand(arg, false)
+		}
+		return "", nil
+	case '?':
+		if set {
+			return val, nil
+		}
+		msg, err := p.expand(arg, false)
+		if err != nil {
+			return "", err
+		}
+		if msg == "" {
+			msg = "required variable is missing a value"
+		}
+		return "", p.errorf("%s: %s", name, msg)
+	default:
+		return "", p.errorf("invalid variable reference ${%s}", expr)
+	}
+}
+
+// matchingBrace returns the index of the '}' closing a ${ whose body starts at start, accounting for nesting.
+func matchingBrace(s string, start int) int {
+	depth := 1
+	for i := start; i < len(s); i++ {
+		switch {
+		case s[i] == '$' && i+1 < len(s) && s[i+1] == '{':
+			depth++
+			i++
+		case s[i] == '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isNameChar(c byte) bool {
+	return isNameStart(c) || (c >= '0' && c <= '9')
+}
@@ -0,0 +1,147 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dotenv
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse_Compose(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		input   string
+		env     map[string]string
+		want    []string
+		wantErr string
+	}{
+		{
+			name:  "comments, blank lines and surrounding whitespace",
+			input: "# a comment\n  A=1  \n\n\t# indented comment\nB = 2\n",
+			want:  []string{"A=1", "B=2"},
+		},
+		{
+			name:  "export prefix",
+			input: "export A=1\nexport\tB=2\nexported=3\n",
+			want:  []string{"A=1", "B=2", "exported=3"},
+		},
+		{
+			name:  "single quotes are literal",
+			input: `A='a "b" \n $HOME ${HOME}'`,
+			env:   map[string]string{"HOME": "/home/user"},
+			want:  []string{`A=a "b" \n $HOME ${HOME}`},
+		},
+		{
+			name:  "double quotes interpret escapes",
+			input: `A="line1\nline2\ttab \"quoted\" back\\slash \$HOME \q"`,
+			want:  []string{"A=line1\nline2\ttab \"quoted\" back\\slash $HOME \\q"},
+		},
+		{
+			name:  "quoted values may span lines",
+			input: "A='first\nsecond'\nB=\"third\nfourth\"\nC=5\n",
+			want:  []string{"A=first\nsecond", "B=third\nfourth", "C=5"},
+		},
+		{
+			name:  "inline comments",
+			input: "A=value # comment\nB=va#lue\nC='quoted # kept' # comment\nD=\"x\"\t# comment\n",
+			want:  []string{"A=value", "B=va#lue", "C=quoted # kept", "D=x"},
+		},
+		{
+			name:  "interpolation from lookup and earlier variables",
+			input: "BASE=/srv\nA=$BASE/app\nB=${BASE}/data\nC=\"${HOME}/x\"\nD=$UNSET\nE=$$BASE\nF=price$\n",
+			env:   map[string]string{"HOME": "/home/user"},
+			want:  []string{"BASE=/srv", "A=/srv/app", "B=/srv/data", "C=/home/user/x", "D=", "E=$BASE", "F=price$"},
+		},
+		{
+			name:  "lookup takes precedence over earlier variables",
+			input: "A=file\nB=${A}\n",
+			env:   map[string]string{"A": "env"},
+			want:  []string{"A=file", "B=env"},
+		},
+		{
+			name: "default, alternate and nested forms",
+			input: "EMPTY=\n" +
+				"A=${UNSET:-def}\nB=${EMPTY:-def}\nC=${EMPTY-def}\n" +
+				"D=${SET:+alt}\nE=${UNSET:+alt}\nF=${EMPTY+alt}\n" +
+				"G=${UNSET:-${SET}-suffix}\n",
+			env:  map[string]string{"SET": "s"},
+			want: []string{"EMPTY=", "A=def", "B=def", "C=", "D=alt", "E=", "F=alt", "G=s-suffix"},
+		},
+		{
+			name:  "keys without a value are looked up",
+			input: "SET\nexport UNSET\n",
+			env:   map[string]string{"SET": "from-env"},
+			want:  []string{"SET=from-env"},
+		},
+		{
+			name:  "later definitions win",
+			input: "A=1\nB=2\nA=3\n",
+			want:  []string{"A=3", "B=2"},
+		},
+		{
+			name:  "CRLF line endings",
+			input: "A=1\r\nB=\"x\r\ny\"\r\nC='z'\r\n",
+			want:  []string{"A=1", "B=x\ny", "C=z"},
+		},
+		{
+			name:  "empty values",
+			input: "A=\nB=''\nC=\"\"\n",
+			want:  []string{"A=", "B=", "C="},
+		},
+		{
+			name:    "required variable",
+			input:   "A=1\nB=${UNSET:?must be set}\n",
+			wantErr: "invalid env file on line 2: UNSET: must be set",
+		},
+		{
+			name:    "unterminated quote",
+			input:   "A=1\nB=\"abc\nC=2\n",
+			wantErr: "invalid env file on line 2: unterminated quoted value, missing closing \"",
+		},
+		{
+			name:    "garbage after quoted value",
+			input:   "A='abc'def\n",
+			wantErr: "invalid env file on line 1: unexpected characters \"def\" after quoted value",
+		},
+		{
+			name:    "whitespace in key",
+			input:   "A B=1\n",
+			wantErr: "invalid env file on line 1: unexpected character ' ' in variable name \"A B\"",
+		},
+		{
+			name:    "missing key",
+			input:   "=1\n",
+			wantErr: "invalid env file on line 1: no variable name",
+		},
+		{
+			name:    "unterminated variable reference",
+			input:   "A=${B\n",
+			wantErr: "invalid env file on line 1: unterminated variable reference in \"${B\"",
+		},
+		{
+			name:    "invalid variable reference",
+			input:   "A=${1B}\n",
+			wantErr: "invalid env file on line 1: invalid variable reference ${1B}",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Parse(strings.NewReader(tc.input), Compose, testLookup(tc.env))
+			if tc.wantErr != "" {
+				assert.EqualError(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
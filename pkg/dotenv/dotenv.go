@@ -0,0 +1,110 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package dotenv parses the environment files passed to --env-file.
+//
+// Two dialects are supported because docker and docker compose disagree on what an env file is:
+// the docker CLI passes values through verbatim (quotes and all), while compose treats the file as
+// a small shell-like language with quoting, escapes, `export` and variable interpolation.
+package dotenv
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Mode selects the dialect used to parse an env file.
+type Mode int
+
+const (
+	// Docker follows the semantics of `docker run --env-file`.
+	// See https://github.com/docker/cli/blob/master/opts/file.go.
+	Docker Mode = iota
+	// Compose follows the semantics of `docker compose --env-file`.
+	// See https://github.com/compose-spec/compose-go/tree/main/dotenv.
+	Compose
+)
+
+// LookupFunc resolves variables that are not assigned a value in the file. It has the same semantics as os.LookupEnv.
+type LookupFunc func(key string) (string, bool)
+
+const whiteSpaces = " \t"
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// ParseError reports a malformed line in an env file.
+type ParseError struct {
+	Line int
+	Msg  string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("invalid env file on line %d: %s", e.Line, e.Msg)
+}
+
+// Parse reads an env file from r and returns its variables as KEY=VALUE pairs in the order they are defined.
+// Variables listed without a value are resolved through lookup and dropped if lookup does not know them.
+// CRLF line endings and a leading UTF-8 byte order mark are accepted in both modes.
+func Parse(r io.Reader, mode Mode, lookup LookupFunc) ([]string, error) {
+	if lookup == nil {
+		lookup = func(string) (string, bool) { return "", false }
+	}
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	b = bytes.TrimPrefix(b, utf8BOM)
+	if !utf8.Valid(b) {
+		return nil, fmt.Errorf("env file contains invalid utf8 bytes")
+	}
+
+	switch mode {
+	case Docker:
+		return parseDocker(b, lookup)
+	case Compose:
+		return parseCompose(string(b), lookup)
+	default:
+		return nil, fmt.Errorf("unknown env file mode: %d", mode)
+	}
+}
+
+// parseDocker mirrors parseKeyValueFile from the docker CLI: leading whitespace is dropped,
+// everything after the first '=' is passed through untouched, and keys must not contain whitespace.
+func parseDocker(b []byte, lookup LookupFunc) ([]string, error) {
+	var envs []string
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimLeftFunc(strings.TrimSuffix(scanner.Text(), "\r"), unicode.IsSpace)
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, val, hasVal := strings.Cut(line, "=")
+		if strings.ContainsAny(key, whiteSpaces) {
+			return nil, &ParseError{Line: lineNum, Msg: fmt.Sprintf("variable '%s' contains whitespaces", key)}
+		}
+		if len(key) == 0 {
+			return nil, &ParseError{Line: lineNum, Msg: fmt.Sprintf("no variable name on line '%s'", line)}
+		}
+
+		if hasVal {
+			envs = append(envs, fmt.Sprintf("%s=%s", key, val))
+			continue
+		}
+		if v, ok := lookup(key); ok {
+			envs = append(envs, fmt.Sprintf("%s=%s", key, v))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return envs, nil
+}
@@ -0,0 +1,121 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dotenv
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testLookup(env map[string]string) LookupFunc {
+	return func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}
+}
+
+func TestParse_Docker(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		input   string
+		env     map[string]string
+		want    []string
+		wantErr string
+	}{
+		{
+			name:  "comments, blank lines and leading whitespace",
+			input: "# a comment\nARG1=val1\n  ARG2=val2\n\n  # a 2nd comment\n  ",
+			want:  []string{"ARG1=val1", "ARG2=val2"},
+		},
+		{
+			name:  "values are passed through verbatim",
+			input: "QUOTED=\"a b\"\nSINGLE='c'\nINLINE=d # not a comment\nTRAILING=e  \nEQ=f=g\n",
+			want:  []string{`QUOTED="a b"`, "SINGLE='c'", "INLINE=d # not a comment", "TRAILING=e  ", "EQ=f=g"},
+		},
+		{
+			name:  "no interpolation",
+			input: "A=${HOME}\n",
+			env:   map[string]string{"HOME": "/home/user"},
+			want:  []string{"A=${HOME}"},
+		},
+		{
+			name:  "keys without a value are looked up",
+			input: "SET\nUNSET\n",
+			env:   map[string]string{"SET": "from-env"},
+			want:  []string{"SET=from-env"},
+		},
+		{
+			name:  "empty value",
+			input: "EMPTY=\n",
+			want:  []string{"EMPTY="},
+		},
+		{
+			name:  "CRLF line endings",
+			input: "A=1\r\nB=2\r\nSET\r\n",
+			env:   map[string]string{"SET": "x"},
+			want:  []string{"A=1", "B=2", "SET=x"},
+		},
+		{
+			name:  "byte order mark",
+			input: "\xEF\xBB\xBFA=1\n",
+			want:  []string{"A=1"},
+		},
+		{
+			name:  "duplicates are kept in order",
+			input: "A=1\nA=2\n",
+			want:  []string{"A=1", "A=2"},
+		},
+		{
+			name:    "whitespace in key",
+			input:   "A=1\nexport B=2\n",
+			wantErr: "invalid env file on line 2: variable 'export B' contains whitespaces",
+		},
+		{
+			name:    "missing key",
+			input:   "=value\n",
+			wantErr: "invalid env file on line 1: no variable name on line '=value'",
+		},
+		{
+			name:    "invalid utf8",
+			input:   "A=\xff\n",
+			wantErr: "env file contains invalid utf8 bytes",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Parse(strings.NewReader(tc.input), Docker, testLookup(tc.env))
+			if tc.wantErr != "" {
+				assert.EqualError(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestParse_ParseError(t *testing.T) {
+	t.Parallel()
+
+	_, err := Parse(strings.NewReader("A=1\n\nB C=2\n"), Docker, nil)
+	var pe *ParseError
+	require.True(t, errors.As(err, &pe))
+	assert.Equal(t, 3, pe.Line)
+}
+
+func TestParse_UnknownMode(t *testing.T) {
+	t.Parallel()
+
+	_, err := Parse(strings.NewReader("A=1"), Mode(42), nil)
+	assert.EqualError(t, err, "unknown env file mode: 42")
+}
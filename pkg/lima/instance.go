@@ -0,0 +1,75 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package lima
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/lima-vm/lima/pkg/limayaml"
+	"github.com/lima-vm/lima/pkg/store/filenames"
+
+	"github.com/runfinch/finch/pkg/command"
+)
+
+// Instance is the state of a Lima instance, as reported by limactl ls --json.
+// Only the fields used by finch are decoded, as the errors of the instance cannot be, see GetVMState.
+type Instance struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Dir    string `json:"dir"`
+	VMType VMType `json:"vmType"`
+	Arch   string `json:"arch"`
+	CPUs   int    `json:"cpus,omitempty"`
+	// Memory is the memory of the instance in bytes.
+	Memory int64 `json:"memory,omitempty"`
+	// Disk is the size of the disk of the instance in bytes.
+	Disk         int64           `json:"disk,omitempty"`
+	SSHLocalPort int             `json:"sshLocalPort,omitempty"`
+	HostAgentPID int             `json:"hostAgentPID,omitempty"`
+	LimaVersion  string          `json:"limaVersion"`
+	Config       *InstanceConfig `json:"config,omitempty"`
+}
+
+// InstanceConfig is the part of the lima.yaml of an instance used by finch.
+type InstanceConfig struct {
+	Mounts  []limayaml.Mount `json:"mounts,omitempty"`
+	Rosetta limayaml.Rosetta `json:"rosetta,omitempty"`
+}
+
+// HostAgentPIDFile returns the path of the file holding the PID of the host agent of the instance,
+// which is written when the instance starts.
+func (i *Instance) HostAgentPIDFile() string {
+	return filepath.Join(i.Dir, filenames.HostAgentPID)
+}
+
+// DiskFiles returns the paths of the files backing the disk of the instance.
+func (i *Instance) DiskFiles() []string {
+	return []string{filepath.Join(i.Dir, filenames.BaseDisk), filepath.Join(i.Dir, filenames.DiffDisk)}
+}
+
+// GetInstance returns the state of the instance with limactl ls --json, or nil if the instance does not exist.
+func GetInstance(creator command.NerdctlCmdCreator, instanceName string) (*Instance, error) {
+	cmd := creator.CreateWithoutStdio("ls", "--json", instanceName)
+	out, err := cmd.Output()
+	if err != nil {
+		if isNonexistentOutput(out, instanceName) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get the state of instance %q: %w", instanceName, err)
+	}
+	if strings.TrimSpace(string(out)) == "" {
+		return nil, nil
+	}
+
+	// limactl prints one JSON object per instance
+	var inst Instance
+	if err := json.NewDecoder(bytes.NewReader(out)).Decode(&inst); err != nil {
+		return nil, fmt.Errorf("failed to JSON-unmarshal the state of instance %q: %w", instanceName, err)
+	}
+	return &inst, nil
+}
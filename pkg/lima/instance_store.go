@@ -0,0 +1,127 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package lima
+
+import (
+	"errors"
+	"io/fs"
+	"sync"
+	"time"
+
+	"github.com/lima-vm/lima/pkg/store"
+
+	"github.com/runfinch/finch/pkg/flog"
+)
+
+// cacheTTL is how long the state of an instance read from the instance store is reused for.
+// The cache is also invalidated explicitly after the lifecycle operations of the instance.
+const cacheTTL = 5 * time.Second
+
+// InstanceStore reads the state of Lima instances directly from the Lima home directory,
+// which is much faster than running limactl ls.
+//
+//go:generate mockgen -copyright_file=../../copyright_header -destination=../mocks/lima_instance_store.go -package=mocks -mock_names InstanceStore=LimaInstanceStore . InstanceStore
+type InstanceStore interface {
+	// Inspect returns the state of the instance, or an error wrapping fs.ErrNotExist if it does not exist.
+	Inspect(instanceName string) (*store.Instance, error)
+}
+
+type limaHomeStore struct{}
+
+var _ InstanceStore = (*limaHomeStore)(nil)
+
+// NewInstanceStore creates an InstanceStore reading the instances of the Lima home directory
+// with the store package of Lima, which checks the lock and PID files of the instances and parses their lima.yaml.
+// The store package locates the Lima home with the LIMA_HOME environment variable only, like limactl,
+// so it must be set in the environment of the current process.
+func NewInstanceStore() InstanceStore {
+	return &limaHomeStore{}
+}
+
+func (s *limaHomeStore) Inspect(instanceName string) (*store.Instance, error) {
+	return store.Inspect(instanceName)
+}
+
+// instanceState is the state of an instance, as reported by limactl ls.
+type instanceState struct {
+	// status is "" if the instance does not exist.
+	status string
+	vmType string
+	// reasons are the errors reported by Lima for the instance, e.g. why it is broken.
+	reasons []string
+	readAt  time.Time
+}
+
+// instanceCache caches the state of the instances read from an InstanceStore for the rest of the process.
+type instanceCache struct {
+	mu     sync.Mutex
+	store  InstanceStore
+	states map[string]instanceState
+	now    func() time.Time
+}
+
+// cache is used by GetVMStatus, GetVMState and GetVMType. It does not read anything until an InstanceStore is set
+// with UseInstanceStore, so that limactl ls is run by default.
+var cache = &instanceCache{now: time.Now}
+
+// UseInstanceStore makes GetVMStatus, GetVMState and GetVMType read the state of the instances from s, and cache it,
+// rather than running limactl ls every time. limactl ls is still run when s fails to read an instance.
+func UseInstanceStore(s InstanceStore) {
+	cache.setStore(s)
+}
+
+// InvalidateCache forgets the cached state of the instance. It must be called after an operation
+// changing the state of the instance, e.g. limactl start.
+func InvalidateCache(instanceName string) {
+	cache.invalidate(instanceName)
+}
+
+func (c *instanceCache) setStore(s InstanceStore) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.store = s
+	c.states = map[string]instanceState{}
+}
+
+func (c *instanceCache) invalidate(instanceName string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.states, instanceName)
+}
+
+// read returns the state of the instance from the cache or from the instance store,
+// or false if it must be read with limactl.
+func (c *instanceCache) read(logger flog.Logger, instanceName string) (instanceState, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.store == nil {
+		return instanceState{}, false
+	}
+	if state, ok := c.states[instanceName]; ok && c.now().Sub(state.readAt) < cacheTTL {
+		return state, true
+	}
+
+	var state instanceState
+	inst, err := c.store.Inspect(instanceName)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		// the instance does not exist
+	case err != nil:
+		logger.Debugf("Failed to read the state of instance %q, falling back to limactl: %v", instanceName, err)
+		return instanceState{}, false
+	case inst.Status == store.StatusUnknown:
+		logger.Debugf("Instance %q has an unknown status, falling back to limactl: %v", instanceName, errors.Join(inst.Errors...))
+		return instanceState{}, false
+	default:
+		state = instanceState{status: inst.Status, vmType: inst.VMType}
+		for _, err := range inst.Errors {
+			state.reasons = append(state.reasons, err.Error())
+		}
+	}
+
+	state.readAt = c.now()
+	c.states[instanceName] = state
+	return state, true
+}
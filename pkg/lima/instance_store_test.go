@@ -0,0 +1,149 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package lima
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"testing"
+	"time"
+
+	"github.com/lima-vm/lima/pkg/store"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/runfinch/finch/pkg/mocks"
+)
+
+func TestGetVMStatus_instanceStore(t *testing.T) {
+	t.Parallel()
+
+	instanceName := "finch"
+	testCases := []struct {
+		name    string
+		want    VMStatus
+		wantErr error
+		mockSvc func(*mocks.LimaInstanceStore, *mocks.NerdctlCmdCreator, *mocks.Logger, *gomock.Controller)
+	}{
+		{
+			name: "running VM is read from the store",
+			want: Running,
+			mockSvc: func(is *mocks.LimaInstanceStore, _ *mocks.NerdctlCmdCreator, logger *mocks.Logger, _ *gomock.Controller) {
+				is.EXPECT().Inspect(instanceName).Return(&store.Instance{Status: store.StatusRunning, VMType: "vz"}, nil)
+				logger.EXPECT().Debugf("Status of virtual machine: %s", "Running")
+			},
+		},
+		{
+			name: "nonexistent VM is read from the store",
+			want: Nonexistent,
+			mockSvc: func(is *mocks.LimaInstanceStore, _ *mocks.NerdctlCmdCreator, logger *mocks.Logger, _ *gomock.Controller) {
+				is.EXPECT().Inspect(instanceName).Return(nil, fmt.Errorf("open lima.yaml: %w", fs.ErrNotExist))
+				logger.EXPECT().Debugf("Status of virtual machine: %s", "")
+			},
+		},
+		{
+			name: "broken VM is read from the store",
+			want: Broken,
+			mockSvc: func(is *mocks.LimaInstanceStore, _ *mocks.NerdctlCmdCreator, logger *mocks.Logger, _ *gomock.Controller) {
+				is.EXPECT().Inspect(instanceName).Return(&store.Instance{
+					Status: store.StatusBroken,
+					Errors: []error{errors.New("host agent is running but driver is not")},
+				}, nil)
+				logger.EXPECT().Debugf("Status of virtual machine: %s", "Broken")
+			},
+		},
+		{
+			name: "unknown status falls back to limactl",
+			want: Stopped,
+			mockSvc: func(is *mocks.LimaInstanceStore, ncc *mocks.NerdctlCmdCreator, logger *mocks.Logger, ctrl *gomock.Controller) {
+				is.EXPECT().Inspect(instanceName).Return(&store.Instance{Status: store.StatusUnknown}, nil)
+				logger.EXPECT().Debugf("Instance %q has an unknown status, falling back to limactl: %v", instanceName, nil)
+				cmd := mocks.NewCommand(ctrl)
+				ncc.EXPECT().CreateWithoutStdio("ls", "-f", "{{.Status}}", instanceName).Return(cmd)
+				cmd.EXPECT().Output().Return([]byte("Stopped"), nil)
+				logger.EXPECT().Debugf("Status of virtual machine: %s", "Stopped")
+			},
+		},
+		{
+			name: "store error falls back to limactl",
+			want: Running,
+			mockSvc: func(is *mocks.LimaInstanceStore, ncc *mocks.NerdctlCmdCreator, logger *mocks.Logger, ctrl *gomock.Controller) {
+				is.EXPECT().Inspect(instanceName).Return(nil, errors.New("permission denied"))
+				logger.EXPECT().Debugf("Failed to read the state of instance %q, falling back to limactl: %v",
+					instanceName, errors.New("permission denied"))
+				cmd := mocks.NewCommand(ctrl)
+				ncc.EXPECT().CreateWithoutStdio("ls", "-f", "{{.Status}}", instanceName).Return(cmd)
+				cmd.EXPECT().Output().Return([]byte("Running"), nil)
+				logger.EXPECT().Debugf("Status of virtual machine: %s", "Running")
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			is := mocks.NewLimaInstanceStore(ctrl)
+			ncc := mocks.NewNerdctlCmdCreator(ctrl)
+			logger := mocks.NewLogger(ctrl)
+			tc.mockSvc(is, ncc, logger, ctrl)
+
+			c := &instanceCache{now: time.Now}
+			c.setStore(is)
+			got, err := getVMStatus(c, ncc, logger, instanceName)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestInstanceCache(t *testing.T) {
+	t.Parallel()
+
+	instanceName := "finch"
+	ctrl := gomock.NewController(t)
+	is := mocks.NewLimaInstanceStore(ctrl)
+	ncc := mocks.NewNerdctlCmdCreator(ctrl)
+	logger := mocks.NewLogger(ctrl)
+	logger.EXPECT().Debugf(gomock.Any(), gomock.Any()).AnyTimes()
+
+	now := time.Now()
+	c := &instanceCache{now: func() time.Time { return now }}
+	c.setStore(is)
+
+	// the state is read once for the status and the type
+	is.EXPECT().Inspect(instanceName).Return(&store.Instance{Status: store.StatusStopped, VMType: "qemu"}, nil)
+	status, err := getVMStatus(c, ncc, logger, instanceName)
+	assert.NoError(t, err)
+	assert.Equal(t, Stopped, status)
+	vmType, err := getVMType(c, ncc, logger, instanceName)
+	assert.NoError(t, err)
+	assert.Equal(t, QEMU, vmType)
+
+	// the state is read again once invalidated
+	c.invalidate(instanceName)
+	is.EXPECT().Inspect(instanceName).Return(&store.Instance{Status: store.StatusRunning, VMType: "qemu"}, nil)
+	status, err = getVMStatus(c, ncc, logger, instanceName)
+	assert.NoError(t, err)
+	assert.Equal(t, Running, status)
+
+	// and once expired
+	now = now.Add(cacheTTL)
+	is.EXPECT().Inspect(instanceName).Return(&store.Instance{Status: store.StatusStopped, VMType: "qemu"}, nil)
+	status, err = getVMStatus(c, ncc, logger, instanceName)
+	assert.NoError(t, err)
+	assert.Equal(t, Stopped, status)
+
+	// along with the reasons of a broken instance
+	c.invalidate(instanceName)
+	is.EXPECT().Inspect(instanceName).Return(&store.Instance{
+		Status: store.StatusBroken,
+		Errors: []error{errors.New("host agent is running but driver is not")},
+	}, nil)
+	state, err := getVMState(c, ncc, logger, instanceName)
+	assert.NoError(t, err)
+	assert.Equal(t, VMState{Status: Broken, Reasons: []string{"host agent is running but driver is not"}}, state)
+}
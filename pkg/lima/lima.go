@@ -9,6 +9,8 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/lima-vm/lima/pkg/store"
+
 	"github.com/runfinch/finch/pkg/command"
 	"github.com/runfinch/finch/pkg/flog"
 )
@@ -26,12 +28,19 @@ type VMType string
 // LimaVersion is injected at build time to be used in the call to osutil.LimaUser.
 var LimaVersion string
 
-// Finch CLI assumes there are only 4 VM status below. Adding more statuses will need to make changes in the caller side.
+// Finch CLI handles every VM status below. Adding more statuses will need to make changes in the caller side.
 const (
 	Running VMStatus = iota
 	Stopped
 	Nonexistent
 	Unknown
+	// Broken means that Lima failed to determine the status of the instance, e.g. its host agent is not running
+	// while its driver is. The reasons are reported by GetVMState.
+	Broken
+	// Installing means that the WSL distribution of the instance is being installed.
+	Installing
+	// Uninitialized means that the instance exists, but its WSL distribution is not registered.
+	Uninitialized
 	QEMU              VMType = "qemu"
 	VZ                VMType = "vz"
 	WSL               VMType = "wsl2"
@@ -39,14 +48,84 @@ const (
 	UnknownVMType     VMType = "unknown"
 )
 
+// String returns the name of the status, as reported by Lima.
+func (s VMStatus) String() string {
+	switch s {
+	case Running:
+		return store.StatusRunning
+	case Stopped:
+		return store.StatusStopped
+	case Nonexistent:
+		return "Nonexistent"
+	case Broken:
+		return store.StatusBroken
+	case Installing:
+		return store.StatusInstalling
+	case Uninitialized:
+		return store.StatusUninitialized
+	default:
+		return "Unknown"
+	}
+}
+
+// VMState is the status of an instance along with the reasons Lima reports for it.
+type VMState struct {
+	Status VMStatus
+	// Reasons are the errors reported by Lima for the instance, e.g. why it is broken.
+	Reasons []string
+}
+
+// stateFormat makes limactl ls print the status of the instance followed by its errors, one per line.
+// The errors are not usable in the JSON output of limactl ls, as they are marshaled as empty objects.
+const stateFormat = `{{.Status}}{{range .Errors}}{{"\n"}}{{.}}{{end}}`
+
+// GetVMState returns the Lima VM status and the reasons reported for it.
+// Unlike GetVMStatus, an unrecognized status is not an error, but is reported as Unknown with the status as reason.
+func GetVMState(creator command.NerdctlCmdCreator, logger flog.Logger, instanceName string) (VMState, error) {
+	return getVMState(cache, creator, logger, instanceName)
+}
+
+func getVMState(c *instanceCache, creator command.NerdctlCmdCreator, logger flog.Logger, instanceName string) (VMState, error) {
+	if state, ok := c.read(logger, instanceName); ok {
+		return toVMState(state.status, state.reasons, logger), nil
+	}
+
+	cmd := creator.CreateWithoutStdio("ls", "-f", stateFormat, instanceName)
+	out, err := cmd.Output()
+	if err != nil {
+		if isNonexistentOutput(out, instanceName) {
+			return VMState{Status: Nonexistent}, nil
+		}
+		return VMState{Status: Unknown}, err
+	}
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	return toVMState(strings.TrimSpace(lines[0]), lines[1:], logger), nil
+}
+
+func toVMState(status string, reasons []string, logger flog.Logger) VMState {
+	vmStatus, err := toVMStatus(status, logger)
+	if err != nil {
+		reasons = append([]string{fmt.Sprintf("unrecognized status %q", status)}, reasons...)
+	}
+	return VMState{Status: vmStatus, Reasons: reasons}
+}
+
 // GetVMStatus returns the Lima VM status.
+// It is read from the instance store set with UseInstanceStore if any, or with limactl ls otherwise.
 func GetVMStatus(creator command.NerdctlCmdCreator, logger flog.Logger, instanceName string) (VMStatus, error) {
+	return getVMStatus(cache, creator, logger, instanceName)
+}
+
+func getVMStatus(c *instanceCache, creator command.NerdctlCmdCreator, logger flog.Logger, instanceName string) (VMStatus, error) {
+	if state, ok := c.read(logger, instanceName); ok {
+		return toVMStatus(state.status, logger)
+	}
+
 	args := []string{"ls", "-f", "{{.Status}}", instanceName}
 	cmd := creator.CreateWithoutStdio(args...)
 	out, err := cmd.Output()
 	if err != nil {
-		if strings.TrimSpace(string(out)) == "" ||
-			strings.Contains(strings.TrimSpace(string(out)), fmt.Sprintf("No instance matching %s found", instanceName)) {
+		if isNonexistentOutput(out, instanceName) {
 			return Nonexistent, nil
 		}
 		return Unknown, err
@@ -55,8 +134,23 @@ func GetVMStatus(creator command.NerdctlCmdCreator, logger flog.Logger, instance
 	return toVMStatus(status, logger)
 }
 
+// isNonexistentOutput reports whether the output of a failed limactl ls means that the instance does not exist.
+func isNonexistentOutput(out []byte, instanceName string) bool {
+	return strings.TrimSpace(string(out)) == "" ||
+		strings.Contains(strings.TrimSpace(string(out)), fmt.Sprintf("No instance matching %s found", instanceName))
+}
+
 // GetVMType returns the Lima VMType for a running instance.
+// It is read from the instance store set with UseInstanceStore if any, or with limactl ls otherwise.
 func GetVMType(creator command.NerdctlCmdCreator, logger flog.Logger, instanceName string) (VMType, error) {
+	return getVMType(cache, creator, logger, instanceName)
+}
+
+func getVMType(c *instanceCache, creator command.NerdctlCmdCreator, logger flog.Logger, instanceName string) (VMType, error) {
+	if state, ok := c.read(logger, instanceName); ok {
+		return toVMType(state.vmType, logger)
+	}
+
 	args := []string{"ls", "-f", "{{.VMType}}", instanceName}
 	cmd := creator.CreateWithoutStdio(args...)
 	out, err := cmd.Output()
@@ -72,10 +166,16 @@ func toVMStatus(status string, logger flog.Logger) (VMStatus, error) {
 	switch status {
 	case "":
 		return Nonexistent, nil
-	case "Running":
+	case store.StatusRunning:
 		return Running, nil
-	case "Stopped":
+	case store.StatusStopped:
 		return Stopped, nil
+	case store.StatusBroken:
+		return Broken, nil
+	case store.StatusInstalling:
+		return Installing, nil
+	case store.StatusUninitialized:
+		return Uninitialized, nil
 	default:
 		return Unknown, errors.New("unrecognized system status")
 	}
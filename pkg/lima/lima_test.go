@@ -7,6 +7,7 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/lima-vm/lima/pkg/limayaml"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 
@@ -55,14 +56,44 @@ func TestGetVMStatus(t *testing.T) {
 				logger.EXPECT().Debugf("Status of virtual machine: %s", "")
 			},
 		},
+		{
+			name:    "broken VM",
+			want:    lima.Broken,
+			wantErr: nil,
+			mockSvc: func(creator *mocks.NerdctlCmdCreator, logger *mocks.Logger, cmd *mocks.Command) {
+				creator.EXPECT().CreateWithoutStdio(mockArgs).Return(cmd)
+				cmd.EXPECT().Output().Return([]byte("Broken "), nil)
+				logger.EXPECT().Debugf("Status of virtual machine: %s", "Broken")
+			},
+		},
+		{
+			name:    "installing VM",
+			want:    lima.Installing,
+			wantErr: nil,
+			mockSvc: func(creator *mocks.NerdctlCmdCreator, logger *mocks.Logger, cmd *mocks.Command) {
+				creator.EXPECT().CreateWithoutStdio(mockArgs).Return(cmd)
+				cmd.EXPECT().Output().Return([]byte("Installing"), nil)
+				logger.EXPECT().Debugf("Status of virtual machine: %s", "Installing")
+			},
+		},
+		{
+			name:    "uninitialized VM",
+			want:    lima.Uninitialized,
+			wantErr: nil,
+			mockSvc: func(creator *mocks.NerdctlCmdCreator, logger *mocks.Logger, cmd *mocks.Command) {
+				creator.EXPECT().CreateWithoutStdio(mockArgs).Return(cmd)
+				cmd.EXPECT().Output().Return([]byte("Uninitialized"), nil)
+				logger.EXPECT().Debugf("Status of virtual machine: %s", "Uninitialized")
+			},
+		},
 		{
 			name:    "unknown VM status",
 			want:    lima.Unknown,
 			wantErr: errors.New("unrecognized system status"),
 			mockSvc: func(creator *mocks.NerdctlCmdCreator, logger *mocks.Logger, cmd *mocks.Command) {
 				creator.EXPECT().CreateWithoutStdio(mockArgs).Return(cmd)
-				cmd.EXPECT().Output().Return([]byte("Broken "), nil)
-				logger.EXPECT().Debugf("Status of virtual machine: %s", "Broken")
+				cmd.EXPECT().Output().Return([]byte("Suspended "), nil)
+				logger.EXPECT().Debugf("Status of virtual machine: %s", "Suspended")
 			},
 		},
 		{
@@ -92,6 +123,95 @@ func TestGetVMStatus(t *testing.T) {
 	}
 }
 
+func TestGetVMState(t *testing.T) {
+	t.Parallel()
+
+	instanceName := "finch"
+	mockArgs := []string{"ls", "-f", `{{.Status}}{{range .Errors}}{{"\n"}}{{.}}{{end}}`, instanceName}
+	testCases := []struct {
+		name    string
+		want    lima.VMState
+		wantErr error
+		mockSvc func(*mocks.NerdctlCmdCreator, *mocks.Logger, *mocks.Command)
+	}{
+		{
+			name: "running VM",
+			want: lima.VMState{Status: lima.Running, Reasons: []string{}},
+			mockSvc: func(creator *mocks.NerdctlCmdCreator, logger *mocks.Logger, cmd *mocks.Command) {
+				creator.EXPECT().CreateWithoutStdio(mockArgs).Return(cmd)
+				cmd.EXPECT().Output().Return([]byte("Running\n"), nil)
+				logger.EXPECT().Debugf("Status of virtual machine: %s", "Running")
+			},
+		},
+		{
+			name: "broken VM with reasons",
+			want: lima.VMState{
+				Status:  lima.Broken,
+				Reasons: []string{"host agent is running but driver is not", `failed to connect to "ha.sock"`},
+			},
+			mockSvc: func(creator *mocks.NerdctlCmdCreator, logger *mocks.Logger, cmd *mocks.Command) {
+				creator.EXPECT().CreateWithoutStdio(mockArgs).Return(cmd)
+				cmd.EXPECT().Output().Return(
+					[]byte("Broken\nhost agent is running but driver is not\nfailed to connect to \"ha.sock\"\n"), nil)
+				logger.EXPECT().Debugf("Status of virtual machine: %s", "Broken")
+			},
+		},
+		{
+			name: "unknown VM status",
+			want: lima.VMState{Status: lima.Unknown, Reasons: []string{`unrecognized status "Suspended"`}},
+			mockSvc: func(creator *mocks.NerdctlCmdCreator, logger *mocks.Logger, cmd *mocks.Command) {
+				creator.EXPECT().CreateWithoutStdio(mockArgs).Return(cmd)
+				cmd.EXPECT().Output().Return([]byte("Suspended"), nil)
+				logger.EXPECT().Debugf("Status of virtual machine: %s", "Suspended")
+			},
+		},
+		{
+			name: "nonexistent VM",
+			want: lima.VMState{Status: lima.Nonexistent},
+			mockSvc: func(creator *mocks.NerdctlCmdCreator, _ *mocks.Logger, cmd *mocks.Command) {
+				creator.EXPECT().CreateWithoutStdio(mockArgs).Return(cmd)
+				cmd.EXPECT().Output().Return([]byte("No instance matching finch found"), errors.New("exit status 1"))
+			},
+		},
+		{
+			name:    "status command returns an error",
+			want:    lima.VMState{Status: lima.Unknown},
+			wantErr: errors.New("get status error"),
+			mockSvc: func(creator *mocks.NerdctlCmdCreator, _ *mocks.Logger, cmd *mocks.Command) {
+				creator.EXPECT().CreateWithoutStdio(mockArgs).Return(cmd)
+				cmd.EXPECT().Output().Return([]byte("Broken"), errors.New("get status error"))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			creator := mocks.NewNerdctlCmdCreator(ctrl)
+			statusCmd := mocks.NewCommand(ctrl)
+			logger := mocks.NewLogger(ctrl)
+			tc.mockSvc(creator, logger, statusCmd)
+			got, err := lima.GetVMState(creator, logger, instanceName)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestVMStatus_String(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "Running", lima.Running.String())
+	assert.Equal(t, "Stopped", lima.Stopped.String())
+	assert.Equal(t, "Nonexistent", lima.Nonexistent.String())
+	assert.Equal(t, "Unknown", lima.Unknown.String())
+	assert.Equal(t, "Broken", lima.Broken.String())
+	assert.Equal(t, "Installing", lima.Installing.String())
+	assert.Equal(t, "Uninitialized", lima.Uninitialized.String())
+}
+
 func TestGetVMType(t *testing.T) {
 	t.Parallel()
 
@@ -179,3 +299,77 @@ func TestGetVMType(t *testing.T) {
 		})
 	}
 }
+
+func TestGetInstance(t *testing.T) {
+	t.Parallel()
+
+	instanceName := "finch"
+	mockArgs := []string{"ls", "--json", instanceName}
+	writable := true
+	testCases := []struct {
+		name    string
+		want    *lima.Instance
+		wantErr error
+		mockSvc func(*mocks.NerdctlCmdCreator, *mocks.Command)
+	}{
+		{
+			name: "existing instance",
+			want: &lima.Instance{
+				Name:         "finch",
+				Status:       "Running",
+				Dir:          "/lima/finch",
+				VMType:       lima.VZ,
+				Arch:         "aarch64",
+				CPUs:         2,
+				Memory:       4294967296,
+				Disk:         53687091200,
+				SSHLocalPort: 53412,
+				LimaVersion:  "1.2.2",
+				Config: &lima.InstanceConfig{
+					Mounts: []limayaml.Mount{{Location: "~", Writable: &writable}},
+				},
+			},
+			mockSvc: func(creator *mocks.NerdctlCmdCreator, cmd *mocks.Command) {
+				creator.EXPECT().CreateWithoutStdio(mockArgs).Return(cmd)
+				cmd.EXPECT().Output().Return([]byte(`{"name":"finch","status":"Running","dir":"/lima/finch","vmType":"vz",`+
+					`"arch":"aarch64","cpus":2,"memory":4294967296,"disk":53687091200,"sshLocalPort":53412,`+
+					`"errors":[{}],"limaVersion":"1.2.2","config":{"mounts":[{"location":"~","writable":true}]}}`+"\n"), nil)
+			},
+		},
+		{
+			name: "nonexistent instance",
+			want: nil,
+			mockSvc: func(creator *mocks.NerdctlCmdCreator, cmd *mocks.Command) {
+				creator.EXPECT().CreateWithoutStdio(mockArgs).Return(cmd)
+				cmd.EXPECT().Output().Return([]byte("No instance matching finch found"), errors.New("exit status 1"))
+			},
+		},
+		{
+			name:    "invalid output",
+			want:    nil,
+			wantErr: errors.New(`failed to JSON-unmarshal the state of instance "finch": invalid character 'o' in literal null (expecting 'u')`),
+			mockSvc: func(creator *mocks.NerdctlCmdCreator, cmd *mocks.Command) {
+				creator.EXPECT().CreateWithoutStdio(mockArgs).Return(cmd)
+				cmd.EXPECT().Output().Return([]byte("not json"), nil)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			creator := mocks.NewNerdctlCmdCreator(ctrl)
+			cmd := mocks.NewCommand(ctrl)
+			tc.mockSvc(creator, cmd)
+			got, err := lima.GetInstance(creator, instanceName)
+			if tc.wantErr != nil {
+				assert.EqualError(t, err, tc.wantErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
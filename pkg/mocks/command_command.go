@@ -14,6 +14,7 @@ package mocks
 
 import (
 	io "io"
+	os "os"
 	reflect "reflect"
 
 	gomock "go.uber.org/mock/gomock"
@@ -135,6 +136,20 @@ func (mr *CommandMockRecorder) SetStdout(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetStdout", reflect.TypeOf((*Command)(nil).SetStdout), arg0)
 }
 
+// Signal mocks base method.
+func (m *Command) Signal(sig os.Signal) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Signal", sig)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Signal indicates an expected call of Signal.
+func (mr *CommandMockRecorder) Signal(sig any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Signal", reflect.TypeOf((*Command)(nil).Signal), sig)
+}
+
 // Start mocks base method.
 func (m *Command) Start() error {
 	m.ctrl.T.Helper()
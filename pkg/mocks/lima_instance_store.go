@@ -0,0 +1,59 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/runfinch/finch/pkg/lima (interfaces: InstanceStore)
+//
+// Generated by this command:
+//
+//	mockgen -copyright_file=../../copyright_header -destination=../mocks/lima_instance_store.go -package=mocks -mock_names InstanceStore=LimaInstanceStore . InstanceStore
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	store "github.com/lima-vm/lima/pkg/store"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// LimaInstanceStore is a mock of InstanceStore interface.
+type LimaInstanceStore struct {
+	ctrl     *gomock.Controller
+	recorder *LimaInstanceStoreMockRecorder
+	isgomock struct{}
+}
+
+// LimaInstanceStoreMockRecorder is the mock recorder for LimaInstanceStore.
+type LimaInstanceStoreMockRecorder struct {
+	mock *LimaInstanceStore
+}
+
+// NewLimaInstanceStore creates a new mock instance.
+func NewLimaInstanceStore(ctrl *gomock.Controller) *LimaInstanceStore {
+	mock := &LimaInstanceStore{ctrl: ctrl}
+	mock.recorder = &LimaInstanceStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *LimaInstanceStore) EXPECT() *LimaInstanceStoreMockRecorder {
+	return m.recorder
+}
+
+// Inspect mocks base method.
+func (m *LimaInstanceStore) Inspect(instanceName string) (*store.Instance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Inspect", instanceName)
+	ret0, _ := ret[0].(*store.Instance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Inspect indicates an expected call of Inspect.
+func (mr *LimaInstanceStoreMockRecorder) Inspect(instanceName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Inspect", reflect.TypeOf((*LimaInstanceStore)(nil).Inspect), instanceName)
+}
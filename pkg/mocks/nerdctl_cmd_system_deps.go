@@ -56,6 +56,21 @@ func (mr *NerdctlCommandSystemDepsMockRecorder) Env(key any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Env", reflect.TypeOf((*NerdctlCommandSystemDeps)(nil).Env), key)
 }
 
+// EvalSymlinks mocks base method.
+func (m *NerdctlCommandSystemDeps) EvalSymlinks(path string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EvalSymlinks", path)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EvalSymlinks indicates an expected call of EvalSymlinks.
+func (mr *NerdctlCommandSystemDepsMockRecorder) EvalSymlinks(path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EvalSymlinks", reflect.TypeOf((*NerdctlCommandSystemDeps)(nil).EvalSymlinks), path)
+}
+
 // FilePathAbs mocks base method.
 func (m *NerdctlCommandSystemDeps) FilePathAbs(elem string) (string, error) {
 	m.ctrl.T.Helper()
@@ -103,6 +118,21 @@ func (mr *NerdctlCommandSystemDepsMockRecorder) FilePathToSlash(elem any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilePathToSlash", reflect.TypeOf((*NerdctlCommandSystemDeps)(nil).FilePathToSlash), elem)
 }
 
+// GetUserHome mocks base method.
+func (m *NerdctlCommandSystemDeps) GetUserHome() (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserHome")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserHome indicates an expected call of GetUserHome.
+func (mr *NerdctlCommandSystemDepsMockRecorder) GetUserHome() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserHome", reflect.TypeOf((*NerdctlCommandSystemDeps)(nil).GetUserHome))
+}
+
 // GetWd mocks base method.
 func (m *NerdctlCommandSystemDeps) GetWd() (string, error) {
 	m.ctrl.T.Helper()
@@ -132,3 +162,17 @@ func (mr *NerdctlCommandSystemDepsMockRecorder) LookupEnv(key any) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LookupEnv", reflect.TypeOf((*NerdctlCommandSystemDeps)(nil).LookupEnv), key)
 }
+
+// Setenv mocks base method.
+func (m *NerdctlCommandSystemDeps) Setenv(key, value string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Setenv", key, value)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Setenv indicates an expected call of Setenv.
+func (mr *NerdctlCommandSystemDepsMockRecorder) Setenv(key, value any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Setenv", reflect.TypeOf((*NerdctlCommandSystemDeps)(nil).Setenv), key, value)
+}
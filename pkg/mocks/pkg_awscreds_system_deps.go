@@ -0,0 +1,57 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/runfinch/finch/pkg/awscreds (interfaces: SystemDeps)
+//
+// Generated by this command:
+//
+//	mockgen -copyright_file=../../copyright_header -destination=../mocks/pkg_awscreds_system_deps.go -package=mocks -mock_names SystemDeps=AWSCredsSystemDeps . SystemDeps
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// AWSCredsSystemDeps is a mock of SystemDeps interface.
+type AWSCredsSystemDeps struct {
+	ctrl     *gomock.Controller
+	recorder *AWSCredsSystemDepsMockRecorder
+	isgomock struct{}
+}
+
+// AWSCredsSystemDepsMockRecorder is the mock recorder for AWSCredsSystemDeps.
+type AWSCredsSystemDepsMockRecorder struct {
+	mock *AWSCredsSystemDeps
+}
+
+// NewAWSCredsSystemDeps creates a new mock instance.
+func NewAWSCredsSystemDeps(ctrl *gomock.Controller) *AWSCredsSystemDeps {
+	mock := &AWSCredsSystemDeps{ctrl: ctrl}
+	mock.recorder = &AWSCredsSystemDepsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *AWSCredsSystemDeps) EXPECT() *AWSCredsSystemDepsMockRecorder {
+	return m.recorder
+}
+
+// Env mocks base method.
+func (m *AWSCredsSystemDeps) Env(key string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Env", key)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Env indicates an expected call of Env.
+func (mr *AWSCredsSystemDepsMockRecorder) Env(key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Env", reflect.TypeOf((*AWSCredsSystemDeps)(nil).Env), key)
+}
@@ -83,3 +83,17 @@ func (mr *BundleConfigMockRecorder) LogFiles() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogFiles", reflect.TypeOf((*BundleConfig)(nil).LogFiles))
 }
+
+// RedactionRulesFile mocks base method.
+func (m *BundleConfig) RedactionRulesFile() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RedactionRulesFile")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// RedactionRulesFile indicates an expected call of RedactionRulesFile.
+func (mr *BundleConfigMockRecorder) RedactionRulesFile() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedactionRulesFile", reflect.TypeOf((*BundleConfig)(nil).RedactionRulesFile))
+}
@@ -0,0 +1,61 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/runfinch/finch/pkg/support (interfaces: BundleUploader)
+//
+// Generated by this command:
+//
+//	mockgen -copyright_file=../../copyright_header -destination=../mocks/pkg_support_uploader.go -package=mocks -mock_names BundleUploader=BundleUploader . BundleUploader
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	url "net/url"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// BundleUploader is a mock of BundleUploader interface.
+type BundleUploader struct {
+	ctrl     *gomock.Controller
+	recorder *BundleUploaderMockRecorder
+	isgomock struct{}
+}
+
+// BundleUploaderMockRecorder is the mock recorder for BundleUploader.
+type BundleUploaderMockRecorder struct {
+	mock *BundleUploader
+}
+
+// NewBundleUploader creates a new mock instance.
+func NewBundleUploader(ctrl *gomock.Controller) *BundleUploader {
+	mock := &BundleUploader{ctrl: ctrl}
+	mock.recorder = &BundleUploaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *BundleUploader) EXPECT() *BundleUploaderMockRecorder {
+	return m.recorder
+}
+
+// Upload mocks base method.
+func (m *BundleUploader) Upload(ctx context.Context, bundlePath string, uploadURL *url.URL) (string, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upload", ctx, bundlePath, uploadURL)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Upload indicates an expected call of Upload.
+func (mr *BundleUploaderMockRecorder) Upload(ctx, bundlePath, uploadURL any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upload", reflect.TypeOf((*BundleUploader)(nil).Upload), ctx, bundlePath, uploadURL)
+}
@@ -0,0 +1,149 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/runfinch/finch/pkg/translate (interfaces: SystemDeps)
+//
+// Generated by this command:
+//
+//	mockgen -copyright_file=../../copyright_header -destination=../mocks/pkg_translate_system_deps.go -package=mocks -mock_names SystemDeps=TranslateSystemDeps . SystemDeps
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// TranslateSystemDeps is a mock of SystemDeps interface.
+type TranslateSystemDeps struct {
+	ctrl     *gomock.Controller
+	recorder *TranslateSystemDepsMockRecorder
+	isgomock struct{}
+}
+
+// TranslateSystemDepsMockRecorder is the mock recorder for TranslateSystemDeps.
+type TranslateSystemDepsMockRecorder struct {
+	mock *TranslateSystemDeps
+}
+
+// NewTranslateSystemDeps creates a new mock instance.
+func NewTranslateSystemDeps(ctrl *gomock.Controller) *TranslateSystemDeps {
+	mock := &TranslateSystemDeps{ctrl: ctrl}
+	mock.recorder = &TranslateSystemDepsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *TranslateSystemDeps) EXPECT() *TranslateSystemDepsMockRecorder {
+	return m.recorder
+}
+
+// Env mocks base method.
+func (m *TranslateSystemDeps) Env(key string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Env", key)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Env indicates an expected call of Env.
+func (mr *TranslateSystemDepsMockRecorder) Env(key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Env", reflect.TypeOf((*TranslateSystemDeps)(nil).Env), key)
+}
+
+// FilePathAbs mocks base method.
+func (m *TranslateSystemDeps) FilePathAbs(elem string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FilePathAbs", elem)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FilePathAbs indicates an expected call of FilePathAbs.
+func (mr *TranslateSystemDepsMockRecorder) FilePathAbs(elem any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilePathAbs", reflect.TypeOf((*TranslateSystemDeps)(nil).FilePathAbs), elem)
+}
+
+// FilePathJoin mocks base method.
+func (m *TranslateSystemDeps) FilePathJoin(elem ...string) string {
+	m.ctrl.T.Helper()
+	varargs := []any{}
+	for _, a := range elem {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "FilePathJoin", varargs...)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// FilePathJoin indicates an expected call of FilePathJoin.
+func (mr *TranslateSystemDepsMockRecorder) FilePathJoin(elem ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilePathJoin", reflect.TypeOf((*TranslateSystemDeps)(nil).FilePathJoin), elem...)
+}
+
+// FilePathToSlash mocks base method.
+func (m *TranslateSystemDeps) FilePathToSlash(elem string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FilePathToSlash", elem)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// FilePathToSlash indicates an expected call of FilePathToSlash.
+func (mr *TranslateSystemDepsMockRecorder) FilePathToSlash(elem any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilePathToSlash", reflect.TypeOf((*TranslateSystemDeps)(nil).FilePathToSlash), elem)
+}
+
+// GetUserHome mocks base method.
+func (m *TranslateSystemDeps) GetUserHome() (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserHome")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserHome indicates an expected call of GetUserHome.
+func (mr *TranslateSystemDepsMockRecorder) GetUserHome() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserHome", reflect.TypeOf((*TranslateSystemDeps)(nil).GetUserHome))
+}
+
+// GetWd mocks base method.
+func (m *TranslateSystemDeps) GetWd() (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWd")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWd indicates an expected call of GetWd.
+func (mr *TranslateSystemDepsMockRecorder) GetWd() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWd", reflect.TypeOf((*TranslateSystemDeps)(nil).GetWd))
+}
+
+// LookupEnv mocks base method.
+func (m *TranslateSystemDeps) LookupEnv(key string) (string, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LookupEnv", key)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// LookupEnv indicates an expected call of LookupEnv.
+func (mr *TranslateSystemDepsMockRecorder) LookupEnv(key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LookupEnv", reflect.TypeOf((*TranslateSystemDeps)(nil).LookupEnv), key)
+}
@@ -29,6 +29,11 @@ func (fp Finch) NerdctlConfigFilePath() string {
 	return filepath.Join(string(fp), "nerdctl", "nerdctl.toml")
 }
 
+// BuildkitConfigFilePath returns the path to the BuildKit config file.
+func (fp Finch) BuildkitConfigFilePath() string {
+	return filepath.Join(string(fp), "buildkit", "buildkitd.toml")
+}
+
 // BuildkitSocketPath returns the path to the Buildkit socket file.
 func (fp Finch) BuildkitSocketPath() string {
 	return filepath.Join(fp.FinchRuntimeDataDir(), "buildkit", "buildkitd.sock")
@@ -40,6 +40,13 @@ func TestFinch_NerdctlConfigFilePath(t *testing.T) {
 	assert.Equal(t, res, filepath.Join("mock_finch", "nerdctl", "nerdctl.toml"))
 }
 
+func TestFinch_BuildkitConfigFilePath(t *testing.T) {
+	t.Parallel()
+
+	res := mockFinch.BuildkitConfigFilePath()
+	assert.Equal(t, res, filepath.Join("mock_finch", "buildkit", "buildkitd.toml"))
+}
+
 func TestFinch_BuildkitSocketPath(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,198 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package pathmap maps host paths to the paths they are visible at in the guest VM, and back.
+//
+// It does not depend on the platform it runs on: the syntax of host paths is selected with a Style,
+// so that the mapping of Windows hosts can be exercised on any platform.
+package pathmap
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Style is the syntax of host paths.
+type Style int
+
+const (
+	// POSIX paths are slash separated and case sensitive, e.g. /Users/user/src.
+	POSIX Style = iota
+	// Windows paths start with a drive letter or are UNC paths, use either separator and are case insensitive,
+	// e.g. C:\Users\user\src.
+	Windows
+)
+
+// ErrNotMapped is returned when a path is not under any mounted root.
+var ErrNotMapped = errors.New("path is not mounted")
+
+// Mount is a host directory that is visible in the guest.
+type Mount struct {
+	// Host is the absolute path of the directory on the host, in the syntax of the Map style.
+	Host string
+	// Guest is the absolute path the directory is mounted at in the guest.
+	Guest string
+}
+
+// Map converts paths between the host and the guest based on the mounted roots.
+type Map struct {
+	style  Style
+	mounts []Mount
+	// drivesRoot is the guest directory under which every drive of a Windows host is mounted, if not empty.
+	drivesRoot string
+}
+
+// New creates a Map of host paths in the given style with the given mounted roots.
+func New(style Style, mounts ...Mount) *Map {
+	return &Map{style: style, mounts: mounts}
+}
+
+// Identity creates a Map of POSIX host directories that are mounted at the same location in the guest,
+// like the shared directories of a Lima VM on macOS.
+func Identity(dirs ...string) *Map {
+	m := New(POSIX)
+	for _, d := range dirs {
+		m.mounts = append(m.mounts, Mount{Host: d, Guest: d})
+	}
+	return m
+}
+
+// WSL creates a Map of Windows host paths where every drive is mounted under /mnt/<drive letter>,
+// as WSL does by default.
+func WSL(mounts ...Mount) *Map {
+	m := New(Windows, mounts...)
+	m.drivesRoot = "/mnt"
+	return m
+}
+
+// Mounts returns the mounted roots of m, not including the drives mounted by WSL.
+func (m *Map) Mounts() []Mount {
+	return append([]Mount{}, m.mounts...)
+}
+
+// ToGuest converts the absolute host path hostPath to the path it is visible at in the guest.
+// The most specific mount wins when mounts are nested. ErrNotMapped is returned if no mount contains hostPath.
+func (m *Map) ToGuest(hostPath string) (string, error) {
+	p, err := m.clean(hostPath)
+	if err != nil {
+		return "", err
+	}
+
+	best, bestLen, rel := -1, 0, ""
+	for i, mount := range m.mounts {
+		root, err := m.clean(mount.Host)
+		if err != nil {
+			return "", fmt.Errorf("invalid mount %q: %w", mount.Host, err)
+		}
+		if r, ok := m.relative(root, p); ok && len(root) >= bestLen {
+			best, bestLen, rel = i, len(root), r
+		}
+	}
+	if best >= 0 {
+		return path.Join(m.mounts[best].Guest, rel), nil
+	}
+
+	if m.drivesRoot != "" && hasDriveLetter(p) {
+		return path.Join(m.drivesRoot, strings.ToLower(p[:1]), p[2:]), nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrNotMapped, hostPath)
+}
+
+// ToHost converts the absolute guest path guestPath to the host path it is mounted from.
+// The most specific mount wins when mounts are nested. ErrNotMapped is returned if guestPath is not mounted.
+func (m *Map) ToHost(guestPath string) (string, error) {
+	if !strings.HasPrefix(guestPath, "/") {
+		return "", fmt.Errorf("%q is not an absolute guest path", guestPath)
+	}
+	p := path.Clean(guestPath)
+
+	best, bestLen, rel := -1, 0, ""
+	for i, mount := range m.mounts {
+		root := path.Clean(mount.Guest)
+		if r, ok := posixRelative(root, p); ok && len(root) >= bestLen {
+			best, bestLen, rel = i, len(root), r
+		}
+	}
+	if best >= 0 {
+		return m.join(m.mounts[best].Host, rel), nil
+	}
+
+	if m.drivesRoot != "" {
+		if r, ok := posixRelative(path.Clean(m.drivesRoot), p); ok && r != "" {
+			drive, rest, _ := strings.Cut(r, "/")
+			if len(drive) == 1 && isLetter(drive[0]) {
+				return m.join(strings.ToUpper(drive)+`:\`, rest), nil
+			}
+		}
+	}
+	return "", fmt.Errorf("%w: %s", ErrNotMapped, guestPath)
+}
+
+// clean returns the absolute host path p in a canonical, slash separated form.
+func (m *Map) clean(p string) (string, error) {
+	if m.style == POSIX {
+		if !strings.HasPrefix(p, "/") {
+			return "", fmt.Errorf("%q is not an absolute path", p)
+		}
+		return path.Clean(p), nil
+	}
+
+	s := strings.ReplaceAll(p, `\`, "/")
+	switch {
+	case hasDriveLetter(s):
+		return strings.ToUpper(s[:1]) + ":" + path.Clean(s[2:]), nil
+	case strings.HasPrefix(s, "//"):
+		// UNC path, //server/share/...
+		return "/" + path.Clean(s), nil
+	default:
+		return "", fmt.Errorf("%q is not an absolute path", p)
+	}
+}
+
+// relative returns the slash separated path of p relative to root, and whether p is root or under it.
+func (m *Map) relative(root, p string) (string, bool) {
+	if m.style == Windows {
+		// Windows paths are case insensitive
+		if len(p) < len(root) || !strings.EqualFold(p[:len(root)], root) {
+			return "", false
+		}
+		return posixRelative(p[:len(root)], p)
+	}
+	return posixRelative(root, p)
+}
+
+// join joins the slash separated relative path rel to the host path root, in the syntax of the Map style.
+func (m *Map) join(root, rel string) string {
+	if m.style == POSIX {
+		return path.Join(root, rel)
+	}
+	p := strings.ReplaceAll(root, "/", `\`)
+	if rel == "" {
+		return p
+	}
+	return strings.TrimSuffix(p, `\`) + `\` + strings.ReplaceAll(rel, "/", `\`)
+}
+
+// posixRelative returns the path of the clean path p relative to the clean path root,
+// and whether p is root or under it.
+func posixRelative(root, p string) (string, bool) {
+	if p == root {
+		return "", true
+	}
+	prefix := strings.TrimSuffix(root, "/") + "/"
+	if !strings.HasPrefix(p, prefix) {
+		return "", false
+	}
+	return p[len(prefix):], true
+}
+
+// hasDriveLetter reports whether the slash separated path p starts with a drive letter and is absolute, e.g. C:/src.
+func hasDriveLetter(p string) bool {
+	return len(p) >= 3 && isLetter(p[0]) && p[1] == ':' && p[2] == '/'
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
@@ -0,0 +1,180 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package pathmap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMap_ToGuest(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		m       *Map
+		path    string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "identity mount",
+			m:    Identity("/Users/user", "/Volumes"),
+			path: "/Users/user/src/../app",
+			want: "/Users/user/app",
+		},
+		{
+			name: "mount root",
+			m:    Identity("/Volumes/"),
+			path: "/Volumes",
+			want: "/Volumes",
+		},
+		{
+			name:    "sibling with the same prefix is not mounted",
+			m:       Identity("/Users/user"),
+			path:    "/Users/username/src",
+			wantErr: "path is not mounted: /Users/username/src",
+		},
+		{
+			name: "most specific mount wins",
+			m:    New(POSIX, Mount{Host: "/data", Guest: "/mnt/data"}, Mount{Host: "/data/cache", Guest: "/cache"}),
+			path: "/data/cache/x",
+			want: "/cache/x",
+		},
+		{
+			name:    "relative path",
+			m:       Identity("/"),
+			path:    "src",
+			wantErr: `"src" is not an absolute path`,
+		},
+		{
+			name: "wsl drive",
+			m:    WSL(),
+			path: `C:\Users\user\src`,
+			want: "/mnt/c/Users/user/src",
+		},
+		{
+			name: "wsl drive root with forward slashes",
+			m:    WSL(),
+			path: "d:/",
+			want: "/mnt/d",
+		},
+		{
+			name: "windows mounts are case insensitive and take precedence over drives",
+			m:    WSL(Mount{Host: `C:\Users\User\.finch`, Guest: "/finch"}),
+			path: `c:\users\user\.finch\config.json`,
+			want: "/finch/config.json",
+		},
+		{
+			name:    "unc path is not mounted by wsl",
+			m:       WSL(),
+			path:    `\\server\share\src`,
+			wantErr: `path is not mounted: \\server\share\src`,
+		},
+		{
+			name: "mounted unc path",
+			m:    WSL(Mount{Host: `\\server\share`, Guest: "/share"}),
+			path: `\\server\share\src`,
+			want: "/share/src",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tc.m.ToGuest(tc.path)
+			if tc.wantErr != "" {
+				assert.EqualError(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestMap_ToHost(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		m       *Map
+		path    string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "identity mount",
+			m:    Identity("/Users/user"),
+			path: "/Users/user/src",
+			want: "/Users/user/src",
+		},
+		{
+			name: "most specific mount wins",
+			m:    New(POSIX, Mount{Host: "/data", Guest: "/mnt"}, Mount{Host: "/cache", Guest: "/mnt/cache"}),
+			path: "/mnt/cache/x",
+			want: "/cache/x",
+		},
+		{
+			name: "wsl drive",
+			m:    WSL(),
+			path: "/mnt/c/Users/user/src",
+			want: `C:\Users\user\src`,
+		},
+		{
+			name: "wsl drive root",
+			m:    WSL(),
+			path: "/mnt/d",
+			want: `D:\`,
+		},
+		{
+			name: "windows mount",
+			m:    WSL(Mount{Host: `C:\Users\user\.finch`, Guest: "/finch"}),
+			path: "/finch/config.json",
+			want: `C:\Users\user\.finch\config.json`,
+		},
+		{
+			name:    "guest only path",
+			m:       WSL(),
+			path:    "/var/lib/containerd",
+			wantErr: "path is not mounted: /var/lib/containerd",
+		},
+		{
+			name:    "relative path",
+			m:       Identity("/"),
+			path:    "src",
+			wantErr: `"src" is not an absolute guest path`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tc.m.ToHost(tc.path)
+			if tc.wantErr != "" {
+				assert.EqualError(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestMap_roundTrip(t *testing.T) {
+	t.Parallel()
+
+	m := WSL(Mount{Host: `C:\Users\user\.finch`, Guest: "/finch"})
+	for _, p := range []string{`C:\Users\user\src`, `C:\Users\user\.finch\finch.yaml`, `E:\`} {
+		guest, err := m.ToGuest(p)
+		require.NoError(t, err)
+		host, err := m.ToHost(guest)
+		require.NoError(t, err)
+		assert.Equal(t, p, host)
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package support
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// The formats of the support bundles.
+const (
+	FormatZip   = "zip"
+	FormatTarGz = "tar.gz"
+)
+
+// bundleExtension returns the file extension of the bundles in format, e.g. ".zip".
+func bundleExtension(format string) string {
+	return "." + format
+}
+
+// formatFromFileName returns the format of the bundle file name by its extension, or "" if it has none.
+func formatFromFileName(fileName string) string {
+	switch {
+	case strings.HasSuffix(fileName, bundleExtension(FormatZip)):
+		return FormatZip
+	case strings.HasSuffix(fileName, bundleExtension(FormatTarGz)), strings.HasSuffix(fileName, ".tgz"):
+		return FormatTarGz
+	default:
+		return ""
+	}
+}
+
+// trimBundleExtension returns the file name without its bundle extension, if any.
+func trimBundleExtension(fileName string) string {
+	for _, ext := range []string{bundleExtension(FormatZip), bundleExtension(FormatTarGz), ".tgz"} {
+		if strings.HasSuffix(fileName, ext) {
+			return strings.TrimSuffix(fileName, ext)
+		}
+	}
+	return fileName
+}
+
+// archiveWriter writes the entries of a bundle in an archive format.
+type archiveWriter interface {
+	writeDir(name string) error
+	writeFile(name string, size int64, content io.Reader) error
+	// Close writes the end of the archive, without closing the underlying writer.
+	Close() error
+}
+
+func newArchiveWriter(w io.Writer, format string) (archiveWriter, error) {
+	switch format {
+	case FormatZip:
+		return &zipArchive{zip: zip.NewWriter(w)}, nil
+	case FormatTarGz:
+		gz := gzip.NewWriter(w)
+		return &tarGzArchive{gz: gz, tar: tar.NewWriter(gz), modTime: time.Now()}, nil
+	default:
+		return nil, fmt.Errorf("unsupported bundle format %q, must be %q or %q", format, FormatZip, FormatTarGz)
+	}
+}
+
+type zipArchive struct {
+	zip *zip.Writer
+}
+
+func (a *zipArchive) writeDir(name string) error {
+	_, err := a.zip.Create(name + "/")
+	return err
+}
+
+func (a *zipArchive) writeFile(name string, _ int64, content io.Reader) error {
+	w, err := a.zip.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, content)
+	return err
+}
+
+func (a *zipArchive) Close() error {
+	return a.zip.Close()
+}
+
+type tarGzArchive struct {
+	gz      *gzip.Writer
+	tar     *tar.Writer
+	modTime time.Time
+}
+
+func (a *tarGzArchive) writeDir(name string) error {
+	return a.tar.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeDir,
+		Name:     name + "/",
+		Mode:     0o755,
+		ModTime:  a.modTime,
+	})
+}
+
+func (a *tarGzArchive) writeFile(name string, size int64, content io.Reader) error {
+	err := a.tar.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Mode:     0o644,
+		Size:     size,
+		ModTime:  a.modTime,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(a.tar, content)
+	return err
+}
+
+func (a *tarGzArchive) Close() error {
+	if err := a.tar.Close(); err != nil {
+		return err
+	}
+	return a.gz.Close()
+}
@@ -0,0 +1,223 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package support
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+// Bundle is a support bundle read from its archive.
+type Bundle struct {
+	// Format is the format of the archive, FormatZip or FormatTarGz.
+	Format string
+	// Files are the files of the bundle, in the order of the archive.
+	Files []*BundleFile
+}
+
+// BundleFile is a file of a support bundle.
+type BundleFile struct {
+	// Path is the path of the file relative to the directory of the bundle, e.g. "logs/ha.stderr.log".
+	Path    string
+	Size    int64
+	content []byte
+}
+
+// Content returns the content of the file.
+func (f *BundleFile) Content() []byte {
+	return f.content
+}
+
+// ReadBundle reads the support bundle of the archive bundlePath, in either format.
+func ReadBundle(afs afero.Fs, bundlePath string) (*Bundle, error) {
+	bundle := &Bundle{}
+	format, err := walkBundle(afs, bundlePath, func(name string, r io.Reader) error {
+		content, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		bundle.add(name, content)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	bundle.Format = format
+	return bundle, nil
+}
+
+// walkBundle calls fn with the name and the content of each file of the archive bundlePath, in the order
+// of the archive, streaming their content instead of reading the bundle in memory. It returns the format of the archive.
+func walkBundle(afs afero.Fs, bundlePath string, fn func(name string, r io.Reader) error) (string, error) {
+	f, err := afs.Open(bundlePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the support bundle: %w", err)
+	}
+	defer f.Close() //nolint:errcheck // the file is only read
+
+	magic := make([]byte, 4)
+	n, err := io.ReadFull(f, magic)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("failed to read the support bundle: %w", err)
+	}
+	magic = magic[:n]
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return "", fmt.Errorf("failed to read the support bundle: %w", err)
+	}
+
+	var format string
+	switch {
+	case bytes.HasPrefix(magic, []byte("PK\x03\x04")):
+		format, err = FormatZip, walkZipBundle(f, fn)
+	case bytes.HasPrefix(magic, []byte{0x1f, 0x8b}):
+		format, err = FormatTarGz, walkTarGzBundle(f, fn)
+	default:
+		return "", fmt.Errorf("%s is not a %s or %s support bundle", bundlePath, FormatZip, FormatTarGz)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read the support bundle %s: %w", bundlePath, err)
+	}
+	return format, nil
+}
+
+func walkZipBundle(f afero.File, fn func(name string, r io.Reader) error) error {
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	zr, err := zip.NewReader(f, info.Size())
+	if err != nil {
+		return err
+	}
+	for _, zf := range zr.File {
+		if zf.FileInfo().IsDir() {
+			continue
+		}
+		rc, err := zf.Open()
+		if err != nil {
+			return err
+		}
+		err = fn(zf.Name, rc)
+		_ = rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func walkTarGzBundle(f afero.File, fn func(name string, r io.Reader) error) error {
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err := fn(hdr.Name, tr); err != nil {
+			return err
+		}
+	}
+}
+
+// bundleFilePath returns the path of the file name of the archive, relative to the directory of the bundle.
+func bundleFilePath(name string) string {
+	_, filePath, found := strings.Cut(path.Clean(name), "/")
+	if !found {
+		return name
+	}
+	return filePath
+}
+
+// add adds the file name of the archive to the bundle, without the directory of the bundle.
+func (bundle *Bundle) add(name string, content []byte) {
+	bundle.Files = append(bundle.Files, &BundleFile{Path: bundleFilePath(name), Size: int64(len(content)), content: content})
+}
+
+// File returns the file of the bundle at filePath, relative to the directory of the bundle.
+func (bundle *Bundle) File(filePath string) (*BundleFile, bool) {
+	filePath = path.Clean(filePath)
+	for _, f := range bundle.Files {
+		if f.Path == filePath {
+			return f, true
+		}
+	}
+	return nil, false
+}
+
+// Manifest returns the manifest of the bundle, or nil if the bundle has none, e.g. as it predates them.
+func (bundle *Bundle) Manifest() (*Manifest, error) {
+	f, ok := bundle.File(manifestFileName)
+	if !ok {
+		return nil, nil
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(f.content, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the manifest of the support bundle: %w", err)
+	}
+	return &manifest, nil
+}
+
+// VerifyBundle returns an error if the support bundle of the archive bundlePath has no manifest, or if a file
+// of its manifest is missing or does not match its checksum, e.g. as the bundle was modified after it was generated.
+// The files are streamed through their checksum, so that large bundles are not read in memory.
+func VerifyBundle(afs afero.Fs, bundlePath string) error {
+	checksums := map[string]string{}
+	var manifest *Manifest
+	_, err := walkBundle(afs, bundlePath, func(name string, r io.Reader) error {
+		filePath := bundleFilePath(name)
+		hash := sha256.New()
+		if filePath == manifestFileName {
+			manifest = &Manifest{}
+			if err := json.NewDecoder(io.TeeReader(r, hash)).Decode(manifest); err != nil {
+				return fmt.Errorf("failed to unmarshal the manifest of the support bundle: %w", err)
+			}
+		}
+		if _, err := io.Copy(hash, r); err != nil {
+			return err
+		}
+		checksums[filePath] = hex.EncodeToString(hash.Sum(nil))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if manifest == nil {
+		return errors.New("the support bundle has no manifest")
+	}
+	for _, item := range manifest.Items {
+		if item.Path == "" {
+			continue
+		}
+		sum, ok := checksums[path.Clean(item.Path)]
+		if !ok {
+			return fmt.Errorf("%s of the manifest is missing from the support bundle", item.Path)
+		}
+		if sum != item.SHA256 {
+			return fmt.Errorf("the checksum of %s does not match the manifest of the support bundle", item.Path)
+		}
+	}
+	return nil
+}
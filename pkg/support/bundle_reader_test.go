@@ -0,0 +1,187 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package support
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadBundle(t *testing.T) {
+	t.Parallel()
+
+	for _, format := range []string{FormatZip, FormatTarGz} {
+		t.Run(format, func(t *testing.T) {
+			t.Parallel()
+
+			bw := newTestBundleWriter(t, "finch-support")
+			manifest := newManifest(&BundleCfg{})
+			for name, content := range map[string]string{"logs/ha.stderr.log": "started\n", "configs/finch.yaml": "cpus: 2\n"} {
+				require.NoError(t, bw.record(manifest, name, nil, func(writer zipCreator) error {
+					w, err := writer.Create("finch-support/" + name)
+					require.NoError(t, err)
+					_, err = io.WriteString(w, content)
+					return err
+				}))
+			}
+			manifest.Items[0].Truncated = true
+			require.NoError(t, bw.writeUnrecorded(func(w zipCreator) error {
+				return writeManifest(w, manifest, "finch-support")
+			}))
+
+			var b bytes.Buffer
+			archive, err := newArchiveWriter(&b, format)
+			require.NoError(t, err)
+			require.NoError(t, bw.write(archive))
+			require.NoError(t, archive.Close())
+			fs := afero.NewMemMapFs()
+			require.NoError(t, afero.WriteFile(fs, "bundle", b.Bytes(), 0o644))
+
+			bundle, err := ReadBundle(fs, "bundle")
+			require.NoError(t, err)
+			assert.Equal(t, format, bundle.Format)
+			require.Len(t, bundle.Files, 3)
+			assert.Equal(t, manifestFileName, bundle.Files[2].Path)
+
+			f, ok := bundle.File("./logs/ha.stderr.log")
+			require.True(t, ok)
+			assert.Equal(t, int64(8), f.Size)
+			assert.Equal(t, "started\n", string(f.Content()))
+			_, ok = bundle.File("logs/missing.log")
+			assert.False(t, ok)
+
+			got, err := bundle.Manifest()
+			require.NoError(t, err)
+			require.Len(t, got.Items, 2)
+			assert.Equal(t, manifest.Items[0].Path, got.Items[0].Path)
+			assert.True(t, got.Items[0].Truncated)
+		})
+	}
+}
+
+func TestReadBundle_errors(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "bundle.txt", []byte("not a bundle"), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "bundle.zip", []byte("PK\x03\x04truncated"), 0o644))
+
+	_, err := ReadBundle(fs, "missing.zip")
+	assert.ErrorIs(t, err, afero.ErrFileNotFound)
+	_, err = ReadBundle(fs, "bundle.txt")
+	assert.EqualError(t, err, "bundle.txt is not a zip or tar.gz support bundle")
+	_, err = ReadBundle(fs, "bundle.zip")
+	assert.ErrorContains(t, err, "failed to read the support bundle bundle.zip")
+}
+
+func TestBundle_Manifest_missing(t *testing.T) {
+	t.Parallel()
+
+	bundle := &Bundle{}
+	bundle.add("finch-support/logs/ha.stderr.log", []byte("started\n"))
+	assert.Equal(t, "logs/ha.stderr.log", bundle.Files[0].Path)
+
+	manifest, err := bundle.Manifest()
+	assert.NoError(t, err)
+	assert.Nil(t, manifest)
+}
+
+func TestVerifyBundle(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name string
+		// editManifest edits the manifest of the bundle, which is left out if it returns nil.
+		editManifest func(*Manifest) *Manifest
+		wantErr      string
+	}{
+		{
+			name:         "bundle matches its manifest",
+			editManifest: func(manifest *Manifest) *Manifest { return manifest },
+		},
+		{
+			name: "file modified",
+			editManifest: func(manifest *Manifest) *Manifest {
+				manifest.Items[0].SHA256 = checksum([]byte("modified\n"))
+				return manifest
+			},
+			wantErr: "the checksum of logs/ha.stderr.log does not match the manifest of the support bundle",
+		},
+		{
+			name: "file missing",
+			editManifest: func(manifest *Manifest) *Manifest {
+				manifest.Items = append(manifest.Items, ManifestItem{Path: "logs/missing.log", SHA256: checksum(nil)})
+				return manifest
+			},
+			wantErr: "logs/missing.log of the manifest is missing from the support bundle",
+		},
+		{
+			name:         "manifest missing",
+			editManifest: func(*Manifest) *Manifest { return nil },
+			wantErr:      "the support bundle has no manifest",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			fs := afero.NewMemMapFs()
+			writeEditedTestBundle(t, fs, "bundle.zip", map[string]string{"logs/ha.stderr.log": "started\n"}, tc.editManifest)
+
+			err := VerifyBundle(fs, "bundle.zip")
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.wantErr)
+			}
+		})
+	}
+}
+
+// writeTestBundle writes a zip support bundle of the files, followed by its manifest, to bundlePath.
+func writeTestBundle(t *testing.T, fs afero.Fs, bundlePath string, files map[string]string) {
+	t.Helper()
+	writeEditedTestBundle(t, fs, bundlePath, files, func(manifest *Manifest) *Manifest { return manifest })
+}
+
+// writeEditedTestBundle writes a zip support bundle of the files to bundlePath, followed by its manifest
+// edited with editManifest, unless it returns nil.
+func writeEditedTestBundle(
+	t *testing.T,
+	fs afero.Fs,
+	bundlePath string,
+	files map[string]string,
+	editManifest func(*Manifest) *Manifest,
+) {
+	t.Helper()
+
+	bw := newTestBundleWriter(t, "finch-support")
+	manifest := newManifest(&BundleCfg{})
+	for name, content := range files {
+		require.NoError(t, bw.record(manifest, name, nil, func(writer zipCreator) error {
+			w, err := writer.Create("finch-support/" + name)
+			require.NoError(t, err)
+			_, err = io.WriteString(w, content)
+			return err
+		}))
+	}
+	if manifest = editManifest(manifest); manifest != nil {
+		require.NoError(t, bw.writeUnrecorded(func(w zipCreator) error {
+			return writeManifest(w, manifest, "finch-support")
+		}))
+	}
+
+	var b bytes.Buffer
+	archive, err := newArchiveWriter(&b, FormatZip)
+	require.NoError(t, err)
+	require.NoError(t, bw.write(archive))
+	require.NoError(t, archive.Close())
+	require.NoError(t, afero.WriteFile(fs, bundlePath, b.Bytes(), 0o644))
+}
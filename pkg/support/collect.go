@@ -0,0 +1,113 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package support
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/runfinch/finch/pkg/command"
+)
+
+const (
+	// DefaultTimeout is how long a bundle can take to be collected when it is generated with the CLI.
+	DefaultTimeout = 10 * time.Minute
+	// DefaultItemTimeout is how long an item of a bundle can take to be collected if BundleCfg.ItemTimeout is 0.
+	DefaultItemTimeout = 2 * time.Minute
+	// defaultBundleWorkers is how many items of a bundle are collected concurrently. Each item collected from the VM
+	// runs its own limactl shell, so they are kept few.
+	defaultBundleWorkers = 4
+)
+
+// collectItems collects the items with a bounded number of workers, then adds them to the bundle in order,
+// so that the bundle does not depend on the order the items complete in.
+// If ctx is done, the items being collected are stopped, and the items left are skipped.
+func (bb *bundleBuilder) collectItems(
+	ctx context.Context,
+	bw *bundleWriter,
+	manifest *Manifest,
+	items []*bundleItem,
+	itemTimeout time.Duration,
+) {
+	queue := make(chan *bundleItem)
+	var wg sync.WaitGroup
+	for range min(bb.workers, len(items)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for item := range queue {
+				bb.collectItem(ctx, bw, item, itemTimeout)
+			}
+		}()
+	}
+	for _, item := range items {
+		if !item.excluded {
+			queue <- item
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	for _, item := range items {
+		bw.add(manifest, item)
+	}
+}
+
+func (bb *bundleBuilder) collectItem(ctx context.Context, bw *bundleWriter, item *bundleItem, timeout time.Duration) {
+	if err := ctx.Err(); err != nil {
+		item.err = fmt.Errorf("skipped, as %s", interruption(ctx))
+		return
+	}
+
+	if item.started != "" {
+		bb.logger.Debugf(item.started, item.source)
+	}
+	itemCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	bw.collect(itemCtx, item)
+	if item.err != nil && itemCtx.Err() != nil {
+		if ctx.Err() != nil {
+			item.err = fmt.Errorf("stopped, as %s", interruption(ctx))
+		} else {
+			item.err = fmt.Errorf("timed out after %s", timeout)
+		}
+	}
+	if item.err != nil && item.warning != "" {
+		bb.logger.Warnf(item.warning, item.source, item.err)
+	}
+}
+
+// interruption returns why the collection of the bundle was interrupted, once ctx is done.
+func interruption(ctx context.Context) string {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return "the support bundle timed out"
+	}
+	return "the support bundle was interrupted"
+}
+
+// runCommand starts the command, and waits until it exits or ctx is done, in which case the command is killed.
+// The writers of the command may still be written to after it returns, if the command does not exit when it is killed.
+func (bb *bundleBuilder) runCommand(ctx context.Context, cmd command.Command) error {
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	waitStatus := make(chan error, 1)
+	go func() {
+		waitStatus <- cmd.Wait()
+	}()
+
+	select {
+	case err := <-waitStatus:
+		return err
+	case <-ctx.Done():
+		if err := cmd.Signal(os.Kill); err != nil {
+			bb.logger.Debugf("Failed to kill the command: %v", err)
+		}
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,239 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package support
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/user"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/runfinch/finch/pkg/mocks"
+	fpath "github.com/runfinch/finch/pkg/path"
+)
+
+// testItem returns an item writing content in the log name, once ready is closed, if not nil.
+func testItem(name, content string, ready <-chan struct{}) *bundleItem {
+	return &bundleItem{
+		source: name,
+		collect: func(ctx context.Context, w zipCreator) error {
+			if ready != nil {
+				select {
+				case <-ready:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+			entry, err := w.Create("bundle/logs/" + name)
+			if err != nil {
+				return err
+			}
+			_, err = io.WriteString(entry, content)
+			return err
+		},
+		warning: "Could not copy in %q. Error: %s",
+	}
+}
+
+func TestBundleBuilder_collectItems(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name         string
+		items        func() []*bundleItem
+		cancel       bool
+		itemTimeout  time.Duration
+		mockSvc      func(*mocks.Logger)
+		wantEntries  []string
+		wantManifest []ManifestItem
+	}{
+		{
+			name: "items added in order",
+			items: func() []*bundleItem {
+				// the first item completes after the last one
+				lastDone := make(chan struct{})
+				last := testItem("third", "3\n", nil)
+				collect := last.collect
+				last.collect = func(ctx context.Context, w zipCreator) error {
+					defer close(lastDone)
+					return collect(ctx, w)
+				}
+				return []*bundleItem{
+					testItem("first", "1\n", lastDone),
+					{source: "excluded", excluded: true},
+					testItem("second", "2\n", nil),
+					last,
+				}
+			},
+			itemTimeout: time.Minute,
+			mockSvc:     func(*mocks.Logger) {},
+			wantEntries: []string{"logs/first", "logs/second", "logs/third"},
+			wantManifest: []ManifestItem{
+				{Source: "first", Path: "logs/first", Size: 2},
+				{Source: "excluded", Excluded: true},
+				{Source: "second", Path: "logs/second", Size: 2},
+				{Source: "third", Path: "logs/third", Size: 2},
+			},
+		},
+		{
+			name: "item timed out",
+			items: func() []*bundleItem {
+				return []*bundleItem{testItem("hung", "", make(chan struct{})), testItem("log", "log\n", nil)}
+			},
+			itemTimeout: 10 * time.Millisecond,
+			mockSvc: func(logger *mocks.Logger) {
+				logger.EXPECT().Warnf("Could not copy in %q. Error: %s", "hung", errors.New("timed out after 10ms"))
+			},
+			wantEntries: []string{"logs/log"},
+			wantManifest: []ManifestItem{
+				{Source: "hung", Error: "timed out after 10ms"},
+				{Source: "log", Path: "logs/log", Size: 4},
+			},
+		},
+		{
+			name: "bundle interrupted",
+			items: func() []*bundleItem {
+				return []*bundleItem{testItem("log", "log\n", nil)}
+			},
+			cancel:      true,
+			itemTimeout: time.Minute,
+			mockSvc:     func(*mocks.Logger) {},
+			wantEntries: []string{},
+			wantManifest: []ManifestItem{
+				{Source: "log", Error: "skipped, as the support bundle was interrupted"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			logger := mocks.NewLogger(ctrl)
+			tc.mockSvc(logger)
+			bb := &bundleBuilder{logger: logger, workers: defaultBundleWorkers}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tc.cancel {
+				cancel()
+			}
+			bw := newTestBundleWriter(t, "bundle")
+			manifest := newManifest(&BundleCfg{})
+			bb.collectItems(ctx, bw, manifest, tc.items(), tc.itemTimeout)
+
+			entries := []string{}
+			for _, e := range bw.entries {
+				entries = append(entries, e.path)
+			}
+			assert.Equal(t, tc.wantEntries, entries)
+			require.Len(t, manifest.Items, len(tc.wantManifest))
+			for i, want := range tc.wantManifest {
+				got := manifest.Items[i]
+				assert.Equal(t, want.Source, got.Source)
+				assert.Equal(t, want.Path, got.Path)
+				assert.Equal(t, want.Size, got.Size)
+				assert.Equal(t, want.Excluded, got.Excluded)
+				assert.Equal(t, want.Error, got.Error)
+			}
+		})
+	}
+}
+
+func TestBundleBuilder_runCommand(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	cmd := mocks.NewCommand(ctrl)
+	bb := &bundleBuilder{}
+
+	cmd.EXPECT().Start().Return(nil)
+	cmd.EXPECT().Wait().Return(errors.New("exit status 1"))
+	assert.EqualError(t, bb.runCommand(context.Background(), cmd), "exit status 1")
+
+	cmd.EXPECT().Start().Return(errors.New("not found"))
+	assert.EqualError(t, bb.runCommand(context.Background(), cmd), "not found")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	killed, waited := make(chan struct{}), make(chan struct{})
+	cmd.EXPECT().Start().DoAndReturn(func() error {
+		cancel()
+		return nil
+	})
+	cmd.EXPECT().Wait().DoAndReturn(func() error {
+		defer close(waited)
+		<-killed
+		return errors.New("signal: killed")
+	})
+	cmd.EXPECT().Signal(os.Kill).DoAndReturn(func(os.Signal) error {
+		close(killed)
+		return nil
+	})
+	assert.ErrorIs(t, bb.runCommand(ctx, cmd), context.Canceled)
+	<-waited
+}
+
+func TestBundleBuilder_GenerateSupportBundle_partial(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	fs := afero.NewMemMapFs()
+	logger := mocks.NewLogger(ctrl)
+	config := mocks.NewBundleConfig(ctrl)
+	ecc := mocks.NewCommandCreator(ctrl)
+	lima := mocks.NewMockLimaWrapper(ctrl)
+	cmd := mocks.NewCommand(ctrl)
+	systemDeps := mocks.NewSupportSystemDeps(ctrl)
+	bb := &bundleBuilder{
+		logger:     logger,
+		fs:         fs,
+		config:     config,
+		finch:      fpath.Finch("mockfinch"),
+		ecc:        ecc,
+		lima:       lima,
+		systemDeps: systemDeps,
+		workers:    defaultBundleWorkers,
+	}
+
+	config.EXPECT().RedactionRulesFile().Return("redaction-rules.yaml")
+	config.EXPECT().LogFiles().Return([]string{"log1"})
+	config.EXPECT().JournalServices().Return([]string{})
+	config.EXPECT().ConfigFiles().Return([]string{})
+	ecc.EXPECT().Create(gomock.Any(), gomock.Any()).Return(cmd).AnyTimes()
+	cmd.EXPECT().Output().Return([]byte("1.2.3\n"), nil).AnyTimes()
+	systemDeps.EXPECT().Executable().Return("/bin/path", nil).AnyTimes()
+	lima.EXPECT().LimaUser(false).Return(&user.User{Username: "mockuser"}).AnyTimes()
+	logger.EXPECT().Debugf("Creating %s...", gomock.Any())
+	logger.EXPECT().Debugln(gomock.Any()).AnyTimes()
+	logger.EXPECT().Warnf("Writing a partial support bundle, as %s", "the support bundle was interrupted")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	bundleFile, err := bb.GenerateSupportBundle(ctx, &BundleCfg{LogLines: 100})
+	assert.EqualError(t, err, "the support bundle is partial: the support bundle was interrupted")
+	assert.ErrorIs(t, err, ErrPartialBundle)
+	assert.True(t, strings.HasSuffix(bundleFile, "-partial.zip"), bundleFile)
+
+	bundle, err := afero.ReadFile(fs, bundleFile)
+	require.NoError(t, err)
+	manifest := readManifest(t, bundle, FormatZip)
+	assert.True(t, manifest.Partial)
+	assert.Equal(t, "the support bundle was interrupted", manifest.PartialReason)
+	sources := map[string]ManifestItem{}
+	for _, item := range manifest.Items {
+		sources[item.Source] = item
+	}
+	assert.Contains(t, sources, "platform")
+	assert.Equal(t, "skipped, as the support bundle was interrupted", sources["log1"].Error)
+}
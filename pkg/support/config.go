@@ -19,6 +19,8 @@ type BundleConfig interface {
 	LogFiles() []string
 	ConfigFiles() []string
 	JournalServices() []string
+	// RedactionRulesFile returns the path of the default file of user-defined redaction rules.
+	RedactionRulesFile() string
 }
 
 // NewBundleConfig creates a new bundleConfig.
@@ -5,8 +5,23 @@
 
 package support
 
+import (
+	"path/filepath"
+
+	fpath "github.com/runfinch/finch/pkg/path"
+)
+
+const (
+	// containerdConfigFile is the config of the containerd service of the host, which the finch package depends on.
+	containerdConfigFile = "/etc/containerd/config.toml"
+	// cniConfigDir is the directory of the CNI configs of the networks created by nerdctl.
+	cniConfigDir = "/etc/cni/net.d"
+	// finchDaemonSocketUnit is the systemd socket of finch-daemon, listening on /run/finch.sock.
+	finchDaemonSocketUnit = "finch.socket"
+)
+
 func (bc *bundleConfig) LogFiles() []string {
-	// TODO: add support for dumping logs from journalctl?
+	// the services log to the journal, which is collected with JournalServices
 	files := []string{}
 	return files
 }
@@ -14,9 +29,39 @@ func (bc *bundleConfig) LogFiles() []string {
 func (bc *bundleConfig) ConfigFiles() []string {
 	return []string{
 		bc.finch.ConfigFilePath(),
+		bc.finch.NerdctlConfigFilePath(),
+		bc.finch.BuildkitConfigFilePath(),
+		containerdConfigFile,
 	}
 }
 
 func (bc *bundleConfig) JournalServices() []string {
-	return []string{"service:containerd", "service:finch", "service:buildkit", "service:soci"}
+	// the services of the finch package, see contrib/packaging/config
+	return []string{"service:containerd", "service:finch", "service:finch-buildkit", "service:finch-soci"}
+}
+
+func (bc *bundleConfig) RedactionRulesFile() string {
+	return filepath.Join(bc.finch.FinchDir(), redactionRulesFileName)
+}
+
+// platformDiagnostics returns the diagnostics included in the bundles.
+func platformDiagnostics(finch fpath.Finch) []Diagnostic {
+	return append(commonDiagnostics(),
+		// each conflist is printed after a "==> <path> <==" header
+		Diagnostic{
+			Name: "cni-conflists",
+			Kind: DiagnosticSystem,
+			Args: []string{"find", cniConfigDir, "-name", "*.conflist", "-exec", "tail", "-v", "-n", "+1", "{}", "+"},
+		},
+		Diagnostic{
+			Name: "finch-state",
+			Kind: DiagnosticSystem,
+			Args: []string{"find", finch.FinchRuntimeDataDir(), "-maxdepth", "3", "-ls"},
+		},
+		Diagnostic{
+			Name: "finch-daemon-socket",
+			Kind: DiagnosticSystem,
+			Args: []string{"systemctl", "status", "--no-pager", finchDaemonSocketUnit},
+		},
+	)
 }
@@ -9,6 +9,8 @@ import (
 	"path"
 	"path/filepath"
 	"runtime"
+
+	fpath "github.com/runfinch/finch/pkg/path"
 )
 
 func (bc *bundleConfig) LogFiles() []string {
@@ -33,3 +35,12 @@ func (bc *bundleConfig) ConfigFiles() []string {
 func (bc *bundleConfig) JournalServices() []string {
 	return []string{"service:containerd", "service:finch", "service:buildkit", "service:soci"}
 }
+
+func (bc *bundleConfig) RedactionRulesFile() string {
+	return filepath.Join(bc.finch.FinchDir(bc.rootDir), redactionRulesFileName)
+}
+
+// platformDiagnostics returns the diagnostics included in the bundles.
+func platformDiagnostics(_ fpath.Finch) []Diagnostic {
+	return append(commonDiagnostics(), Diagnostic{Name: "limactl-ls", Kind: DiagnosticLimactl, Args: []string{"ls", "--json"}})
+}
@@ -59,3 +59,21 @@ func TestBundleConfig_ConfigFiles(t *testing.T) {
 		assert.True(t, filepath.IsAbs(fileName))
 	}
 }
+
+func TestBundleConfig_RedactionRulesFile(t *testing.T) {
+	t.Parallel()
+
+	var homeDir string
+	var finch fpath.Finch
+	if runtime.GOOS == "windows" {
+		finch = fpath.Finch("C:\\mockfinch")
+		homeDir = "C:\\mockhome"
+	} else {
+		finch = fpath.Finch("/mockfinch")
+		homeDir = "/mockhome"
+	}
+	config := NewBundleConfig(finch, homeDir)
+
+	assert.True(t, filepath.IsAbs(config.RedactionRulesFile()))
+	assert.Equal(t, "redaction-rules.yaml", filepath.Base(config.RedactionRulesFile()))
+}
@@ -0,0 +1,188 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package support
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/runfinch/finch/pkg/command"
+)
+
+const (
+	diagnosticsPrefix = "diagnostics"
+	allDiagnostics    = "diag:all"
+	// defaultDiagnosticTimeout is how long the command of a diagnostic can run if it has no timeout.
+	defaultDiagnosticTimeout = 10 * time.Second
+)
+
+// DiagnosticKind is the kind of the command of a Diagnostic, which determines where it runs.
+type DiagnosticKind int
+
+const (
+	// DiagnosticNerdctl runs nerdctl, in the VM on macOS and Windows.
+	DiagnosticNerdctl DiagnosticKind = iota
+	// DiagnosticSystem runs a system command, in the VM on macOS and Windows.
+	DiagnosticSystem
+	// DiagnosticLimactl runs limactl on the host.
+	DiagnosticLimactl
+	// DiagnosticFinch runs the current finch executable on the host.
+	DiagnosticFinch
+)
+
+// Diagnostic is a command whose output is included in the bundles, as a snapshot of the state of the system.
+type Diagnostic struct {
+	// Name identifies the diagnostic, e.g. to exclude it with "diag:<name>".
+	// Its output is saved to diagnostics/<name>.txt.
+	Name string
+	Kind DiagnosticKind
+	Args []string
+	// Timeout is how long the command can run, or defaultDiagnosticTimeout if it is 0.
+	Timeout time.Duration
+}
+
+// commonDiagnostics returns the diagnostics of the containers and of the system running them.
+func commonDiagnostics() []Diagnostic {
+	return []Diagnostic{
+		{Name: "nerdctl-info", Kind: DiagnosticNerdctl, Args: []string{"info"}},
+		{Name: "nerdctl-ps", Kind: DiagnosticNerdctl, Args: []string{"ps", "-a"}},
+		{Name: "nerdctl-images", Kind: DiagnosticNerdctl, Args: []string{"images"}},
+		{Name: "nerdctl-network-ls", Kind: DiagnosticNerdctl, Args: []string{"network", "ls"}},
+		{Name: "nerdctl-volume-ls", Kind: DiagnosticNerdctl, Args: []string{"volume", "ls"}},
+		// computing the disk usage of the images and containers can be slow
+		{Name: "nerdctl-system-df", Kind: DiagnosticNerdctl, Args: []string{"system", "df"}, Timeout: 30 * time.Second},
+		{Name: "finch-version", Kind: DiagnosticFinch, Args: []string{"version", "--format", "json"}},
+		{Name: "df", Kind: DiagnosticSystem, Args: []string{"df", "-h"}},
+		// cgroup2fs for cgroup v2, tmpfs for cgroup v1
+		{Name: "cgroup-version", Kind: DiagnosticSystem, Args: []string{"stat", "-fc", "%T", "/sys/fs/cgroup"}},
+		{Name: "kernel", Kind: DiagnosticSystem, Args: []string{"uname", "-a"}},
+	}
+}
+
+func isDiagnostic(filename string) bool {
+	return strings.HasPrefix(filename, "diag:")
+}
+
+// diagnosticItems returns the items of the bundle running the diagnostics, and copying their outputs in the bundle.
+func (bb *bundleBuilder) diagnosticItems(redaction *bundleRedaction, excludeFiles []string, bundleDir string) []*bundleItem {
+	if slices.Contains(excludeFiles, allDiagnostics) {
+		bb.logger.Info("Excluding all diagnostics...")
+		return []*bundleItem{{source: allDiagnostics, excluded: true}}
+	}
+
+	bb.logger.Debugln("Collecting diagnostics...")
+	var items []*bundleItem
+	for _, diag := range bb.diagnostics {
+		source := "diag:" + diag.Name
+		if slices.Contains(excludeFiles, source) {
+			bb.logger.Infof("Excluding %s...", source)
+			items = append(items, &bundleItem{source: source, excluded: true})
+			continue
+		}
+		itemRedaction := redaction.forItem(source)
+		items = append(items, &bundleItem{
+			source:    source,
+			redaction: itemRedaction,
+			collect: func(ctx context.Context, w zipCreator) error {
+				return bb.collectDiagnostic(ctx, w, itemRedaction, diag, path.Join(bundleDir, diagnosticsPrefix))
+			},
+			started: "Collecting %s...",
+			warning: "Could not collect %q. Error: %s",
+		})
+	}
+	return items
+}
+
+// collectDiagnostic runs the diagnostic, and copies its output in the bundle, even if it fails.
+func (bb *bundleBuilder) collectDiagnostic(
+	ctx context.Context,
+	writer zipCreator,
+	redaction *itemRedaction,
+	diag Diagnostic,
+	prefix string,
+) error {
+	out, runErr := bb.runDiagnostic(ctx, diag)
+	if runErr != nil && out == nil {
+		return runErr
+	}
+
+	diagCopy, err := writer.Create(path.Join(prefix, diag.Name+".txt"))
+	if err != nil {
+		return err
+	}
+	err = bb.copyAndRedactFile(diagCopy, bufio.NewReader(bytes.NewReader(out)), redaction)
+	if err != nil {
+		return err
+	}
+	return runErr
+}
+
+// runDiagnostic runs the command of the diagnostic, and returns its output.
+// The command is killed if it runs longer than the timeout of the diagnostic or ctx is done,
+// in which case its output is nil.
+func (bb *bundleBuilder) runDiagnostic(ctx context.Context, diag Diagnostic) ([]byte, error) {
+	cmd, err := bb.diagnosticCommand(diag)
+	if err != nil {
+		return nil, err
+	}
+	stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+	cmd.SetStdout(stdout)
+	cmd.SetStderr(stderr)
+
+	timeout := diag.Timeout
+	if timeout == 0 {
+		timeout = defaultDiagnosticTimeout
+	}
+	diagCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	err = bb.runCommand(diagCtx, cmd)
+	if err != nil && diagCtx.Err() != nil {
+		// the output is not read, as the command may still be writing it
+		if ctx.Err() == nil {
+			return nil, fmt.Errorf("timed out after %s", timeout)
+		}
+		return nil, ctx.Err()
+	}
+	if err != nil && stderr.Len() > 0 {
+		err = fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), err
+}
+
+// diagnosticCommand returns the command of the diagnostic, running it in the VM if there is one.
+func (bb *bundleBuilder) diagnosticCommand(diag Diagnostic) (command.Command, error) {
+	if len(diag.Args) == 0 {
+		return nil, fmt.Errorf("diagnostic %q has no command", diag.Name)
+	}
+	hasVM := runtime.GOOS != "linux"
+	switch diag.Kind {
+	case DiagnosticNerdctl:
+		if hasVM {
+			return bb.ncc.CreateWithoutStdio(append([]string{"shell", "finch", "sudo", "nerdctl"}, diag.Args...)...), nil
+		}
+		return bb.ncc.CreateWithoutStdio(diag.Args...), nil
+	case DiagnosticSystem:
+		if hasVM {
+			return bb.ncc.CreateWithoutStdio(append([]string{"shell", "finch"}, diag.Args...)...), nil
+		}
+		return bb.ecc.Create(diag.Args[0], diag.Args[1:]...), nil
+	case DiagnosticLimactl:
+		return bb.ncc.CreateWithoutStdio(diag.Args...), nil
+	case DiagnosticFinch:
+		executable, err := bb.systemDeps.Executable()
+		if err != nil {
+			return nil, err
+		}
+		return bb.ecc.Create(executable, diag.Args...), nil
+	default:
+		return nil, fmt.Errorf("diagnostic %q has an unknown kind %d", diag.Name, diag.Kind)
+	}
+}
@@ -0,0 +1,276 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package support
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/runfinch/finch/pkg/mocks"
+	fpath "github.com/runfinch/finch/pkg/path"
+	"github.com/runfinch/finch/pkg/support/redact"
+)
+
+func TestBundleBuilder_diagnosticItems(t *testing.T) {
+	t.Parallel()
+
+	kernel := Diagnostic{Name: "kernel", Kind: DiagnosticSystem, Args: []string{"uname", "-a"}}
+	expectKernel := func(ecc *mocks.CommandCreator, ncc *mocks.NerdctlCmdCreator, cmd *mocks.Command) {
+		if runtime.GOOS == "linux" {
+			ecc.EXPECT().Create("uname", "-a").Return(cmd)
+		} else {
+			ncc.EXPECT().CreateWithoutStdio("shell", "finch", "uname", "-a").Return(cmd)
+		}
+	}
+	// writeOutput makes cmd write stdout and stderr when it runs.
+	writeOutput := func(cmd *mocks.Command, stdout, stderr string) {
+		cmd.EXPECT().SetStdout(gomock.Any()).Do(func(w io.Writer) {
+			_, _ = w.Write([]byte(stdout))
+		})
+		cmd.EXPECT().SetStderr(gomock.Any()).Do(func(w io.Writer) {
+			_, _ = w.Write([]byte(stderr))
+		})
+		cmd.EXPECT().Start().Return(nil)
+	}
+
+	testCases := []struct {
+		name         string
+		diagnostics  []Diagnostic
+		exclude      []string
+		mockSvc      func(*mocks.Logger, *mocks.CommandCreator, *mocks.NerdctlCmdCreator, *mocks.Command, *mocks.SupportSystemDeps)
+		wantEntries  map[string]string
+		wantManifest []ManifestItem
+	}{
+		{
+			name:        "system diagnostic",
+			diagnostics: []Diagnostic{kernel},
+			mockSvc: func(
+				logger *mocks.Logger,
+				ecc *mocks.CommandCreator,
+				ncc *mocks.NerdctlCmdCreator,
+				cmd *mocks.Command,
+				_ *mocks.SupportSystemDeps,
+			) {
+				logger.EXPECT().Debugln("Collecting diagnostics...")
+				logger.EXPECT().Debugf("Collecting %s...", "diag:kernel")
+				expectKernel(ecc, ncc, cmd)
+				writeOutput(cmd, "Linux lima-finch 6.8.0 x86_64 192.168.5.15\n", "")
+				cmd.EXPECT().Wait().Return(nil)
+			},
+			wantEntries: map[string]string{"diagnostics/kernel.txt": "Linux lima-finch 6.8.0 x86_64 <ip-address-elided>\n"},
+			wantManifest: []ManifestItem{{
+				Path:          "diagnostics/kernel.txt",
+				Source:        "diag:kernel",
+				RedactionHits: map[string]int{redact.NetworkAddresses: 1},
+			}},
+		},
+		{
+			name:        "nerdctl diagnostic",
+			diagnostics: commonDiagnostics()[0:1],
+			mockSvc: func(
+				logger *mocks.Logger,
+				_ *mocks.CommandCreator,
+				ncc *mocks.NerdctlCmdCreator,
+				cmd *mocks.Command,
+				_ *mocks.SupportSystemDeps,
+			) {
+				logger.EXPECT().Debugln("Collecting diagnostics...")
+				logger.EXPECT().Debugf("Collecting %s...", "diag:nerdctl-info")
+				if runtime.GOOS == "linux" {
+					ncc.EXPECT().CreateWithoutStdio("info").Return(cmd)
+				} else {
+					ncc.EXPECT().CreateWithoutStdio("shell", "finch", "sudo", "nerdctl", "info").Return(cmd)
+				}
+				writeOutput(cmd, "Server Version: v1.7.7\n", "")
+				cmd.EXPECT().Wait().Return(nil)
+			},
+			wantEntries: map[string]string{"diagnostics/nerdctl-info.txt": "Server Version: v1.7.7\n"},
+			wantManifest: []ManifestItem{{
+				Path:   "diagnostics/nerdctl-info.txt",
+				Source: "diag:nerdctl-info",
+			}},
+		},
+		{
+			name:        "finch diagnostic",
+			diagnostics: []Diagnostic{{Name: "finch-version", Kind: DiagnosticFinch, Args: []string{"version", "--format", "json"}}},
+			mockSvc: func(
+				logger *mocks.Logger,
+				ecc *mocks.CommandCreator,
+				_ *mocks.NerdctlCmdCreator,
+				cmd *mocks.Command,
+				systemDeps *mocks.SupportSystemDeps,
+			) {
+				logger.EXPECT().Debugln("Collecting diagnostics...")
+				logger.EXPECT().Debugf("Collecting %s...", "diag:finch-version")
+				systemDeps.EXPECT().Executable().Return("/bin/finch", nil)
+				ecc.EXPECT().Create("/bin/finch", "version", "--format", "json").Return(cmd)
+				writeOutput(cmd, `{"version":"v1.0.0"}`+"\n", "")
+				cmd.EXPECT().Wait().Return(nil)
+			},
+			wantEntries: map[string]string{"diagnostics/finch-version.txt": `{"version":"v1.0.0"}` + "\n"},
+			wantManifest: []ManifestItem{{
+				Path:   "diagnostics/finch-version.txt",
+				Source: "diag:finch-version",
+			}},
+		},
+		{
+			name:        "failed diagnostic",
+			diagnostics: []Diagnostic{kernel},
+			mockSvc: func(
+				logger *mocks.Logger,
+				ecc *mocks.CommandCreator,
+				ncc *mocks.NerdctlCmdCreator,
+				cmd *mocks.Command,
+				_ *mocks.SupportSystemDeps,
+			) {
+				logger.EXPECT().Debugln("Collecting diagnostics...")
+				logger.EXPECT().Debugf("Collecting %s...", "diag:kernel")
+				expectKernel(ecc, ncc, cmd)
+				writeOutput(cmd, "", "uname: not found\n")
+				cmd.EXPECT().Wait().Return(errors.New("exit status 127"))
+				logger.EXPECT().Warnf("Could not collect %q. Error: %s", "diag:kernel", gomock.Any())
+			},
+			wantEntries: map[string]string{},
+			wantManifest: []ManifestItem{{
+				Source: "diag:kernel",
+				Error:  "exit status 127: uname: not found",
+			}},
+		},
+		{
+			name:        "timed out diagnostic",
+			diagnostics: []Diagnostic{{Name: "kernel", Kind: DiagnosticSystem, Args: []string{"uname", "-a"}, Timeout: 10 * time.Millisecond}},
+			mockSvc: func(
+				logger *mocks.Logger,
+				ecc *mocks.CommandCreator,
+				ncc *mocks.NerdctlCmdCreator,
+				cmd *mocks.Command,
+				_ *mocks.SupportSystemDeps,
+			) {
+				logger.EXPECT().Debugln("Collecting diagnostics...")
+				logger.EXPECT().Debugf("Collecting %s...", "diag:kernel")
+				expectKernel(ecc, ncc, cmd)
+				writeOutput(cmd, "partial output", "")
+				killed := make(chan struct{})
+				cmd.EXPECT().Wait().DoAndReturn(func() error {
+					<-killed
+					return errors.New("signal: killed")
+				})
+				cmd.EXPECT().Signal(os.Kill).DoAndReturn(func(os.Signal) error {
+					close(killed)
+					return nil
+				})
+				logger.EXPECT().Warnf("Could not collect %q. Error: %s", "diag:kernel", gomock.Any())
+			},
+			wantEntries: map[string]string{},
+			wantManifest: []ManifestItem{{
+				Source: "diag:kernel",
+				Error:  "timed out after 10ms",
+			}},
+		},
+		{
+			name:        "excluded diagnostic",
+			diagnostics: []Diagnostic{kernel},
+			exclude:     []string{"diag:kernel"},
+			mockSvc: func(logger *mocks.Logger, _ *mocks.CommandCreator, _ *mocks.NerdctlCmdCreator, _ *mocks.Command, _ *mocks.SupportSystemDeps) {
+				logger.EXPECT().Debugln("Collecting diagnostics...")
+				logger.EXPECT().Infof("Excluding %s...", "diag:kernel")
+			},
+			wantEntries:  map[string]string{},
+			wantManifest: []ManifestItem{{Source: "diag:kernel", Excluded: true}},
+		},
+		{
+			name:        "all diagnostics excluded",
+			diagnostics: []Diagnostic{kernel},
+			exclude:     []string{"diag:all"},
+			mockSvc: func(logger *mocks.Logger, _ *mocks.CommandCreator, _ *mocks.NerdctlCmdCreator, _ *mocks.Command, _ *mocks.SupportSystemDeps) {
+				logger.EXPECT().Info("Excluding all diagnostics...")
+			},
+			wantEntries:  map[string]string{},
+			wantManifest: []ManifestItem{{Source: "diag:all", Excluded: true}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			logger := mocks.NewLogger(ctrl)
+			ecc := mocks.NewCommandCreator(ctrl)
+			ncc := mocks.NewNerdctlCmdCreator(ctrl)
+			cmd := mocks.NewCommand(ctrl)
+			systemDeps := mocks.NewSupportSystemDeps(ctrl)
+			tc.mockSvc(logger, ecc, ncc, cmd, systemDeps)
+
+			bb := &bundleBuilder{
+				logger:      logger,
+				ecc:         ecc,
+				ncc:         ncc,
+				systemDeps:  systemDeps,
+				diagnostics: tc.diagnostics,
+				workers:     defaultBundleWorkers,
+			}
+			bw := newTestBundleWriter(t, "bundle")
+			manifest := newManifest(&BundleCfg{})
+			redaction := newBundleRedaction(redact.NewEngine(redact.Builtin("/finch", "mockuser")...), nil)
+			items := bb.diagnosticItems(redaction, tc.exclude, "bundle")
+			bb.collectItems(context.Background(), bw, manifest, items, DefaultItemTimeout)
+
+			entries := map[string]string{}
+			for _, e := range bw.entries {
+				entries[e.path] = entryContent(t, e)
+			}
+			assert.Equal(t, tc.wantEntries, entries)
+
+			require.Len(t, manifest.Items, len(tc.wantManifest))
+			for i, want := range tc.wantManifest {
+				got := manifest.Items[i]
+				assert.Equal(t, want.Path, got.Path)
+				assert.Equal(t, want.Source, got.Source)
+				assert.Equal(t, want.Error, got.Error)
+				assert.Equal(t, want.Excluded, got.Excluded)
+				assert.Equal(t, want.RedactionHits, got.RedactionHits)
+			}
+		})
+	}
+}
+
+func TestBundleBuilder_diagnosticCommand(t *testing.T) {
+	t.Parallel()
+
+	bb := &bundleBuilder{}
+	_, err := bb.diagnosticCommand(Diagnostic{Name: "empty"})
+	assert.EqualError(t, err, `diagnostic "empty" has no command`)
+	_, err = bb.diagnosticCommand(Diagnostic{Name: "unknown", Kind: DiagnosticKind(42), Args: []string{"true"}})
+	assert.EqualError(t, err, `diagnostic "unknown" has an unknown kind 42`)
+}
+
+func TestPlatformDiagnostics(t *testing.T) {
+	t.Parallel()
+
+	names := map[string]bool{}
+	for _, diag := range platformDiagnostics(fpath.Finch("/mockfinch")) {
+		assert.False(t, names[diag.Name], "%s is defined more than once", diag.Name)
+		names[diag.Name] = true
+		assert.NotEmpty(t, diag.Args, diag.Name)
+	}
+	assert.Contains(t, names, "nerdctl-info")
+	assert.Contains(t, names, "kernel")
+	if runtime.GOOS == "linux" {
+		assert.Contains(t, names, "cni-conflists")
+		assert.Contains(t, names, "finch-state")
+		assert.Contains(t, names, "finch-daemon-socket")
+	} else {
+		assert.Contains(t, names, "limactl-ls")
+	}
+}
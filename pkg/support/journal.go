@@ -0,0 +1,130 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package support
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// DefaultJournalMaxSize is the size in bytes the journal logs of the services are truncated to, keeping their end.
+const DefaultJournalMaxSize = 10 << 20
+
+// journalTimeLayout is the layout of the timestamps passed to journalctl. They are in UTC,
+// as the time zone of the VM may differ from the one of the host.
+const journalTimeLayout = "2006-01-02 15:04:05 UTC"
+
+// journalOptions select the entries of the journal logs of the services collected in the bundles.
+type journalOptions struct {
+	// lines is the number of the most recent entries collected, unless boot is true or it is 0.
+	lines int
+	// since and until, if not zero, restrict the entries to a time window.
+	since time.Time
+	until time.Time
+	// boot collects all the entries since the current boot, instead of the last lines.
+	boot bool
+	// maxSize is the size in bytes the logs are truncated to, keeping their end.
+	maxSize int64
+}
+
+func newJournalOptions(cfg *BundleCfg) journalOptions {
+	maxSize := cfg.JournalMaxSize
+	if maxSize <= 0 {
+		maxSize = DefaultJournalMaxSize
+	}
+	return journalOptions{
+		lines:   cfg.LogLines,
+		since:   cfg.Since,
+		until:   cfg.Until,
+		boot:    cfg.Boot,
+		maxSize: maxSize,
+	}
+}
+
+// args returns the journalctl command collecting the journal logs of service.
+func (opts journalOptions) args(service string) []string {
+	args := []string{"journalctl"}
+	switch {
+	case opts.boot:
+		args = append(args, "-b")
+	case opts.lines > 0:
+		args = append(args, "-n", strconv.Itoa(opts.lines))
+	}
+	if !opts.since.IsZero() {
+		args = append(args, "--since", opts.since.UTC().Format(journalTimeLayout))
+	}
+	if !opts.until.IsZero() {
+		args = append(args, "--until", opts.until.UTC().Format(journalTimeLayout))
+	}
+	return append(args, "--no-pager", "-xu", service)
+}
+
+// ParseJournalTime parses value as a time in the past relative to now, e.g. "90m" or "2h",
+// or as an absolute time, e.g. "2024-01-02T15:04:05Z", "2024-01-02 15:04:05" or "2024-01-02" in the local time zone.
+func ParseJournalTime(value string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		if d < 0 {
+			return time.Time{}, fmt.Errorf("invalid time %q, durations must not be negative", value)
+		}
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{time.DateTime, "2006-01-02 15:04", time.DateOnly} {
+		if t, err := time.ParseInLocation(layout, value, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf(`invalid time %q, must be a duration, e.g. "2h", or a date, e.g. "2024-01-02 15:04:05"`, value)
+}
+
+// tailWriter keeps the last lines written to it, up to maxSize bytes.
+// At most twice maxSize bytes are held in memory, so that large logs can be streamed through it.
+type tailWriter struct {
+	maxSize int
+	buf     []byte
+	written int64
+}
+
+func newTailWriter(maxSize int64) *tailWriter {
+	return &tailWriter{maxSize: int(maxSize)}
+}
+
+func (w *tailWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	w.written += int64(len(p))
+	if len(w.buf) > 2*w.maxSize {
+		w.buf = tail(w.buf, w.maxSize)
+	}
+	return len(p), nil
+}
+
+// bytes returns the last lines written, up to maxSize bytes.
+func (w *tailWriter) bytes() []byte {
+	return tail(w.buf, w.maxSize)
+}
+
+// truncated returns whether the lines written do not all fit in maxSize bytes.
+func (w *tailWriter) truncated() bool {
+	return w.written > int64(w.maxSize)
+}
+
+// tail returns the end of content, up to size bytes, starting at a line if content is cut in a line,
+// unless it is cut in its last line.
+func tail(content []byte, size int) []byte {
+	if len(content) <= size {
+		return content
+	}
+	cut := len(content) - size
+	end := content[cut:]
+	if content[cut-1] != '\n' {
+		if i := bytes.IndexByte(end, '\n'); i >= 0 && i+1 < len(end) {
+			end = end[i+1:]
+		}
+	}
+	return end
+}
@@ -0,0 +1,223 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package support
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/runfinch/finch/pkg/mocks"
+	"github.com/runfinch/finch/pkg/support/redact"
+)
+
+func TestNewJournalOptions(t *testing.T) {
+	t.Parallel()
+
+	since := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	assert.Equal(t, journalOptions{lines: 100, maxSize: DefaultJournalMaxSize}, newJournalOptions(&BundleCfg{LogLines: 100}))
+	assert.Equal(t,
+		journalOptions{lines: 100, since: since, boot: true, maxSize: 1024},
+		newJournalOptions(&BundleCfg{LogLines: 100, Since: since, Boot: true, JournalMaxSize: 1024}),
+	)
+}
+
+func TestJournalOptions_args(t *testing.T) {
+	t.Parallel()
+
+	since := time.Date(2024, 1, 2, 15, 4, 5, 0, time.FixedZone("PST", -8*60*60))
+	until := time.Date(2024, 1, 3, 8, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name string
+		opts journalOptions
+		want []string
+	}{
+		{
+			name: "last lines",
+			opts: journalOptions{lines: 100},
+			want: []string{"journalctl", "-n", "100", "--no-pager", "-xu", "containerd"},
+		},
+		{
+			name: "time window in UTC",
+			opts: journalOptions{since: since, until: until},
+			want: []string{
+				"journalctl", "--since", "2024-01-02 23:04:05 UTC", "--until", "2024-01-03 08:00:00 UTC",
+				"--no-pager", "-xu", "containerd",
+			},
+		},
+		{
+			name: "time window with last lines",
+			opts: journalOptions{lines: 10000, since: since},
+			want: []string{"journalctl", "-n", "10000", "--since", "2024-01-02 23:04:05 UTC", "--no-pager", "-xu", "containerd"},
+		},
+		{
+			name: "current boot",
+			opts: journalOptions{lines: 100, boot: true},
+			want: []string{"journalctl", "-b", "--no-pager", "-xu", "containerd"},
+		},
+		{
+			name: "current boot since",
+			opts: journalOptions{boot: true, since: until},
+			want: []string{"journalctl", "-b", "--since", "2024-01-03 08:00:00 UTC", "--no-pager", "-xu", "containerd"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.want, tc.opts.args("containerd"))
+		})
+	}
+}
+
+func TestParseJournalTime(t *testing.T) {
+	t.Parallel()
+
+	local := time.FixedZone("CET", 60*60)
+	now := time.Date(2024, 1, 2, 15, 4, 5, 0, local)
+
+	testCases := []struct {
+		value   string
+		want    time.Time
+		wantErr string
+	}{
+		{value: "2h", want: time.Date(2024, 1, 2, 13, 4, 5, 0, local)},
+		{value: "1h30m", want: time.Date(2024, 1, 2, 13, 34, 5, 0, local)},
+		{value: "2024-01-01T10:00:00Z", want: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)},
+		{value: "2024-01-01 10:00:00", want: time.Date(2024, 1, 1, 10, 0, 0, 0, local)},
+		{value: "2024-01-01 10:00", want: time.Date(2024, 1, 1, 10, 0, 0, 0, local)},
+		{value: "2024-01-01", want: time.Date(2024, 1, 1, 0, 0, 0, 0, local)},
+		{value: "-2h", wantErr: `invalid time "-2h", durations must not be negative`},
+		{
+			value:   "yesterday",
+			wantErr: `invalid time "yesterday", must be a duration, e.g. "2h", or a date, e.g. "2024-01-02 15:04:05"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseJournalTime(tc.value, now)
+			if tc.wantErr != "" {
+				assert.EqualError(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, tc.want.Equal(got), "want %s, got %s", tc.want, got)
+		})
+	}
+}
+
+func TestTail(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		content string
+		size    int
+		want    string
+	}{
+		{name: "fits", content: "a\nb\n", size: 4, want: "a\nb\n"},
+		{name: "cut at a line", content: "aa\nbb\ncc\n", size: 6, want: "bb\ncc\n"},
+		{name: "cut in a line", content: "aa\nbb\ncc\n", size: 7, want: "bb\ncc\n"},
+		{name: "cut in the last line", content: "aa\nbbbbbb\n", size: 4, want: "bbb\n"},
+		{name: "cut in the last line without newline", content: "aa\nbbbbbb", size: 3, want: "bbb"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.want, string(tail([]byte(tc.content), tc.size)))
+		})
+	}
+}
+
+func TestTailWriter(t *testing.T) {
+	t.Parallel()
+
+	tw := newTailWriter(10)
+	for i := range 20 {
+		_, err := fmt.Fprintf(tw, "line %d\n", i)
+		require.NoError(t, err)
+		// the lines before the last ones are discarded as they are written
+		assert.LessOrEqual(t, len(tw.buf), 20+len("line 19\n"))
+	}
+	assert.True(t, tw.truncated())
+	assert.Equal(t, int64(150), tw.written)
+	assert.Equal(t, "line 19\n", string(tw.bytes()))
+
+	tw = newTailWriter(10)
+	_, err := io.WriteString(tw, "a\nb\n")
+	require.NoError(t, err)
+	assert.False(t, tw.truncated())
+	assert.Equal(t, "a\nb\n", string(tw.bytes()))
+}
+
+func TestBundleBuilder_copyFileFromVMOrLocal_journal(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	logger := mocks.NewLogger(ctrl)
+	ecc := mocks.NewCommandCreator(ctrl)
+	ncc := mocks.NewNerdctlCmdCreator(ctrl)
+	cmd := mocks.NewCommand(ctrl)
+	bb := &bundleBuilder{logger: logger, ecc: ecc, ncc: ncc}
+
+	var journalLogs strings.Builder
+	for i := range 10 {
+		fmt.Fprintf(&journalLogs, "Jan 02 15:04:0%d lima-finch containerd[42]: entry %d\n", i, i)
+	}
+	journal := journalOptions{boot: true, maxSize: 120}
+	if runtime.GOOS == "linux" {
+		ecc.EXPECT().Create("journalctl", "-b", "--no-pager", "-xu", "containerd").Return(cmd)
+	} else {
+		ncc.EXPECT().CreateWithoutStdio("shell", "finch", "sudo", "journalctl", "-b", "--no-pager", "-xu", "containerd").Return(cmd)
+	}
+	written := make(chan struct{})
+	cmd.EXPECT().SetStdout(gomock.Any()).Do(func(w io.Writer) {
+		// the logs are streamed to the bundle, rather than read in memory
+		assert.IsType(t, &io.PipeWriter{}, w)
+		go func() {
+			_, _ = io.WriteString(w, journalLogs.String())
+			close(written)
+		}()
+	})
+	cmd.EXPECT().SetStderr(gomock.Any())
+	cmd.EXPECT().Start().Return(nil)
+	cmd.EXPECT().Wait().DoAndReturn(func() error {
+		<-written
+		return nil
+	})
+
+	bw := newTestBundleWriter(t, "bundle")
+	manifest := newManifest(&BundleCfg{})
+	redaction := newBundleRedaction(redact.NewEngine(), nil).forItem("service:containerd")
+	require.NoError(t, bw.record(manifest, "service:containerd", redaction, func(w zipCreator) error {
+		return bb.copyFileFromVMOrLocal(context.Background(), w, redaction, "service:containerd", "bundle/logs/journalctl", journal)
+	}))
+
+	require.Len(t, bw.entries, 1)
+	assert.Equal(t, "logs/journalctl/containerd", bw.entries[0].path)
+	content := entryContent(t, bw.entries[0])
+	assert.Equal(t,
+		"Jan 02 15:04:08 lima-finch containerd[42]: entry 8\nJan 02 15:04:09 lima-finch containerd[42]: entry 9\n",
+		content,
+	)
+	require.Len(t, manifest.Items, 1)
+	assert.True(t, manifest.Items[0].Truncated)
+	assert.Equal(t, int64(journalLogs.Len()), manifest.Items[0].OriginalSize)
+	assert.Equal(t, int64(len(content)), manifest.Items[0].Size)
+}
@@ -0,0 +1,420 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package support
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/afero"
+
+	"github.com/runfinch/finch/pkg/support/redact"
+)
+
+const (
+	manifestFileName = "manifest.json"
+	// ManifestSchemaVersion is the version of the schema of manifest.json. It is increased on breaking changes.
+	ManifestSchemaVersion = 1
+)
+
+// Manifest describes the contents of a support bundle, including the items that could not be collected.
+type Manifest struct {
+	SchemaVersion int       `json:"schemaVersion"`
+	FinchVersion  string    `json:"finchVersion"`
+	CreatedAt     time.Time `json:"createdAt"`
+	// Options are the options the bundle was generated with.
+	Options BundleCfg `json:"options"`
+	// Partial is true if the bundle was interrupted or timed out, in which case PartialReason tells which,
+	// and the items which could not be collected have an error.
+	Partial       bool   `json:"partial,omitempty"`
+	PartialReason string `json:"partialReason,omitempty"`
+	// RedactionRules are the user-defined redaction rules, with how many times they matched in the bundle.
+	RedactionRules []ManifestRedactionRule `json:"redactionRules,omitempty"`
+	Items          []ManifestItem          `json:"items"`
+}
+
+// ManifestRedactionRule is a user-defined redaction rule applied to a support bundle.
+// Its pattern is left out, as it may be sensitive itself.
+type ManifestRedactionRule struct {
+	Name  string   `json:"name"`
+	Files []string `json:"files,omitempty"`
+	Hits  int      `json:"hits"`
+}
+
+// ManifestItem is an item of a support bundle.
+type ManifestItem struct {
+	// Path is the path of the item in the bundle, or "" if it could not be collected.
+	Path string `json:"path,omitempty"`
+	// Source is where the item was collected from, e.g. a file path, "vm:<path>" or "service:<name>".
+	Source string `json:"source"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256,omitempty"`
+	// Redactions are the names of the redaction rules applied to the item.
+	Redactions []string `json:"redactions,omitempty"`
+	// RedactionHits counts the matches of the redaction rules in the item.
+	RedactionHits map[string]int `json:"redactionHits,omitempty"`
+	DurationMs    float64        `json:"durationMs"`
+	// Truncated is true if the item was truncated to fit the maximum size of the bundle or of the journal logs,
+	// in which case OriginalSize is its size before.
+	Truncated    bool  `json:"truncated,omitempty"`
+	OriginalSize int64 `json:"originalSize,omitempty"`
+	// Excluded is true if the item was excluded with --exclude.
+	Excluded bool   `json:"excluded,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+func newManifest(cfg *BundleCfg) *Manifest {
+	return &Manifest{
+		SchemaVersion: ManifestSchemaVersion,
+		FinchVersion:  getFinchVersion(),
+		CreatedAt:     time.Now().UTC(),
+		Options:       *cfg,
+		Items:         []ManifestItem{},
+	}
+}
+
+// redact redacts the paths and the errors of the manifest with the built-in rules, like the files of the bundle,
+// as they hold host paths, e.g. the home directory of the user.
+func (manifest *Manifest) redact(engine *redact.Engine) {
+	redactString := func(s string) string {
+		return string(engine.Redact([]byte(s), nil))
+	}
+	redactStrings := func(ss []string) []string {
+		if ss == nil {
+			return nil
+		}
+		redacted := make([]string, len(ss))
+		for i, s := range ss {
+			redacted[i] = redactString(s)
+		}
+		return redacted
+	}
+
+	options := &manifest.Options
+	options.AdditionalFiles = redactStrings(options.AdditionalFiles)
+	options.ExcludeFiles = redactStrings(options.ExcludeFiles)
+	options.Output = redactString(options.Output)
+	options.RedactionRules = redactString(options.RedactionRules)
+	for i := range manifest.Items {
+		item := &manifest.Items[i]
+		item.Source = redactString(item.Source)
+		item.Error = redactString(item.Error)
+	}
+}
+
+// addRedactionRules records the user-defined redaction rules applied to the bundle.
+func (manifest *Manifest) addRedactionRules(rules []*RedactionRule) {
+	for _, rule := range rules {
+		manifest.RedactionRules = append(manifest.RedactionRules, ManifestRedactionRule{Name: rule.Name, Files: rule.Files})
+	}
+}
+
+// zipCreator creates the entries of a zip archive. It is implemented by *zip.Writer and *bundleWriter.
+type zipCreator interface {
+	Create(name string) (io.Writer, error)
+}
+
+// bundleWriter holds the entries of a support bundle in temporary files until they are written to an archive,
+// so that the items can be collected concurrently, the bundle truncated to fit a size, and written in any format.
+type bundleWriter struct {
+	fs     afero.Fs
+	dir    string
+	prefix string
+	// entries are the entries of the items added to the bundle, in the order the items were added.
+	entries []*bundleEntry
+}
+
+var _ zipCreator = (*itemWriter)(nil)
+
+// newBundleWriter returns a bundle writer whose entries are named after prefix, holding them in a temporary
+// directory of afs until it is closed.
+func newBundleWriter(afs afero.Fs, prefix string) (*bundleWriter, error) {
+	dir, err := afero.TempDir(afs, "", bundlePrefix+"-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create a temporary directory for the support bundle: %w", err)
+	}
+	return &bundleWriter{fs: afs, dir: dir, prefix: prefix}, nil
+}
+
+// Close removes the entries of the bundle.
+func (bw *bundleWriter) Close() error {
+	for _, e := range bw.entries {
+		_ = e.file.Close()
+	}
+	return bw.fs.RemoveAll(bw.dir)
+}
+
+// bundleEntry is an entry of a support bundle, held in a temporary file.
+type bundleEntry struct {
+	name string
+	// path is the path of the entry relative to the prefix of the bundle.
+	path string
+	file afero.File
+	// offset is where the entry starts in its file, which is not 0 if the beginning of the entry was truncated.
+	offset int64
+	end    int64
+	// item is the index of the manifest item of the entry.
+	item int
+	// originalSize is the size of the entry before it was truncated, or 0 if it was not.
+	originalSize int64
+}
+
+// markTruncated records that the entry w of a bundle was truncated from originalSize bytes when it was collected.
+func markTruncated(w io.Writer, originalSize int64) {
+	if e, ok := w.(*bundleEntry); ok {
+		e.originalSize = originalSize
+	}
+}
+
+func (e *bundleEntry) Write(p []byte) (int, error) {
+	n, err := e.file.Write(p)
+	e.end += int64(n)
+	return n, err
+}
+
+// size returns the size of the entry.
+func (e *bundleEntry) size() int64 {
+	return e.end - e.offset
+}
+
+func (e *bundleEntry) reader() io.Reader {
+	return io.NewSectionReader(e.file, e.offset, e.size())
+}
+
+// bytes returns the content of the entry.
+func (e *bundleEntry) bytes() ([]byte, error) {
+	return io.ReadAll(e.reader())
+}
+
+// checksum returns the SHA-256 checksum of the entry.
+func (e *bundleEntry) checksum() (string, error) {
+	h := sha256.New()
+	if _, err := io.Copy(h, e.reader()); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// itemWriter creates the entries of an item of the bundle, which may be collected concurrently with other items.
+type itemWriter struct {
+	bw      *bundleWriter
+	entries []*bundleEntry
+}
+
+func (iw *itemWriter) Create(name string) (io.Writer, error) {
+	f, err := afero.TempFile(iw.bw.fs, iw.bw.dir, "entry-")
+	if err != nil {
+		return nil, err
+	}
+	e := &bundleEntry{name: name, path: strings.TrimPrefix(name, iw.bw.prefix+"/"), file: f, item: -1}
+	iw.entries = append(iw.entries, e)
+	return e, nil
+}
+
+// bundleItem is an item of a support bundle, e.g. a log file or the output of a command.
+type bundleItem struct {
+	source string
+	// excluded is true if the item was excluded with --exclude, in which case it is not collected.
+	excluded bool
+	// redaction is the redaction of the item, or nil if it is not redacted.
+	redaction *itemRedaction
+	// collect collects the item with the entries it creates with w.
+	collect func(ctx context.Context, w zipCreator) error
+	// started and warning are the formats of the messages logged with the source of the item
+	// when its collection starts, and with the error collecting it, if any.
+	started string
+	warning string
+
+	entries  []*bundleEntry
+	duration time.Duration
+	err      error
+}
+
+// collect collects the item, holding its entries until it is added to the bundle.
+func (bw *bundleWriter) collect(ctx context.Context, item *bundleItem) {
+	iw := &itemWriter{bw: bw}
+	start := time.Now()
+	item.err = item.collect(ctx, iw)
+	item.duration = time.Since(start)
+	item.entries = iw.entries
+}
+
+// add adds the entries of the collected item to the bundle, and the item to the manifest along with
+// the redaction rules applied to it, if any, how long it took and the error collecting it, if any.
+func (bw *bundleWriter) add(manifest *Manifest, item *bundleItem) {
+	if item.excluded {
+		manifest.exclude(item.source)
+		return
+	}
+
+	manifestItem := ManifestItem{
+		Source:     item.source,
+		DurationMs: float64(item.duration.Microseconds()) / 1000,
+	}
+	if redaction := item.redaction; redaction != nil {
+		manifestItem.Redactions = redaction.names()
+		for name, hits := range redaction.hits {
+			if manifestItem.RedactionHits == nil {
+				manifestItem.RedactionHits = map[string]int{}
+			}
+			manifestItem.RedactionHits[name] = hits
+		}
+		for i, rule := range manifest.RedactionRules {
+			manifest.RedactionRules[i].Hits += redaction.hits[rule.Name]
+		}
+	}
+	err := item.err
+	for _, e := range item.entries {
+		// a single entry is created per item
+		e.item = len(manifest.Items)
+		manifestItem.Path = e.path
+		manifestItem.Size = e.size()
+		sum, sumErr := e.checksum()
+		if sumErr != nil {
+			err = errors.Join(err, sumErr)
+		}
+		manifestItem.SHA256 = sum
+		if e.originalSize > 0 {
+			manifestItem.Truncated = true
+			manifestItem.OriginalSize = e.originalSize
+		}
+		bw.entries = append(bw.entries, e)
+	}
+	if err != nil {
+		manifestItem.Error = err.Error()
+	}
+	manifest.Items = append(manifest.Items, manifestItem)
+}
+
+// record collects the item from source with collect, and adds it to the bundle and the manifest.
+func (bw *bundleWriter) record(
+	manifest *Manifest,
+	source string,
+	redaction *itemRedaction,
+	collect func(w zipCreator) error,
+) error {
+	item := &bundleItem{
+		source:    source,
+		redaction: redaction,
+		collect: func(_ context.Context, w zipCreator) error {
+			return collect(w)
+		},
+	}
+	bw.collect(context.Background(), item)
+	bw.add(manifest, item)
+	return item.err
+}
+
+// writeUnrecorded writes entries in the bundle with write, without recording them in the manifest, e.g. the manifest.
+func (bw *bundleWriter) writeUnrecorded(write func(w zipCreator) error) error {
+	iw := &itemWriter{bw: bw}
+	err := write(iw)
+	bw.entries = append(bw.entries, iw.entries...)
+	return err
+}
+
+// truncate truncates the largest logs first, keeping their end, until the entries of the bundle fit in maxSize bytes.
+// The logs are truncated to the same size, e.g. a log much larger than the others is truncated alone.
+// It returns the number of logs truncated, and whether the entries fit in maxSize.
+func (bw *bundleWriter) truncate(manifest *Manifest, maxSize int64) (int, bool, error) {
+	var total int64
+	var logs []*bundleEntry
+	for _, e := range bw.entries {
+		total += e.size()
+		if strings.HasPrefix(e.path, logPrefix+"/") {
+			logs = append(logs, e)
+		}
+	}
+	excess := total - maxSize
+	if excess <= 0 {
+		return 0, true, nil
+	}
+
+	// find the size the largest logs must be truncated to, so that they shrink by excess
+	slices.SortStableFunc(logs, func(a, b *bundleEntry) int {
+		return cmp.Compare(b.size(), a.size())
+	})
+	var level, largest int64
+	for i, e := range logs {
+		largest += e.size()
+		level = (largest - excess) / int64(i+1)
+		if i+1 == len(logs) || level >= logs[i+1].size() {
+			break
+		}
+	}
+	level = max(level, 0)
+
+	truncated := 0
+	for _, e := range logs {
+		size := e.size()
+		if size <= level {
+			break
+		}
+		// read the end of the log, with the byte before to tell whether it is cut in a line
+		end := make([]byte, level+1)
+		if _, err := e.file.ReadAt(end, e.end-level-1); err != nil {
+			return truncated, false, err
+		}
+		content := tail(end, int(level))
+		e.offset = e.end - int64(len(content))
+		total -= size - e.size()
+		truncated++
+		if e.originalSize == 0 {
+			e.originalSize = size
+		}
+
+		if e.item >= 0 {
+			item := &manifest.Items[e.item]
+			item.Truncated = true
+			item.OriginalSize = e.originalSize
+			item.Size = e.size()
+			item.SHA256 = checksum(content)
+		}
+	}
+	return truncated, total <= maxSize, nil
+}
+
+// write writes the entries of the bundle in the archive, in the order they were added.
+func (bw *bundleWriter) write(archive archiveWriter) error {
+	if err := archive.writeDir(bw.prefix); err != nil {
+		return err
+	}
+	for _, e := range bw.entries {
+		if err := archive.writeFile(e.name, e.size(), e.reader()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// exclude records that the item from source was excluded from the bundle.
+func (manifest *Manifest) exclude(source string) {
+	manifest.Items = append(manifest.Items, ManifestItem{Source: source, Excluded: true})
+}
+
+func writeManifest(writer zipCreator, manifest *Manifest, prefix string) error {
+	manifestFile, err := writer.Create(path.Join(prefix, manifestFileName))
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(manifestFile)
+	enc.SetIndent("", "  ")
+	return enc.Encode(manifest)
+}
@@ -0,0 +1,300 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package support
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"path"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/runfinch/finch/pkg/support/redact"
+)
+
+func TestBundleWriter_record(t *testing.T) {
+	t.Parallel()
+
+	bw := newTestBundleWriter(t, "bundle")
+	manifest := newManifest(&BundleCfg{LogLines: 10})
+	rules := []*RedactionRule{{Name: "account-ids", Files: []string{"log"}}, {Name: "hosts"}}
+	manifest.addRedactionRules(rules)
+
+	br := newBundleRedaction(redact.NewEngine(redact.Builtin("/finch", "mockuser")...), rules)
+	redaction := br.forItem("/path/to/log")
+	redaction.hits["account-ids"] = 2
+	redaction.hits[redact.Username] = 1
+	err := bw.record(manifest, "/path/to/log", redaction, func(writer zipCreator) error {
+		w, err := writer.Create("bundle/logs/log")
+		if err != nil {
+			return err
+		}
+		_, err = w.Write([]byte("file contents\n"))
+		return err
+	})
+	require.NoError(t, err)
+
+	err = bw.record(manifest, "vm:/missing", br.forItem("vm:/missing"), func(zipCreator) error {
+		return errors.New("no such file")
+	})
+	assert.EqualError(t, err, "no such file")
+
+	manifest.exclude("service:all")
+
+	require.Len(t, manifest.Items, 3)
+	assert.Equal(t, "logs/log", manifest.Items[0].Path)
+	assert.Equal(t, "/path/to/log", manifest.Items[0].Source)
+	assert.Equal(t, int64(14), manifest.Items[0].Size)
+	// sha256sum of "file contents\n"
+	assert.Equal(t, "3bf6b30277bde416a4de3058ad97f1d794f00cdc834ad15cb62e8018a45c1f91", manifest.Items[0].SHA256)
+	assert.Equal(t, append(redact.BuiltinNames(), "account-ids", "hosts"), manifest.Items[0].Redactions)
+	assert.Equal(t, map[string]int{"account-ids": 2, redact.Username: 1}, manifest.Items[0].RedactionHits)
+	assert.Empty(t, manifest.Items[0].Error)
+
+	assert.Equal(t, ManifestItem{
+		Source:     "vm:/missing",
+		Redactions: append(redact.BuiltinNames(), "hosts"),
+		DurationMs: manifest.Items[1].DurationMs,
+		Error:      "no such file",
+	}, manifest.Items[1])
+	assert.Equal(t, ManifestItem{Source: "service:all", Excluded: true}, manifest.Items[2])
+	assert.Equal(t, []ManifestRedactionRule{{Name: "account-ids", Files: []string{"log"}, Hits: 2}, {Name: "hosts"}},
+		manifest.RedactionRules)
+}
+
+func TestWriteManifest(t *testing.T) {
+	t.Parallel()
+
+	buf := new(bytes.Buffer)
+	writer := zip.NewWriter(buf)
+	manifest := newManifest(&BundleCfg{AdditionalFiles: []string{"extra"}, ExcludeFiles: []string{}, LogLines: 100})
+	manifest.exclude("extra")
+
+	require.NoError(t, writeManifest(writer, manifest, "bundle"))
+	require.NoError(t, writer.Close())
+
+	reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	require.Len(t, reader.File, 1)
+	assert.Equal(t, "bundle/manifest.json", reader.File[0].Name)
+
+	rc, err := reader.File[0].Open()
+	require.NoError(t, err)
+	defer rc.Close() //nolint:errcheck // closing the file
+	content, err := io.ReadAll(rc)
+	require.NoError(t, err)
+
+	var got Manifest
+	require.NoError(t, json.Unmarshal(content, &got))
+	assert.Equal(t, ManifestSchemaVersion, got.SchemaVersion)
+	assert.Equal(t, BundleCfg{AdditionalFiles: []string{"extra"}, ExcludeFiles: []string{}, LogLines: 100}, got.Options)
+	assert.Equal(t, []ManifestItem{{Source: "extra", Excluded: true}}, got.Items)
+	assert.Contains(t, string(content), `"schemaVersion": 1`)
+}
+
+func TestBundleWriter_truncate(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		maxSize       int64
+		wantTruncated int
+		wantFits      bool
+		wantContents  map[string]string
+	}{
+		{
+			name:     "bundle smaller than the maximum size",
+			maxSize:  1000,
+			wantFits: true,
+			wantContents: map[string]string{
+				"logs/large.log":  "line 1\nline 2\nline 3\nline 4\n",
+				"logs/small.log":  "line a\n",
+				"configs/config":  "key: value\n",
+				"logs/medium.log": "line x\nline y\n",
+			},
+		},
+		{
+			name:          "largest log truncated alone",
+			maxSize:       50,
+			wantTruncated: 1,
+			wantFits:      true,
+			wantContents: map[string]string{
+				"logs/large.log":  "line 3\nline 4\n",
+				"logs/small.log":  "line a\n",
+				"configs/config":  "key: value\n",
+				"logs/medium.log": "line x\nline y\n",
+			},
+		},
+		{
+			name:          "largest logs truncated to the same size",
+			maxSize:       40,
+			wantTruncated: 2,
+			wantFits:      true,
+			wantContents: map[string]string{
+				"logs/large.log":  "line 4\n",
+				"logs/small.log":  "line a\n",
+				"configs/config":  "key: value\n",
+				"logs/medium.log": "line y\n",
+			},
+		},
+		{
+			name:          "configs are not truncated",
+			maxSize:       1,
+			wantTruncated: 3,
+			wantFits:      false,
+			wantContents: map[string]string{
+				"logs/large.log":  "",
+				"logs/small.log":  "",
+				"configs/config":  "key: value\n",
+				"logs/medium.log": "",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			bw := newTestBundleWriter(t, "bundle")
+			manifest := newManifest(&BundleCfg{})
+			for _, file := range []struct{ path, content string }{
+				{"logs/large.log", "line 1\nline 2\nline 3\nline 4\n"},
+				{"logs/small.log", "line a\n"},
+				{"configs/config", "key: value\n"},
+				{"logs/medium.log", "line x\nline y\n"},
+			} {
+				require.NoError(t, bw.record(manifest, file.path, nil, func(writer zipCreator) error {
+					w, err := writer.Create(path.Join("bundle", file.path))
+					require.NoError(t, err)
+					_, err = w.Write([]byte(file.content))
+					return err
+				}))
+			}
+
+			truncated, fits, err := bw.truncate(manifest, tc.maxSize)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantTruncated, truncated)
+			assert.Equal(t, tc.wantFits, fits)
+			for i, e := range bw.entries {
+				assert.Equal(t, tc.wantContents[e.path], entryContent(t, e), e.path)
+				item := manifest.Items[i]
+				assert.Equal(t, int64(len(tc.wantContents[e.path])), item.Size)
+				assert.Equal(t, checksum([]byte(tc.wantContents[e.path])), item.SHA256)
+				assert.Equal(t, item.Truncated, item.OriginalSize > item.Size)
+			}
+		})
+	}
+}
+
+func TestBundleWriter_write(t *testing.T) {
+	t.Parallel()
+
+	for _, format := range []string{FormatZip, FormatTarGz} {
+		t.Run(format, func(t *testing.T) {
+			t.Parallel()
+
+			bw := newTestBundleWriter(t, "bundle")
+			require.NoError(t, bw.writeUnrecorded(func(writer zipCreator) error {
+				w, err := writer.Create("bundle/logs/log")
+				require.NoError(t, err)
+				_, err = w.Write([]byte("file contents\n"))
+				require.NoError(t, err)
+				return writeManifest(writer, newManifest(&BundleCfg{}), "bundle")
+			}))
+
+			buf := new(bytes.Buffer)
+			archive, err := newArchiveWriter(buf, format)
+			require.NoError(t, err)
+			require.NoError(t, bw.write(archive))
+			require.NoError(t, archive.Close())
+
+			entries := readBundle(t, buf.Bytes(), format)
+			assert.Equal(t, "file contents\n", string(entries["bundle/logs/log"]))
+			assert.Contains(t, entries, "bundle/manifest.json")
+			assert.Contains(t, entries, "bundle/")
+		})
+	}
+
+	_, err := newArchiveWriter(new(bytes.Buffer), "rar")
+	assert.EqualError(t, err, `unsupported bundle format "rar", must be "zip" or "tar.gz"`)
+}
+
+// newTestBundleWriter returns a bundle writer holding its entries in memory until the test ends.
+func newTestBundleWriter(t *testing.T, prefix string) *bundleWriter {
+	t.Helper()
+
+	bw, err := newBundleWriter(afero.NewMemMapFs(), prefix)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, bw.Close())
+	})
+	return bw
+}
+
+// entryContent returns the content of the entry of a bundle.
+func entryContent(t *testing.T, e *bundleEntry) string {
+	t.Helper()
+
+	content, err := e.bytes()
+	require.NoError(t, err)
+	return string(content)
+}
+
+// readBundle returns the contents of the entries of the bundle by name.
+func readBundle(t *testing.T, bundle []byte, format string) map[string][]byte {
+	t.Helper()
+
+	entries := map[string][]byte{}
+	switch format {
+	case FormatZip:
+		reader, err := zip.NewReader(bytes.NewReader(bundle), int64(len(bundle)))
+		require.NoError(t, err)
+		for _, f := range reader.File {
+			rc, err := f.Open()
+			require.NoError(t, err)
+			content, err := io.ReadAll(rc)
+			require.NoError(t, err)
+			require.NoError(t, rc.Close())
+			entries[f.Name] = content
+		}
+	case FormatTarGz:
+		gz, err := gzip.NewReader(bytes.NewReader(bundle))
+		require.NoError(t, err)
+		reader := tar.NewReader(gz)
+		for {
+			header, err := reader.Next()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			require.NoError(t, err)
+			content, err := io.ReadAll(reader)
+			require.NoError(t, err)
+			entries[header.Name] = content
+		}
+	}
+	return entries
+}
+
+// readManifest returns the manifest of the bundle.
+func readManifest(t *testing.T, bundle []byte, format string) *Manifest {
+	t.Helper()
+
+	for name, content := range readBundle(t, bundle, format) {
+		if path.Base(name) == manifestFileName {
+			var manifest Manifest
+			require.NoError(t, json.Unmarshal(content, &manifest))
+			return &manifest
+		}
+	}
+	require.Fail(t, "the bundle has no manifest")
+	return nil
+}
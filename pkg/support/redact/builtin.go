@@ -0,0 +1,207 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package redact
+
+import (
+	"bytes"
+	"net/netip"
+	"path/filepath"
+	"regexp"
+)
+
+// The names of the built-in rules.
+const (
+	FinchInstall     = "finch-install"
+	HomeDirectories  = "home-directories"
+	AWSAccessKeys    = "aws-access-keys"
+	AWSSessionTokens = "aws-session-tokens"
+	RegistryAuth     = "registry-auth"
+	Tokens           = "tokens"
+	URLPasswords     = "url-passwords"
+	Emails           = "emails"
+	Username         = "username"
+	NetworkAddresses = "network-addresses"
+	Ports            = "ports"
+	SSHKeys          = "ssh-keys"
+)
+
+// BuiltinNames returns the names of the built-in rules, in the order they are applied.
+func BuiltinNames() []string {
+	return []string{
+		FinchInstall, HomeDirectories, AWSAccessKeys, AWSSessionTokens, RegistryAuth, Tokens,
+		URLPasswords, Emails, Username, NetworkAddresses, Ports, SSHKeys,
+	}
+}
+
+// The built-in rules not depending on the host. Credentials are redacted before the rules
+// replacing smaller parts of them, e.g. the email or the username of a URL.
+var (
+	homeDirectoryRules = []Rule{
+		{
+			Name:        HomeDirectories,
+			Matcher:     regexp.MustCompile(`(?i)(/Users/|/home/|\b[A-Z]:(?:\\{1,2}|/)Users(?:\\{1,2}|/))[^/\\\s"':;,]+`),
+			Replacement: "${1}<username-elided>",
+		},
+	}
+
+	credentialRules = []Rule{
+		{
+			Name:        AWSAccessKeys,
+			Matcher:     regexp.MustCompile(`\b(?:A3T[A-Z0-9]|AKIA|ASIA|ABIA|ACCA|AGPA|AIDA|AIPA|ANPA|ANVA|APKA|AROA)[A-Z0-9]{16}\b`),
+			Replacement: "<aws-access-key-id-elided>",
+		},
+		{
+			Name:        AWSAccessKeys,
+			Matcher:     regexp.MustCompile(`(?i)((?:aws_?)?secret_?access_?key["']?\s*[=:]\s*["']?)[A-Za-z0-9/+=]{40}`),
+			Replacement: "${1}<aws-secret-access-key-elided>",
+		},
+		{
+			Name: AWSSessionTokens,
+			Matcher: regexp.MustCompile(
+				`(?i)((?:aws_?session_?token|aws_?security_?token|x-amz-security-token|session_?token)["']?\s*[=:]\s*["']?)` +
+					`[A-Za-z0-9/+=%]{16,}`),
+			Replacement: "${1}<aws-session-token-elided>",
+		},
+		{
+			// the session tokens of STS are base64-encoded and start with the same bytes
+			Name:        AWSSessionTokens,
+			Matcher:     regexp.MustCompile(`\b(?:IQoJb3JpZ2luX2Vj|FwoGZXIvYXdzE)[A-Za-z0-9/+=]*`),
+			Replacement: "<aws-session-token-elided>",
+		},
+		{
+			// the credentials of the registries in the config.json of nerdctl and Docker
+			Name:        RegistryAuth,
+			Matcher:     regexp.MustCompile(`(?i)("(?:auth|identitytoken|registrytoken|password)"\s*:\s*")[^"\n]+(")`),
+			Replacement: "${1}<credentials-elided>${2}",
+		},
+		{
+			Name:        Tokens,
+			Matcher:     regexp.MustCompile(`(?i)(\bbearer\s+)[A-Za-z0-9._~+/=-]{16,}`),
+			Replacement: "${1}<token-elided>",
+		},
+		{
+			Name:        Tokens,
+			Matcher:     regexp.MustCompile(`(?i)(\bauthorization["']?\s*[:=]\s*["']?basic\s+)[A-Za-z0-9+/=]+`),
+			Replacement: "${1}<token-elided>",
+		},
+		{
+			Name:        Tokens,
+			Matcher:     regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{4,}\.eyJ[A-Za-z0-9_-]{4,}\.[A-Za-z0-9_-]*`),
+			Replacement: "<jwt-elided>",
+		},
+		{
+			// e.g. the passwords of the proxies in HTTPS_PROXY
+			Name:        URLPasswords,
+			Matcher:     regexp.MustCompile(`\b([A-Za-z][A-Za-z0-9+.-]*://[^\s:/@]*:)[^\s/@]+@`),
+			Replacement: "${1}<password-elided>@",
+		},
+		{
+			Name:        Emails,
+			Matcher:     regexp.MustCompile(`\b[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}\b`),
+			Replacement: "<email-elided>",
+		},
+	}
+
+	networkRules = []Rule{
+		{
+			// runs of characters that may be IPv6 addresses, compressed or not, validated by redactIPv6
+			Name:    NetworkAddresses,
+			Matcher: regexp.MustCompile(`[0-9A-Za-z.%]*:[0-9A-Za-z:.%]*:[0-9A-Za-z:.%]*`),
+			Replace: redactIPv6,
+		},
+		{
+			Name:        NetworkAddresses,
+			Matcher:     regexp.MustCompile(`(?:[0-9]{1,3}\.){3}[0-9]{1,3}(:[0-9]{1,5})?`),
+			Replacement: "<ip-address-elided>",
+		},
+		{
+			Name:        NetworkAddresses,
+			Matcher:     regexp.MustCompile(`([0-9A-Fa-f]{2}[:-]){5}([0-9A-Fa-f]{2})`),
+			Replacement: "<mac-address-elided>",
+		},
+		// we can't redact every 1-5 digit number, so we have to redact ports based on specific context.
+		{
+			Name:        Ports,
+			Matcher:     regexp.MustCompile(`('\[' -n )[0-9]{1,5}( ']')`),
+			Replacement: "${1}<port-elided>${2}",
+		},
+		{
+			Name:        Ports,
+			Matcher:     regexp.MustCompile(`('\[' )[0-9]{1,5}( -ne 0 ']')`),
+			Replacement: "${1}<port-elided>${2}",
+		},
+		{
+			Name:        Ports,
+			Matcher:     regexp.MustCompile(`(\[ssh -F .* -p )[0-9]{1,5}(.*])`),
+			Replacement: "${1}<port-elided>${2}",
+		},
+		{
+			Name:        Ports,
+			Matcher:     regexp.MustCompile(`([{,]"sshLocalPort":)[0-9]{1,5}(})`),
+			Replacement: "${1}<port-elided>${2}",
+		},
+		{
+			Name:        Ports,
+			Matcher:     regexp.MustCompile(`(port )[0-9]{1,5}`),
+			Replacement: "${1}<port-elided>",
+		},
+	}
+
+	sshKeyRules = []Rule{
+		{
+			Name: SSHKeys,
+			Matcher: regexp.MustCompile(
+				`(?:ssh-(?:rsa|dss|ed25519)|ecdsa-sha2-nistp(?:256|384|521)|sk-(?:ssh-ed25519|ecdsa-sha2-nistp256)@openssh\.com) ` +
+					`AAAA[A-Za-z0-9+/]+=*(?: \S+)?`),
+			Replacement: "<key-elided>",
+		},
+		{
+			// the host keys of the VM printed by cloud-init
+			Name:        SSHKeys,
+			Matcher:     regexp.MustCompile(`(?:ecdsa-sha2-nistp256|ssh-ed25519|ssh-rsa) .* root@lima-finch`),
+			Replacement: "<key-elided>",
+		},
+	}
+)
+
+// Builtin returns the built-in rules, redacting the install location of finch finchInstall
+// and the username of the user, in the order of BuiltinNames.
+func Builtin(finchInstall, username string) []Rule {
+	var rules []Rule
+	if rule, ok := literal(FinchInstall, filepath.ToSlash(finchInstall), "<finch-install-location-elided>"); ok {
+		rules = append(rules, rule)
+	}
+	if filepath.ToSlash(finchInstall) != finchInstall {
+		if rule, ok := literal(FinchInstall, finchInstall, "<finch-install-location-elided>"); ok {
+			rules = append(rules, rule)
+		}
+	}
+	rules = append(rules, homeDirectoryRules...)
+	rules = append(rules, credentialRules...)
+	if rule, ok := literal(Username, username, "<username-elided>"); ok {
+		rules = append(rules, rule)
+	}
+	rules = append(rules, networkRules...)
+	rules = append(rules, sshKeyRules...)
+	return rules
+}
+
+// redactIPv6 redacts the IPv6 address ending the match, if any, e.g. in "fe80::1" or "addr:fe80::1".
+// A trailing dot or colon is not part of the address, e.g. at the end of a sentence.
+func redactIPv6(match []byte) ([]byte, bool) {
+	for start := 0; start < len(match); {
+		addr := bytes.TrimRight(match[start:], ".:")
+		if ip, err := netip.ParseAddr(string(addr)); err == nil && ip.Is6() {
+			redacted := append([]byte{}, match[:start]...)
+			redacted = append(redacted, "<ip-address-elided>"...)
+			return append(redacted, match[start+len(addr):]...), true
+		}
+		next := bytes.IndexByte(match[start:], ':')
+		if next < 0 {
+			break
+		}
+		start += next + 1
+	}
+	return nil, false
+}
@@ -0,0 +1,40 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package redact
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update the golden files of testdata")
+
+// TestEngine_Redact_golden redacts the log of testdata with the built-in rules, and compares it with its .golden file.
+// Run go test with -update to update the .golden file after changing the rules.
+func TestEngine_Redact_golden(t *testing.T) {
+	t.Parallel()
+
+	input, err := os.ReadFile(filepath.Join("testdata", "bundle.log"))
+	require.NoError(t, err)
+
+	engine := NewEngine(Builtin("/Applications/Finch", "jdoe")...)
+	var got []byte
+	for _, line := range bytes.SplitAfter(input, []byte("\n")) {
+		got = append(got, engine.Redact(line, nil)...)
+	}
+
+	goldenFile := filepath.Join("testdata", "bundle.log.golden")
+	if *update {
+		require.NoError(t, os.WriteFile(goldenFile, got, 0o600))
+	}
+	want, err := os.ReadFile(goldenFile)
+	require.NoError(t, err)
+	assert.Equal(t, string(want), string(got))
+}
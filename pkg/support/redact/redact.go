@@ -0,0 +1,98 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package redact redacts sensitive information, e.g. credentials, addresses and usernames,
+// from the files of the support bundles.
+package redact
+
+import (
+	"regexp"
+	"slices"
+)
+
+// Rule replaces the matches of a regular expression.
+type Rule struct {
+	// Name identifies the rule, e.g. in the manifest of the bundles. Several rules can have the same name.
+	Name    string
+	Matcher *regexp.Regexp
+	// Replacement replaces the matches of Matcher, and can refer to its submatches, e.g. ${1}.
+	Replacement string
+	// Replace, if set, is called instead of using Replacement. It returns the replacement of the match,
+	// or false if the match must be left as is, e.g. when it is not a valid address.
+	Replace func(match []byte) ([]byte, bool)
+}
+
+// Engine redacts text with a sequence of rules, whose regular expressions are compiled only once.
+type Engine struct {
+	rules []Rule
+}
+
+// NewEngine creates an Engine applying the rules in order.
+func NewEngine(rules ...Rule) *Engine {
+	return &Engine{rules: rules}
+}
+
+// With returns an Engine applying the rules of e, then the rules.
+func (e *Engine) With(rules ...Rule) *Engine {
+	return &Engine{rules: slices.Concat(e.rules, rules)}
+}
+
+// Names returns the names of the rules of e, in order and without duplicates.
+func (e *Engine) Names() []string {
+	var names []string
+	for _, rule := range e.rules {
+		if !slices.Contains(names, rule.Name) {
+			names = append(names, rule.Name)
+		}
+	}
+	return names
+}
+
+// Redact returns the line with the matches of the rules replaced.
+// If hits is not nil, the number of replacements of each rule is added to it by name.
+func (e *Engine) Redact(line []byte, hits map[string]int) []byte {
+	for _, rule := range e.rules {
+		matches := rule.Matcher.FindAllSubmatchIndex(line, -1)
+		if len(matches) == 0 {
+			continue
+		}
+
+		redacted := make([]byte, 0, len(line))
+		last, n := 0, 0
+		for _, match := range matches {
+			redacted = append(redacted, line[last:match[0]]...)
+			last = match[1]
+			if rule.Replace == nil {
+				redacted = rule.Matcher.Expand(redacted, []byte(rule.Replacement), line, match)
+				n++
+				continue
+			}
+			replacement, ok := rule.Replace(line[match[0]:match[1]])
+			if !ok {
+				redacted = append(redacted, line[match[0]:match[1]]...)
+				continue
+			}
+			redacted = append(redacted, replacement...)
+			n++
+		}
+		line = append(redacted, line[last:]...)
+
+		if hits != nil && n > 0 {
+			hits[rule.Name] += n
+		}
+	}
+	return line
+}
+
+// literal returns a Rule replacing the literal text with replacement, which must not refer to submatches,
+// or false if text is empty.
+func literal(name, text, replacement string) (Rule, bool) {
+	if text == "" {
+		return Rule{}, false
+	}
+	return Rule{
+		Name:        name,
+		Matcher:     regexp.MustCompile(regexp.QuoteMeta(text)),
+		Replacement: replacement,
+	}, true
+}
@@ -0,0 +1,161 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package support
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v3"
+
+	"github.com/runfinch/finch/pkg/support/redact"
+)
+
+const (
+	redactionRulesFileName = "redaction-rules.yaml"
+	defaultReplacement     = "<redacted>"
+)
+
+// RedactionRule is a user-defined redaction rule, applied to the files of the bundles after the built-in rules.
+type RedactionRule struct {
+	Name string `yaml:"name"`
+	// Pattern is the regular expression matching the text to redact, in the syntax of the regexp package.
+	Pattern string `yaml:"pattern"`
+	// Replacement replaces the matches of Pattern, and can refer to its submatches, e.g. ${1}.
+	// It defaults to "<redacted>".
+	Replacement string `yaml:"replacement"`
+	// Files are globs restricting the rule to the files whose source or base name they match,
+	// e.g. "*.log", "vm:/var/log/*", "service:*" or "diag:*". The rule applies to all the files if empty.
+	Files []string `yaml:"files"`
+
+	matcher *regexp.Regexp
+}
+
+type redactionRulesFile struct {
+	Rules []*RedactionRule `yaml:"rules"`
+}
+
+// LoadRedactionRules reads and validates the redaction rules of the file rulesPath.
+// If required is false, no rules are returned when the file does not exist.
+func LoadRedactionRules(afs afero.Fs, rulesPath string, required bool) ([]*RedactionRule, error) {
+	b, err := afero.ReadFile(afs, rulesPath)
+	if err != nil {
+		if !required && errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read the redaction rules: %w", err)
+	}
+
+	var rulesFile redactionRulesFile
+	if err := yaml.Unmarshal(b, &rulesFile); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the redaction rules of %s: %w", rulesPath, err)
+	}
+	if err := validateRedactionRules(rulesFile.Rules); err != nil {
+		return nil, fmt.Errorf("invalid redaction rules in %s: %w", rulesPath, err)
+	}
+	return rulesFile.Rules, nil
+}
+
+func validateRedactionRules(rules []*RedactionRule) error {
+	names := map[string]bool{}
+	for i, rule := range rules {
+		if rule == nil || rule.Name == "" {
+			return fmt.Errorf("rule %d has no name", i+1)
+		}
+		if names[rule.Name] || slices.Contains(redact.BuiltinNames(), rule.Name) {
+			return fmt.Errorf("rule %q is defined more than once", rule.Name)
+		}
+		names[rule.Name] = true
+
+		if rule.Pattern == "" {
+			return fmt.Errorf("rule %q has no pattern", rule.Name)
+		}
+		matcher, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return fmt.Errorf("rule %q has an invalid pattern: %w", rule.Name, err)
+		}
+		// a pattern matching the empty string would insert the replacement between every character
+		if matcher.MatchString("") {
+			return fmt.Errorf("rule %q has a pattern matching the empty string", rule.Name)
+		}
+		rule.matcher = matcher
+		if rule.Replacement == "" {
+			rule.Replacement = defaultReplacement
+		}
+
+		for _, glob := range rule.Files {
+			if _, err := path.Match(glob, ""); err != nil {
+				return fmt.Errorf("rule %q has an invalid file glob %q: %w", rule.Name, glob, err)
+			}
+		}
+	}
+	return nil
+}
+
+// appliesTo returns whether the rule applies to the file collected from source, e.g. a file path,
+// "vm:<path>", "service:<name>" or "diag:<name>".
+func (rule *RedactionRule) appliesTo(source string) bool {
+	if len(rule.Files) == 0 {
+		return true
+	}
+	source = filepath.ToSlash(source)
+	_, filePath, _ := strings.Cut(source, ":")
+	if !isFileFromVM(source) && !isService(source) && !isDiagnostic(source) {
+		filePath = source
+	}
+	for _, glob := range rule.Files {
+		if ok, _ := path.Match(glob, source); ok {
+			return true
+		}
+		if ok, _ := path.Match(glob, path.Base(filePath)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// bundleRedaction redacts the items of a bundle with the built-in rules, then the user-defined ones.
+type bundleRedaction struct {
+	builtin *redact.Engine
+	rules   []*RedactionRule
+}
+
+func newBundleRedaction(builtin *redact.Engine, rules []*RedactionRule) *bundleRedaction {
+	return &bundleRedaction{builtin: builtin, rules: rules}
+}
+
+// forItem returns the redaction of the item collected from source.
+func (br *bundleRedaction) forItem(source string) *itemRedaction {
+	var rules []redact.Rule
+	for _, rule := range br.rules {
+		if rule.appliesTo(source) {
+			rules = append(rules, redact.Rule{Name: rule.Name, Matcher: rule.matcher, Replacement: rule.Replacement})
+		}
+	}
+	return &itemRedaction{engine: br.builtin.With(rules...), hits: map[string]int{}}
+}
+
+// itemRedaction is the redaction of an item of a bundle.
+type itemRedaction struct {
+	engine *redact.Engine
+	// hits counts the matches of each rule in the item.
+	hits map[string]int
+}
+
+// names returns the names of all the redaction rules applied to the item, in order.
+func (r *itemRedaction) names() []string {
+	return r.engine.Names()
+}
+
+// apply redacts the line.
+func (r *itemRedaction) apply(line []byte) []byte {
+	return r.engine.Redact(line, r.hits)
+}
@@ -0,0 +1,227 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package support
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/runfinch/finch/pkg/mocks"
+	fpath "github.com/runfinch/finch/pkg/path"
+	"github.com/runfinch/finch/pkg/support/redact"
+)
+
+func TestLoadRedactionRules(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name      string
+		content   string
+		required  bool
+		wantNames []string
+		wantErr   string
+	}{
+		{
+			name: "valid rules",
+			content: `rules:
+  - name: account-ids
+    pattern: '\b[0-9]{12}\b'
+    replacement: <account-id-elided>
+  - name: internal-hosts
+    pattern: '[a-z0-9-]+\.corp\.example\.com'
+    files: ["*.log", "service:*"]
+`,
+			wantNames: []string{"account-ids", "internal-hosts"},
+		},
+		{
+			name: "missing optional file",
+		},
+		{
+			name:     "missing required file",
+			required: true,
+			wantErr:  "failed to read the redaction rules: open /rules.yaml: file does not exist",
+		},
+		{
+			name:    "invalid YAML",
+			content: "rules: [",
+			wantErr: "failed to unmarshal the redaction rules of /rules.yaml: yaml: line 1: did not find expected node content",
+		},
+		{
+			name:    "rule without a name",
+			content: "rules:\n  - pattern: secret\n",
+			wantErr: "invalid redaction rules in /rules.yaml: rule 1 has no name",
+		},
+		{
+			name:    "duplicate rule",
+			content: "rules:\n  - name: a\n    pattern: x\n  - name: a\n    pattern: y\n",
+			wantErr: `invalid redaction rules in /rules.yaml: rule "a" is defined more than once`,
+		},
+		{
+			name:    "rule named like a built-in rule",
+			content: "rules:\n  - name: username\n    pattern: x\n",
+			wantErr: `invalid redaction rules in /rules.yaml: rule "username" is defined more than once`,
+		},
+		{
+			name:    "rule without a pattern",
+			content: "rules:\n  - name: a\n",
+			wantErr: `invalid redaction rules in /rules.yaml: rule "a" has no pattern`,
+		},
+		{
+			name:    "invalid pattern",
+			content: "rules:\n  - name: a\n    pattern: '(x'\n",
+			wantErr: "invalid redaction rules in /rules.yaml: rule \"a\" has an invalid pattern: " +
+				"error parsing regexp: missing closing ): `(x`",
+		},
+		{
+			name:    "pattern matching the empty string",
+			content: "rules:\n  - name: a\n    pattern: 'x*'\n",
+			wantErr: `invalid redaction rules in /rules.yaml: rule "a" has a pattern matching the empty string`,
+		},
+		{
+			name:    "invalid file glob",
+			content: "rules:\n  - name: a\n    pattern: x\n    files: ['[']\n",
+			wantErr: `invalid redaction rules in /rules.yaml: rule "a" has an invalid file glob "[": syntax error in pattern`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			fs := afero.NewMemMapFs()
+			if tc.content != "" {
+				require.NoError(t, afero.WriteFile(fs, "/rules.yaml", []byte(tc.content), 0o644))
+			}
+
+			rules, err := LoadRedactionRules(fs, "/rules.yaml", tc.required)
+			if tc.wantErr != "" {
+				assert.EqualError(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			var names []string
+			for _, rule := range rules {
+				names = append(names, rule.Name)
+				assert.NotNil(t, rule.matcher)
+				assert.NotEmpty(t, rule.Replacement)
+			}
+			assert.Equal(t, tc.wantNames, names)
+		})
+	}
+}
+
+func TestRedactionRule_appliesTo(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		files  []string
+		source string
+		want   bool
+	}{
+		{name: "no globs", source: "/path/to/ha.stderr.log", want: true},
+		{name: "base name glob", files: []string{"*.log"}, source: "/path/to/ha.stderr.log", want: true},
+		{name: "path glob", files: []string{"/path/*/ha.*"}, source: "/path/to/ha.stderr.log", want: true},
+		{name: "VM file", files: []string{"vm:/var/log/*"}, source: "vm:/var/log/cloud-init.log", want: true},
+		{name: "base name of a VM file", files: []string{"cloud-init.log"}, source: "vm:/var/log/cloud-init.log", want: true},
+		{name: "service", files: []string{"service:*"}, source: "service:containerd", want: true},
+		{name: "service name", files: []string{"containerd"}, source: "service:containerd", want: true},
+		{name: "diagnostic", files: []string{"diag:*"}, source: "diag:nerdctl-info", want: true},
+		{name: "no match", files: []string{"*.yaml", "service:*"}, source: "/path/to/ha.stderr.log", want: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			rule := &RedactionRule{Name: "rule", Files: tc.files}
+			assert.Equal(t, tc.want, rule.appliesTo(tc.source))
+		})
+	}
+}
+
+func TestBundleRedaction_forItem(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/rules.yaml", []byte(`rules:
+  - name: account-ids
+    pattern: 'arn:aws:iam::([0-9]{12}):'
+    replacement: 'arn:aws:iam::<account-id-elided>:'
+  - name: codenames
+    pattern: '(?i)project-(falcon|otter)'
+  - name: yaml-only
+    pattern: secret
+    files: ["*.yaml"]
+`), 0o644))
+	rules, err := LoadRedactionRules(fs, "/rules.yaml", true)
+	require.NoError(t, err)
+
+	br := newBundleRedaction(redact.NewEngine(redact.Builtin("/finch", "jdoe")...), rules)
+	redaction := br.forItem("/logs/ha.stderr.log")
+	assert.Equal(t, append(redact.BuiltinNames(), "account-ids", "codenames"), redaction.names())
+
+	got := redaction.apply([]byte("jdoe assumed arn:aws:iam::123456789012:role/x of Project-Falcon and project-otter, secret\n"))
+	assert.Equal(t, "<username-elided> assumed arn:aws:iam::<account-id-elided>:role/x of <redacted> and <redacted>, secret\n",
+		string(got))
+	got = redaction.apply([]byte("nothing to redact\n"))
+	assert.Equal(t, "nothing to redact\n", string(got))
+	assert.Equal(t, map[string]int{redact.Username: 1, "account-ids": 1, "codenames": 2}, redaction.hits)
+}
+
+func TestBundleBuilder_userRedactionRules(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	fs := afero.NewMemMapFs()
+	logger := mocks.NewLogger(ctrl)
+	config := mocks.NewBundleConfig(ctrl)
+	bb := &bundleBuilder{logger: logger, fs: fs, config: config, finch: fpath.Finch("mockfinch")}
+
+	require.NoError(t, afero.WriteFile(fs, "/finch/redaction-rules.yaml",
+		[]byte("rules:\n  - name: hosts\n    pattern: '[a-z]+\\.corp\\.example\\.com'\n"), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/logs/app.log", []byte("connected to db.corp.example.com\n"), 0o644))
+	config.EXPECT().RedactionRulesFile().Return("/finch/redaction-rules.yaml")
+	logger.EXPECT().Debugf("Loaded %d redaction rules from %s", 1, "/finch/redaction-rules.yaml")
+
+	rules, err := bb.loadRedactionRules("")
+	require.NoError(t, err)
+	br := newBundleRedaction(redact.NewEngine(redact.Builtin(string(bb.finch), "mockuser")...), rules)
+
+	bw := newTestBundleWriter(t, "bundle")
+	manifest := newManifest(&BundleCfg{})
+	manifest.addRedactionRules(rules)
+	redaction := br.forItem("/logs/app.log")
+	require.NoError(t, bw.record(manifest, "/logs/app.log", redaction, func(w zipCreator) error {
+		return bb.copyFileFromVMOrLocal(context.Background(), w, redaction, "/logs/app.log", "bundle/logs", journalOptions{lines: 100})
+	}))
+	require.Len(t, bw.entries, 1)
+	assert.Equal(t, "connected to <redacted>\n", entryContent(t, bw.entries[0]))
+
+	assert.Equal(t, []ManifestRedactionRule{{Name: "hosts", Hits: 1}}, manifest.RedactionRules)
+	require.Len(t, manifest.Items, 1)
+	assert.Equal(t, map[string]int{"hosts": 1}, manifest.Items[0].RedactionHits)
+}
+
+func TestBundleBuilder_loadRedactionRules_missingFile(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	config := mocks.NewBundleConfig(ctrl)
+	bb := &bundleBuilder{fs: afero.NewMemMapFs(), config: config}
+
+	config.EXPECT().RedactionRulesFile().Return("/finch/redaction-rules.yaml")
+	rules, err := bb.loadRedactionRules("")
+	assert.NoError(t, err)
+	assert.Empty(t, rules)
+
+	_, err = bb.loadRedactionRules("/rules.yaml")
+	assert.True(t, errors.Is(err, afero.ErrFileNotFound))
+}
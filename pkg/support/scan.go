@@ -0,0 +1,223 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package support
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+// The names of the detectors of the scanner.
+const (
+	DetectorAWSAccessKeyID     = "aws-access-key-id"
+	DetectorAWSSecretAccessKey = "aws-secret-access-key"
+	DetectorAWSSessionToken    = "aws-session-token"
+	DetectorToken              = "token"
+	DetectorPrivateKey         = "private-key"
+	DetectorURLPassword        = "url-password"
+	DetectorHighEntropy        = "high-entropy-string"
+	DetectorUsername           = "username"
+	DetectorHostname           = "hostname"
+)
+
+const (
+	// highEntropyMinLength is the length from which strings are checked for high entropy.
+	highEntropyMinLength = 32
+	// highEntropyThreshold is the Shannon entropy in bits per character from which strings are considered random,
+	// e.g. about 4.4 for 32 random base64 characters, and less than 4 for most identifiers.
+	highEntropyThreshold = 4.2
+)
+
+// Finding is a potential secret found in a support bundle.
+type Finding struct {
+	// Path is the path of the file of the bundle the secret was found in.
+	Path string
+	// Line is the line of the file the secret was found in, starting at 1.
+	Line     int
+	Detector string
+	// Match is the text matched by the detector, to be masked before it is printed.
+	Match string
+}
+
+// Masked returns the match of the finding with all but its first characters masked.
+func (f Finding) Masked() string {
+	keep := min(4, len(f.Match)/4)
+	return f.Match[:keep] + strings.Repeat("*", min(len(f.Match)-keep, 8))
+}
+
+type detector struct {
+	name    string
+	matcher *regexp.Regexp
+	// check, if not nil, filters the matches.
+	check func(match []byte) bool
+}
+
+// The detectors not depending on the host. They match secrets which may be left in the bundles,
+// e.g. in files added with --include, or in formats the redaction rules miss.
+var builtinDetectors = []detector{
+	{
+		name:    DetectorAWSAccessKeyID,
+		matcher: regexp.MustCompile(`\b(?:A3T[A-Z0-9]|AKIA|ASIA|ABIA|ACCA|AGPA|AIDA|AIPA|ANPA|ANVA|APKA|AROA)[A-Z0-9]{16}\b`),
+	},
+	{
+		name:    DetectorAWSSecretAccessKey,
+		matcher: regexp.MustCompile(`(?i)(?:aws_?)?secret_?access_?key["']?\s*[=:]\s*["']?[A-Za-z0-9/+=]{40}`),
+	},
+	{
+		name:    DetectorAWSSessionToken,
+		matcher: regexp.MustCompile(`\b(?:IQoJb3JpZ2luX2Vj|FwoGZXIvYXdzE)[A-Za-z0-9/+=]+`),
+	},
+	{
+		name:    DetectorToken,
+		matcher: regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9._~+/=-]{16,}`),
+	},
+	{
+		name:    DetectorToken,
+		matcher: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{4,}\.eyJ[A-Za-z0-9_-]{4,}\.[A-Za-z0-9_-]*`),
+	},
+	{
+		// GitHub tokens
+		name:    DetectorToken,
+		matcher: regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`),
+	},
+	{
+		// the credentials of the registries in the config.json of nerdctl and Docker
+		name:    DetectorToken,
+		matcher: regexp.MustCompile(`(?i)"(?:auth|identitytoken|registrytoken|password)"\s*:\s*"[^"<\n]+"`),
+	},
+	{
+		name:    DetectorPrivateKey,
+		matcher: regexp.MustCompile(`-----BEGIN (?:[A-Z0-9]+ )*PRIVATE KEY(?: BLOCK)?-----`),
+	},
+	{
+		name:    DetectorURLPassword,
+		matcher: regexp.MustCompile(`\b[A-Za-z][A-Za-z0-9+.-]*://[^\s:/@]*:[^\s/@<]+@`),
+	},
+	{
+		name:    DetectorHighEntropy,
+		matcher: regexp.MustCompile(`[A-Za-z0-9+/=_-]{32,}`),
+		check:   isHighEntropy,
+	},
+}
+
+// Scanner detects potential secrets in support bundles.
+type Scanner struct {
+	detectors []detector
+}
+
+// NewScanner returns a scanner with the built-in detectors, and detecting the username and the hostname, if not empty.
+func NewScanner(username, hostname string) *Scanner {
+	detectors := append([]detector{}, builtinDetectors...)
+	if username != "" {
+		detectors = append(detectors, detector{name: DetectorUsername, matcher: literalWord(username)})
+	}
+	if hostname != "" && hostname != "localhost" {
+		detectors = append(detectors, detector{name: DetectorHostname, matcher: literalWord(hostname)})
+	}
+	return &Scanner{detectors: detectors}
+}
+
+// literalWord returns a matcher of the text not being part of a larger word, e.g. "alice" in "/Users/alice/".
+func literalWord(text string) *regexp.Regexp {
+	return regexp.MustCompile(`(?:^|[^A-Za-z0-9_.-])(` + regexp.QuoteMeta(text) + `)(?:$|[^A-Za-z0-9_-])`)
+}
+
+// Scan returns the potential secrets of the files of the bundle, in the order of the files and of their lines.
+func (s *Scanner) Scan(bundle *Bundle) []Finding {
+	var findings []Finding
+	for _, f := range bundle.Files {
+		for i, line := range bytes.Split(f.content, []byte("\n")) {
+			findings = append(findings, s.scanLine(f.Path, i+1, line)...)
+		}
+	}
+	return findings
+}
+
+// ScanFile returns the potential secrets of the files of the support bundle of the archive bundlePath, like Scan,
+// streaming the files line by line instead of reading the bundle in memory.
+func (s *Scanner) ScanFile(afs afero.Fs, bundlePath string) ([]Finding, error) {
+	var findings []Finding
+	_, err := walkBundle(afs, bundlePath, func(name string, r io.Reader) error {
+		filePath := bundleFilePath(name)
+		br := bufio.NewReader(r)
+		for lineNumber := 1; ; lineNumber++ {
+			line, err := br.ReadBytes('\n')
+			findings = append(findings, s.scanLine(filePath, lineNumber, bytes.TrimSuffix(line, []byte("\n")))...)
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return findings, nil
+}
+
+// scanLine returns the potential secrets of the line. A match overlapping the match of a previous detector
+// is skipped, e.g. an AWS session token is not reported as a high entropy string as well.
+func (s *Scanner) scanLine(filePath string, lineNumber int, line []byte) []Finding {
+	var findings []Finding
+	var matched [][2]int
+	for _, d := range s.detectors {
+		for _, loc := range d.matcher.FindAllSubmatchIndex(line, -1) {
+			// the matchers of literal words have a group excluding their boundaries
+			start, end := loc[0], loc[1]
+			if len(loc) > 2 {
+				start, end = loc[2], loc[3]
+			}
+			match := line[start:end]
+			if d.check != nil && !d.check(match) {
+				continue
+			}
+			if slices.ContainsFunc(matched, func(m [2]int) bool { return start < m[1] && m[0] < end }) {
+				continue
+			}
+			matched = append(matched, [2]int{start, end})
+			findings = append(findings, Finding{Path: filePath, Line: lineNumber, Detector: d.name, Match: string(match)})
+		}
+	}
+	return findings
+}
+
+// isHighEntropy returns whether s looks like a random string, e.g. a key or a token.
+// Strings without upper case letters, lower case letters and digits are skipped, e.g. hexadecimal digests.
+func isHighEntropy(s []byte) bool {
+	if len(s) < highEntropyMinLength || bytes.HasPrefix(s, []byte("/")) || bytes.Contains(s, []byte("//")) {
+		return false
+	}
+	var upper, lower, digit bool
+	counts := map[byte]int{}
+	for _, c := range s {
+		switch {
+		case c >= 'A' && c <= 'Z':
+			upper = true
+		case c >= 'a' && c <= 'z':
+			lower = true
+		case c >= '0' && c <= '9':
+			digit = true
+		}
+		counts[c]++
+	}
+	if !upper || !lower || !digit {
+		return false
+	}
+	var entropy float64
+	for _, n := range counts {
+		p := float64(n) / float64(len(s))
+		entropy -= p * math.Log2(p)
+	}
+	return entropy >= highEntropyThreshold
+}
@@ -5,17 +5,18 @@
 package support
 
 import (
-	"archive/zip"
 	"bufio"
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
+	"os"
 	"path"
 	"path/filepath"
 	"runtime"
 	"slices"
-	"strconv"
 	"strings"
 	"time"
 
@@ -26,6 +27,7 @@ import (
 	"github.com/runfinch/finch/pkg/flog"
 	"github.com/runfinch/finch/pkg/lima/wrapper"
 	fpath "github.com/runfinch/finch/pkg/path"
+	"github.com/runfinch/finch/pkg/support/redact"
 	"github.com/runfinch/finch/pkg/system"
 	"github.com/runfinch/finch/pkg/version"
 )
@@ -39,6 +41,8 @@ const (
 	configPrefix     = "configs"
 	additionalPrefix = "misc"
 	allServices      = "service:all"
+	// Stdout is the output of the bundles written to the standard output.
+	Stdout = "-"
 )
 
 // PlatformData defines the YAML structure for the platform data included in a support bundle.
@@ -46,18 +50,48 @@ type PlatformData struct {
 	Os    string `yaml:"os"`
 	Arch  string `yaml:"arch"`
 	Finch string `yaml:"finch"`
+	// Package is the name and the version of the deb or rpm package finch was installed with, on Linux.
+	Package string `yaml:"package,omitempty"`
 }
 
 // BundleCfg has all the required args for generating support bundles.
 type BundleCfg struct {
-	AdditionalFiles []string
-	ExcludeFiles    []string
-	LogLines        int
+	AdditionalFiles []string `json:"additionalFiles"`
+	ExcludeFiles    []string `json:"excludeFiles"`
+	// LogLines is the number of the most recent lines of the journal logs of the services collected.
+	// If it is 0, all their lines are collected, up to JournalMaxSize.
+	LogLines int `json:"logLines"`
+	// Since and Until, if not zero, restrict the journal logs of the services to a time window.
+	Since time.Time `json:"since,omitzero"`
+	Until time.Time `json:"until,omitzero"`
+	// Boot collects the journal logs of the services since the current boot, instead of their last LogLines lines.
+	Boot bool `json:"boot,omitempty"`
+	// JournalMaxSize is the size in bytes the journal logs of each service are truncated to, keeping their end.
+	// It defaults to DefaultJournalMaxSize.
+	JournalMaxSize int64 `json:"journalMaxSize,omitempty"`
+	// Output is the path of the bundle, or of its directory, or "-" to write it to the standard output.
+	// The bundle is written to the current directory if it is empty.
+	Output string `json:"output,omitempty"`
+	// Format is the format of the bundle, FormatZip or FormatTarGz. It is inferred from Output if empty.
+	Format string `json:"format,omitempty"`
+	// MaxSize, if not 0, is the size in bytes the log files are truncated to fit in, the largest ones first.
+	MaxSize int64 `json:"maxSize,omitempty"`
+	// Timeout, if not 0, is how long the bundle can take to be collected, after which a partial bundle is written.
+	Timeout time.Duration `json:"timeout,omitempty"`
+	// ItemTimeout is how long each item of the bundle can take to be collected. It defaults to DefaultItemTimeout.
+	ItemTimeout time.Duration `json:"itemTimeout,omitempty"`
+	// RedactionRules is the path of the file of user-defined redaction rules.
+	// The rules of the finch directory are used, if any, when it is empty.
+	RedactionRules string `json:"redactionRules,omitempty"`
 }
 
+// ErrPartialBundle is wrapped by the error of GenerateSupportBundle when it writes a partial bundle,
+// as it was interrupted or timed out.
+var ErrPartialBundle = errors.New("the support bundle is partial")
+
 // BundleBuilder provides methods to generate support bundles.
 type BundleBuilder interface {
-	GenerateSupportBundle(*BundleCfg) (string, error)
+	GenerateSupportBundle(context.Context, *BundleCfg) (string, error)
 }
 
 // SystemDeps provides methods to get system dependencies.
@@ -76,6 +110,11 @@ type bundleBuilder struct {
 	ncc        command.NerdctlCmdCreator
 	lima       wrapper.LimaWrapper
 	systemDeps SystemDeps
+	stdout     io.Writer
+	// diagnostics are the commands whose outputs are included in the bundles.
+	diagnostics []Diagnostic
+	// workers is how many items of the bundles are collected concurrently.
+	workers int
 }
 
 // NewBundleBuilder produces a new BundleBuilder.
@@ -88,40 +127,83 @@ func NewBundleBuilder(
 	ncc command.NerdctlCmdCreator,
 	lima wrapper.LimaWrapper,
 	systemDeps SystemDeps,
+	stdout io.Writer,
 ) BundleBuilder {
 	return &bundleBuilder{
-		logger:     logger,
-		fs:         fs,
-		config:     config,
-		finch:      finch,
-		ecc:        ecc,
-		ncc:        ncc,
-		lima:       lima,
-		systemDeps: systemDeps,
+		logger:      logger,
+		fs:          fs,
+		config:      config,
+		finch:       finch,
+		ecc:         ecc,
+		ncc:         ncc,
+		lima:        lima,
+		systemDeps:  systemDeps,
+		stdout:      stdout,
+		diagnostics: platformDiagnostics(finch),
+		workers:     defaultBundleWorkers,
 	}
 }
 
 // GenerateSupportBundle generates a new support bundle.
-func (bb *bundleBuilder) GenerateSupportBundle(cfg *BundleCfg) (string, error) {
+// If ctx is done or the bundle times out, a partial bundle is written along with an error wrapping ErrPartialBundle.
+func (bb *bundleBuilder) GenerateSupportBundle(ctx context.Context, cfg *BundleCfg) (string, error) {
 	additionalFiles := cfg.AdditionalFiles
 	excludeFiles := cfg.ExcludeFiles
-	logLines := cfg.LogLines
+	journal := newJournalOptions(cfg)
+
+	// the rules are validated before collecting anything
+	rules, err := bb.loadRedactionRules(cfg.RedactionRules)
+	if err != nil {
+		return "", err
+	}
 
-	zipFileName := bundleFileName()
-	bb.logger.Debugf("Creating %s...", zipFileName)
-	zipFile, err := bb.fs.Create(zipFileName)
+	format := cfg.Format
+	if format == "" {
+		format = formatFromFileName(cfg.Output)
+	}
+	if format == "" {
+		format = FormatZip
+	}
+	bundleName, bundleDir, err := bb.bundleName(cfg.Output, format)
 	if err != nil {
 		return "", err
 	}
 
-	zipPrefix := strings.TrimSuffix(zipFileName, filepath.Ext(zipFileName))
+	var output io.Writer = bb.stdout
+	var bundleFile afero.File
+	if bundleName != Stdout {
+		bb.logger.Debugf("Creating %s...", bundleName)
+		bundleFile, err = bb.fs.Create(bundleName)
+		if err != nil {
+			return "", err
+		}
+		defer bundleFile.Close() //nolint:errcheck // the file is closed below when the bundle is complete
+		output = bundleFile
+	}
+	archive, err := newArchiveWriter(output, format)
+	if err != nil {
+		return "", err
+	}
 
-	writer := zip.NewWriter(zipFile)
+	if cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.Timeout)
+		defer cancel()
+	}
+	itemTimeout := cfg.ItemTimeout
+	if itemTimeout <= 0 {
+		itemTimeout = DefaultItemTimeout
+	}
 
-	_, err = writer.Create(fmt.Sprintf("%s/", zipPrefix))
+	bw, err := newBundleWriter(bb.fs, bundleDir)
 	if err != nil {
 		return "", err
 	}
+	defer bw.Close() //nolint:errcheck // the entries are temporary files
+	manifest := newManifest(cfg)
+	manifest.addRedactionRules(rules)
+	// the built-in rules are compiled once for all the files of the bundle
+	redaction := newBundleRedaction(redact.NewEngine(redact.Builtin(string(bb.finch), bb.lima.LimaUser(false).Username)...), rules)
 
 	platform, err := bb.getPlatformData()
 	if err != nil {
@@ -129,119 +211,187 @@ func (bb *bundleBuilder) GenerateSupportBundle(cfg *BundleCfg) (string, error) {
 	}
 
 	bb.logger.Debugln("Gathering platform data...")
-	err = writePlatformData(writer, platform, zipPrefix)
+	err = bw.record(manifest, "platform", nil, func(w zipCreator) error {
+		return writePlatformData(w, platform, bundleDir)
+	})
 	if err != nil {
 		return "", err
 	}
 
 	bb.logger.Debugln("Collecting finch version output...")
 	version := bb.getFinchVersion()
-	err = writeVersionOutput(writer, version, zipPrefix)
+	err = bw.record(manifest, "finch version", nil, func(w zipCreator) error {
+		return writeVersionOutput(w, version, bundleDir)
+	})
 	if err != nil {
 		return "", err
 	}
 
+	// the items are listed in the order they are written in the bundle, then collected concurrently
+	var items []*bundleItem
 	bb.logger.Debugln("Copying in log files...")
 	for _, file := range bb.config.LogFiles() {
-		if fileShouldBeExcluded(file, excludeFiles) {
-			bb.logger.Infof("Excluding %s...", file)
-			continue
-		}
-		bb.logger.Debugf("Copying %s...", file)
-		err = bb.copyFileFromVMOrLocal(writer, file, path.Join(zipPrefix, logPrefix), logLines)
-		if err != nil {
-			bb.logger.Warnf("Could not copy in %q. Error: %s", file, err)
-		}
+		items = append(items, bb.fileItem(file, excludeFiles, redaction, path.Join(bundleDir, logPrefix), journal))
 	}
 
 	if slices.Contains(excludeFiles, allServices) {
 		bb.logger.Info("Excluding all service logs...")
+		items = append(items, &bundleItem{source: allServices, excluded: true})
 	} else {
 		bb.logger.Debugln("Copying in journal logs...")
 		for _, file := range bb.config.JournalServices() {
-			if fileShouldBeExcluded(file, excludeFiles) {
-				bb.logger.Infof("Excluding %s...", file)
-				continue
-			}
-			bb.logger.Debugf("Copying %s...", file)
-			err = bb.copyFileFromVMOrLocal(writer, file, path.Join(zipPrefix, journalPrefix), logLines)
-			if err != nil {
-				bb.logger.Warnf("Could not copy in %q. Error: %s", file, err)
-			}
+			items = append(items, bb.fileItem(file, excludeFiles, redaction, path.Join(bundleDir, journalPrefix), journal))
 		}
 	}
 
 	bb.logger.Debugln("Copying in config files...")
 	for _, file := range bb.config.ConfigFiles() {
-		if fileShouldBeExcluded(file, excludeFiles) {
-			bb.logger.Infof("Excluding %s...", file)
-			continue
-		}
-		bb.logger.Debugf("Copying %s...", file)
-		err = bb.copyFileFromVMOrLocal(writer, file, path.Join(zipPrefix, configPrefix), logLines)
-		if err != nil {
-			bb.logger.Warnf("Could not copy in %q. Error: %s", file, err)
-		}
+		items = append(items, bb.fileItem(file, excludeFiles, redaction, path.Join(bundleDir, configPrefix), journal))
 	}
 
+	items = append(items, bb.diagnosticItems(redaction, excludeFiles, bundleDir)...)
+
 	bb.logger.Debugln("Copying in additional files...")
 	for _, file := range additionalFiles {
-		if fileShouldBeExcluded(file, excludeFiles) {
-			bb.logger.Infof("Excluding %s...", file)
-			continue
-		}
-		bb.logger.Debugf("Copying %s...", file)
-		err = bb.copyFileFromVMOrLocal(writer, file, filepath.Join(zipPrefix, additionalPrefix), logLines)
+		item := bb.fileItem(file, excludeFiles, redaction, filepath.Join(bundleDir, additionalPrefix), journal)
+		item.warning = "Could not add additional file %s. Error: %s"
+		items = append(items, item)
+	}
+
+	bb.collectItems(ctx, bw, manifest, items, itemTimeout)
+	if ctx.Err() != nil {
+		manifest.Partial = true
+		manifest.PartialReason = interruption(ctx)
+		bb.logger.Warnf("Writing a partial support bundle, as %s", manifest.PartialReason)
+	}
+
+	if cfg.MaxSize > 0 {
+		truncated, fits, err := bw.truncate(manifest, cfg.MaxSize)
 		if err != nil {
-			bb.logger.Warnf("Could not add additional file %s. Error: %s", file, err)
+			return "", err
 		}
+		if truncated > 0 {
+			bb.logger.Infof("Truncated %d log files to fit the bundle in %d bytes", truncated, cfg.MaxSize)
+		}
+		if !fits {
+			bb.logger.Warnf("The bundle is larger than %d bytes, even with its log files truncated", cfg.MaxSize)
+		}
+	}
+
+	manifest.redact(redaction.builtin)
+	err = bw.writeUnrecorded(func(w zipCreator) error {
+		return writeManifest(w, manifest, bundleDir)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	err = bw.write(archive)
+	if err != nil {
+		return "", err
 	}
 
-	err = writer.Close()
+	err = archive.Close()
 	if err != nil {
 		return "", err
 	}
 
-	return zipFileName, nil
+	if bundleFile != nil {
+		err = bundleFile.Close()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if manifest.Partial {
+		// a bundle named by default is renamed, so that it can't be mistaken for a complete one
+		if bundleName != Stdout && bundleName != cfg.Output {
+			partialName := trimBundleExtension(bundleName) + "-partial" + bundleExtension(format)
+			if err := bb.fs.Rename(bundleName, partialName); err != nil {
+				return bundleName, err
+			}
+			bundleName = partialName
+		}
+		return bundleName, fmt.Errorf("%w: %s", ErrPartialBundle, manifest.PartialReason)
+	}
+	return bundleName, nil
+}
+
+// fileItem returns the item of the bundle copying the file, or excluded if it matches excludeFiles.
+func (bb *bundleBuilder) fileItem(
+	file string,
+	excludeFiles []string,
+	bundleRedaction *bundleRedaction,
+	zipPath string,
+	journal journalOptions,
+) *bundleItem {
+	if fileShouldBeExcluded(file, excludeFiles) {
+		bb.logger.Infof("Excluding %s...", file)
+		return &bundleItem{source: file, excluded: true}
+	}
+	redaction := bundleRedaction.forItem(file)
+	return &bundleItem{
+		source:    file,
+		redaction: redaction,
+		collect: func(ctx context.Context, w zipCreator) error {
+			return bb.copyFileFromVMOrLocal(ctx, w, redaction, file, zipPath, journal)
+		},
+		started: "Copying %s...",
+		warning: "Could not copy in %q. Error: %s",
+	}
 }
 
 type bufReader interface {
 	ReadBytes(delim byte) ([]byte, error)
 }
 
-func (bb *bundleBuilder) copyFileFromVMOrLocal(writer *zip.Writer, filename, zipPath string, logLines int) error {
+// loadRedactionRules loads the user-defined redaction rules of rulesPath, or of the finch directory if it is empty.
+func (bb *bundleBuilder) loadRedactionRules(rulesPath string) ([]*RedactionRule, error) {
+	required := rulesPath != ""
+	if !required {
+		rulesPath = bb.config.RedactionRulesFile()
+	}
+	rules, err := LoadRedactionRules(bb.fs, rulesPath, required)
+	if err != nil {
+		return nil, err
+	}
+	if len(rules) > 0 {
+		bb.logger.Debugf("Loaded %d redaction rules from %s", len(rules), rulesPath)
+	}
+	return rules, nil
+}
+
+// copyFileFromVMOrLocal copies the file in the bundle.
+func (bb *bundleBuilder) copyFileFromVMOrLocal(
+	ctx context.Context,
+	writer zipCreator,
+	redaction *itemRedaction,
+	filename, zipPath string,
+	journal journalOptions,
+) error {
 	if runtime.GOOS != "linux" && (isFileFromVM(filename) || isService(filename)) {
-		return bb.streamFileFromVM(writer, filename, zipPath, logLines)
+		return bb.streamFileFromVM(ctx, writer, redaction, filename, zipPath, journal)
 	}
-	return bb.copyInFile(writer, filename, zipPath, logLines)
+	return bb.copyInFile(ctx, writer, redaction, filename, zipPath, journal)
 }
 
-func (bb *bundleBuilder) copyAndRedactFile(writer io.Writer, reader bufReader) error {
+// copyAndRedactFile copies the file line by line, applying the redaction rules of the item.
+func (bb *bundleBuilder) copyAndRedactFile(writer io.Writer, reader bufReader, redaction *itemRedaction) error {
 	var bufErr error
 	for bufErr == nil {
 		var line []byte
 		line, bufErr = reader.ReadBytes('\n')
 		if bufErr != nil && !errors.Is(bufErr, io.EOF) {
-			bb.logger.Error(bufErr.Error())
+			// the pipe of a command is closed when the command is stopped
+			if !errors.Is(bufErr, io.ErrClosedPipe) {
+				bb.logger.Error(bufErr.Error())
+			}
 			continue
 		}
 
-		line, err := redactFinchInstall(line, bb.finch)
-		if err != nil {
-			return err
-		}
-
-		user := bb.lima.LimaUser(false)
-		line, err = redactUsername(line, user.Username)
-		if err != nil {
-			return err
-		}
-
-		line = redactNetworkAddresses(line)
-		line = redactPorts(line)
-		line = redactSSHKeys(line)
+		line = redaction.apply(line)
 
-		_, err = writer.Write(line)
+		_, err := writer.Write(line)
 		if err != nil {
 			return err
 		}
@@ -250,25 +400,24 @@ func (bb *bundleBuilder) copyAndRedactFile(writer io.Writer, reader bufReader) e
 	return nil
 }
 
-func (bb *bundleBuilder) copyInFile(writer *zip.Writer, fileName string, prefix string, logLines int) error {
-	var f io.Reader
+func (bb *bundleBuilder) copyInFile(
+	ctx context.Context,
+	writer zipCreator,
+	redaction *itemRedaction,
+	fileName string,
+	prefix string,
+	journal journalOptions,
+) error {
 	if isService(fileName) {
-		service := strings.TrimPrefix(fileName, "service:")
-		cmd := bb.ecc.Create("journalctl", "-n", strconv.Itoa(logLines), "--no-pager", "-xu", service)
-		out, err := cmd.Output()
-		if err != nil {
-			return err
-		}
-		f = bytes.NewReader(out)
-		fileName = service
-	} else {
-		var err error
-		// check filename validity?
-		f, err = bb.fs.Open(fileName)
-		if err != nil {
-			return err
-		}
+		return bb.streamLocalJournal(ctx, writer, redaction, strings.TrimPrefix(fileName, "service:"), prefix, journal)
+	}
+
+	// check filename validity?
+	f, err := bb.fs.Open(fileName)
+	if err != nil {
+		return err
 	}
+	defer f.Close() //nolint:errcheck // the file is only read
 
 	baseName := filepath.Base(fileName)
 	zipCopy, err := writer.Create(path.Join(prefix, baseName))
@@ -281,17 +430,70 @@ func (bb *bundleBuilder) copyInFile(writer *zip.Writer, fileName string, prefix
 	if err != nil {
 		return err
 	}
-	return bb.copyAndRedactFile(zipCopy, buf)
+	return bb.copyAndRedactFile(zipCopy, buf, redaction)
+}
+
+// streamLocalJournal copies the journal logs of the service of the host in the bundle, as they are read,
+// so that only the end of the logs kept by copyAndRedactJournal is held in memory.
+func (bb *bundleBuilder) streamLocalJournal(
+	ctx context.Context,
+	writer zipCreator,
+	redaction *itemRedaction,
+	service, prefix string,
+	journal journalOptions,
+) error {
+	zipCopy, err := writer.Create(path.Join(prefix, filepath.Base(service)))
+	if err != nil {
+		return err
+	}
+
+	args := journal.args(service)
+	cmd := bb.ecc.Create(args[0], args[1:]...)
+	pipeReader, pipeWriter := io.Pipe()
+	stderr := new(bytes.Buffer)
+	cmd.SetStdout(pipeWriter)
+	cmd.SetStderr(stderr)
+
+	runStatus := make(chan error, 1)
+	go func() {
+		err := bb.runCommand(ctx, cmd)
+		// runCommand returns when ctx is done, even if the command does not exit, which stops the copy
+		_ = pipeWriter.CloseWithError(ctx.Err())
+		runStatus <- err
+	}()
+
+	if err := bb.copyAndRedactJournal(zipCopy, bufio.NewReader(pipeReader), redaction, journal); err != nil {
+		// unblock the command writing the logs
+		_ = pipeReader.CloseWithError(err)
+		<-runStatus
+		return err
+	}
+	if err := <-runStatus; err != nil {
+		if ctx.Err() == nil && stderr.Len() > 0 {
+			err = fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+		}
+		return err
+	}
+	return nil
 }
 
-func (bb *bundleBuilder) streamFileFromVM(writer *zip.Writer, filename, prefix string, logLines int) error {
+// streamFileFromVM copies the file or the journal logs of the service from the VM in the bundle, as they are read.
+// If ctx is done, the command reading them is killed, and the copy stops.
+func (bb *bundleBuilder) streamFileFromVM(
+	ctx context.Context,
+	writer zipCreator,
+	redaction *itemRedaction,
+	filename, prefix string,
+	journal journalOptions,
+) error {
 	pipeReader, pipeWriter := io.Pipe()
 	errBuf := new(bytes.Buffer)
 
 	_, filePathInVM, _ := strings.Cut(filename, ":")
+	service := isService(filename)
 	var cmd command.Command
-	if isService(filename) {
-		cmd = bb.ncc.CreateWithoutStdio("shell", "finch", "sudo", "journalctl", "-n", strconv.Itoa(logLines), "--no-pager", "-xu", filePathInVM)
+	if service {
+		cmd = bb.ncc.CreateWithoutStdio(append([]string{"shell", "finch", "sudo"}, journal.args(filePathInVM)...)...)
 		// omit service prefix
 		filename = filePathInVM
 	} else {
@@ -305,7 +507,7 @@ func (bb *bundleBuilder) streamFileFromVM(writer *zip.Writer, filename, prefix s
 		return err
 	}
 
-	waitStatus := make(chan error)
+	waitStatus := make(chan error, 1)
 	go func() {
 		err := cmd.Wait()
 		if err != nil {
@@ -317,6 +519,14 @@ func (bb *bundleBuilder) streamFileFromVM(writer *zip.Writer, filename, prefix s
 		_ = pipeWriter.Close()
 		waitStatus <- err
 	}()
+	stop := context.AfterFunc(ctx, func() {
+		if err := cmd.Signal(os.Kill); err != nil {
+			bb.logger.Debugf("Failed to kill the command copying %s: %v", filename, err)
+		}
+		// stop the copy, even if the command does not exit
+		_ = pipeReader.CloseWithError(ctx.Err())
+	})
+	defer stop()
 
 	baseName := filepath.Base(filename)
 	zipCopy, err := writer.Create(path.Join(prefix, baseName))
@@ -326,12 +536,35 @@ func (bb *bundleBuilder) streamFileFromVM(writer *zip.Writer, filename, prefix s
 
 	bufReader := bufio.NewReader(pipeReader)
 
-	err = bb.copyAndRedactFile(zipCopy, bufReader)
+	if service {
+		err = bb.copyAndRedactJournal(zipCopy, bufReader, redaction, journal)
+	} else {
+		err = bb.copyAndRedactFile(zipCopy, bufReader, redaction)
+	}
 	if err != nil {
 		return err
 	}
 
-	return <-waitStatus
+	select {
+	case err := <-waitStatus:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// copyAndRedactJournal copies the journal logs of a service like copyAndRedactFile,
+// truncating them to the maximum size of the journal logs.
+func (bb *bundleBuilder) copyAndRedactJournal(writer io.Writer, reader bufReader, redaction *itemRedaction, journal journalOptions) error {
+	tw := newTailWriter(journal.maxSize)
+	if err := bb.copyAndRedactFile(tw, reader, redaction); err != nil {
+		return err
+	}
+	if tw.truncated() {
+		markTruncated(writer, tw.written)
+	}
+	_, err := writer.Write(tw.bytes())
+	return err
 }
 
 func (bb *bundleBuilder) getPlatformData() (*PlatformData, error) {
@@ -350,6 +583,10 @@ func (bb *bundleBuilder) getPlatformData() (*PlatformData, error) {
 	// populate Finch version
 	platform.Finch = getFinchVersion()
 
+	if runtime.GOOS == "linux" {
+		platform.Package = bb.getPackageVersion()
+	}
+
 	return platform, nil
 }
 
@@ -402,7 +639,31 @@ func (bb *bundleBuilder) getFinchVersion() string {
 	return output
 }
 
-func writePlatformData(writer *zip.Writer, platform *PlatformData, prefix string) error {
+// getPackageVersion returns the name and the version of the rpm or deb package owning the finch executable,
+// e.g. "runfinch-finch 1.4.1", or an empty string if it is not owned by a package, e.g. if it was built from source.
+func (bb *bundleBuilder) getPackageVersion() string {
+	executable, err := bb.systemDeps.Executable()
+	if err != nil {
+		return ""
+	}
+	out, err := bb.ecc.Create("rpm", "-qf", "--queryformat", "%{NAME} %{VERSION}-%{RELEASE}", executable).Output()
+	if err == nil {
+		return strings.TrimSpace(string(out))
+	}
+	// e.g. "runfinch-finch: /usr/bin/finch", or "runfinch-finch:amd64: /usr/bin/finch"
+	out, err = bb.ecc.Create("dpkg-query", "-S", executable).Output()
+	if err != nil {
+		return ""
+	}
+	pkg, _, _ := strings.Cut(string(out), ":")
+	out, err = bb.ecc.Create("dpkg-query", "-W", "-f", "${Package} ${Version}", pkg).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func writePlatformData(writer zipCreator, platform *PlatformData, prefix string) error {
 	platformFile, err := writer.Create(path.Join(prefix, platformFileName))
 	if err != nil {
 		return err
@@ -421,9 +682,31 @@ func writePlatformData(writer *zip.Writer, platform *PlatformData, prefix string
 	return nil
 }
 
-func bundleFileName() string {
+func bundleFileName(format string) string {
 	timestamp := time.Now().Format("20060102150405")
-	return fmt.Sprintf("%s-%s.zip", bundlePrefix, timestamp)
+	return fmt.Sprintf("%s-%s%s", bundlePrefix, timestamp, bundleExtension(format))
+}
+
+// bundleName returns the path of the bundle written to output, and the directory of its entries.
+// The bundle is written to the current directory if output is empty, and to the standard output if it is "-".
+func (bb *bundleBuilder) bundleName(output, format string) (string, string, error) {
+	fileName := bundleFileName(format)
+	switch output {
+	case "":
+	case Stdout:
+		return Stdout, trimBundleExtension(fileName), nil
+	default:
+		isDir, err := afero.IsDir(bb.fs, output)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return "", "", err
+		}
+		if isDir {
+			fileName = filepath.Join(output, fileName)
+		} else {
+			fileName = output
+		}
+	}
+	return fileName, trimBundleExtension(filepath.Base(fileName)), nil
 }
 
 func fileShouldBeExcluded(filename string, exclude []string) bool {
@@ -458,7 +741,7 @@ func isService(filename string) bool {
 	return strings.HasPrefix(filename, "service:")
 }
 
-func writeVersionOutput(writer *zip.Writer, version, prefix string) error {
+func writeVersionOutput(writer zipCreator, version, prefix string) error {
 	versionFile, err := writer.Create(path.Join(prefix, versionFileName))
 	if err != nil {
 		return err
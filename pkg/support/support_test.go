@@ -5,10 +5,15 @@ package support
 
 import (
 	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os/user"
+	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
 	"time"
 
@@ -34,7 +39,7 @@ func TestSupport_NewBundleBuilder(t *testing.T) {
 	systemDeps := mocks.NewSupportSystemDeps(ctrl)
 
 	config := NewBundleConfig(finch, "mockhome")
-	NewBundleBuilder(logger, fs, config, finch, ecc, ncc, lima, systemDeps)
+	NewBundleBuilder(logger, fs, config, finch, ecc, ncc, lima, systemDeps, new(bytes.Buffer))
 }
 
 func TestSupportBundleBuilder_GenerateSupportBundle(t *testing.T) {
@@ -56,8 +61,12 @@ func TestSupportBundleBuilder_GenerateSupportBundle(t *testing.T) {
 			*mocks.SupportSystemDeps,
 			afero.Fs,
 		)
-		include []string
-		exclude []string
+		include       []string
+		exclude       []string
+		output        string
+		format        string
+		maxSize       int64
+		wantTruncated bool
 	}
 
 	testCases := testcases{
@@ -73,6 +82,8 @@ func TestSupportBundleBuilder_GenerateSupportBundle(t *testing.T) {
 				systemDeps *mocks.SupportSystemDeps,
 				_ afero.Fs,
 			) {
+				config.EXPECT().RedactionRulesFile().Return("redaction-rules.yaml")
+				logger.EXPECT().Debugln("Collecting diagnostics...")
 				logger.EXPECT().Debugf("Creating %s...", gomock.Any())
 				logger.EXPECT().Debugln("Gathering platform data...")
 
@@ -83,6 +94,7 @@ func TestSupportBundleBuilder_GenerateSupportBundle(t *testing.T) {
 					ecc.EXPECT().Create("sw_vers", "-productVersion").Return(cmd)
 				case "linux":
 					ecc.EXPECT().Create("uname", "-r").Return(cmd)
+					expectPackageVersion(ecc, cmd, systemDeps)
 				default:
 					cmd = nil
 				}
@@ -118,6 +130,134 @@ func TestSupportBundleBuilder_GenerateSupportBundle(t *testing.T) {
 			include: []string{},
 			exclude: []string{},
 		},
+		{
+			name: "Generate support bundle as a tar.gz on the standard output",
+			mockSvc: func(
+				logger *mocks.Logger,
+				config *mocks.BundleConfig,
+				ecc *mocks.CommandCreator,
+				ncc *mocks.NerdctlCmdCreator,
+				cmd *mocks.Command,
+				lima *mocks.MockLimaWrapper,
+				systemDeps *mocks.SupportSystemDeps,
+				_ afero.Fs,
+			) {
+				config.EXPECT().RedactionRulesFile().Return("redaction-rules.yaml")
+				logger.EXPECT().Debugln("Collecting diagnostics...")
+				logger.EXPECT().Debugln("Gathering platform data...")
+
+				switch runtime.GOOS {
+				case "windows":
+					ecc.EXPECT().Create("cmd", "/c", "ver").Return(cmd)
+				case "darwin":
+					ecc.EXPECT().Create("sw_vers", "-productVersion").Return(cmd)
+				case "linux":
+					ecc.EXPECT().Create("uname", "-r").Return(cmd)
+					expectPackageVersion(ecc, cmd, systemDeps)
+				default:
+					cmd = nil
+				}
+
+				cmd.EXPECT().Output().Return([]byte("1.2.3\n"), nil).AnyTimes()
+
+				logger.EXPECT().Debugln("Collecting finch version output...")
+				systemDeps.EXPECT().Executable().Return("/bin/path", nil)
+				ecc.EXPECT().Create("/bin/path", "version").Return(cmd)
+
+				config.EXPECT().LogFiles().Return([]string{
+					"log1",
+					"log2",
+				})
+
+				config.EXPECT().ConfigFiles().Return([]string{
+					"config1",
+					"config2",
+				})
+
+				logger.EXPECT().Debugln("Copying in log files...")
+				logger.EXPECT().Debugf("Copying %s...", "log1")
+				logger.EXPECT().Debugf("Copying %s...", "log2")
+				checkJournalCmdOutputs(logger, config, ecc, ncc, cmd, lima, mockUser)
+
+				logger.EXPECT().Debugln("Copying in config files...")
+				logger.EXPECT().Debugf("Copying %s...", "config1")
+				logger.EXPECT().Debugf("Copying %s...", "config2")
+				logger.EXPECT().Debugln("Copying in additional files...")
+
+				lima.EXPECT().LimaUser(false).Return(mockUser).AnyTimes()
+			},
+			include: []string{},
+			exclude: []string{},
+			output:  Stdout,
+			format:  FormatTarGz,
+		},
+		{
+			name: "Generate support bundle in a directory with its logs truncated",
+			mockSvc: func(
+				logger *mocks.Logger,
+				config *mocks.BundleConfig,
+				ecc *mocks.CommandCreator,
+				ncc *mocks.NerdctlCmdCreator,
+				cmd *mocks.Command,
+				lima *mocks.MockLimaWrapper,
+				systemDeps *mocks.SupportSystemDeps,
+				fs afero.Fs,
+			) {
+				require.NoError(t, fs.Mkdir("bundles", 0o755))
+				config.EXPECT().RedactionRulesFile().Return("redaction-rules.yaml")
+				logger.EXPECT().Debugln("Collecting diagnostics...")
+				logger.EXPECT().Debugf("Creating %s...", gomock.Any())
+				logger.EXPECT().Debugln("Gathering platform data...")
+
+				switch runtime.GOOS {
+				case "windows":
+					ecc.EXPECT().Create("cmd", "/c", "ver").Return(cmd)
+				case "darwin":
+					ecc.EXPECT().Create("sw_vers", "-productVersion").Return(cmd)
+				case "linux":
+					ecc.EXPECT().Create("uname", "-r").Return(cmd)
+					expectPackageVersion(ecc, cmd, systemDeps)
+				default:
+					cmd = nil
+				}
+
+				cmd.EXPECT().Output().Return([]byte("1.2.3\n"), nil).AnyTimes()
+
+				logger.EXPECT().Debugln("Collecting finch version output...")
+				systemDeps.EXPECT().Executable().Return("/bin/path", nil)
+				ecc.EXPECT().Create("/bin/path", "version").Return(cmd)
+
+				config.EXPECT().LogFiles().Return([]string{
+					"log1",
+					"log2",
+				})
+
+				config.EXPECT().ConfigFiles().Return([]string{
+					"config1",
+					"config2",
+				})
+
+				logger.EXPECT().Debugln("Copying in log files...")
+				logger.EXPECT().Debugf("Copying %s...", "log1")
+				logger.EXPECT().Debugf("Copying %s...", "log2")
+				checkJournalCmdOutputs(logger, config, ecc, ncc, cmd, lima, mockUser)
+
+				logger.EXPECT().Debugln("Copying in config files...")
+				logger.EXPECT().Debugf("Copying %s...", "config1")
+				logger.EXPECT().Debugf("Copying %s...", "config2")
+				logger.EXPECT().Debugln("Copying in additional files...")
+
+				logger.EXPECT().Infof("Truncated %d log files to fit the bundle in %d bytes", gomock.Any(), int64(1))
+				logger.EXPECT().Warnf("The bundle is larger than %d bytes, even with its log files truncated", int64(1))
+
+				lima.EXPECT().LimaUser(false).Return(mockUser).AnyTimes()
+			},
+			include:       []string{},
+			exclude:       []string{},
+			output:        "bundles",
+			maxSize:       1,
+			wantTruncated: true,
+		},
 		{
 			name: "Generate support bundle with an extra file included",
 			mockSvc: func(
@@ -130,6 +270,8 @@ func TestSupportBundleBuilder_GenerateSupportBundle(t *testing.T) {
 				systemDeps *mocks.SupportSystemDeps,
 				_ afero.Fs,
 			) {
+				config.EXPECT().RedactionRulesFile().Return("redaction-rules.yaml")
+				logger.EXPECT().Debugln("Collecting diagnostics...")
 				logger.EXPECT().Debugf("Creating %s...", gomock.Any())
 				logger.EXPECT().Debugln("Gathering platform data...")
 
@@ -140,6 +282,7 @@ func TestSupportBundleBuilder_GenerateSupportBundle(t *testing.T) {
 					ecc.EXPECT().Create("sw_vers", "-productVersion").Return(cmd)
 				case "linux":
 					ecc.EXPECT().Create("uname", "-r").Return(cmd)
+					expectPackageVersion(ecc, cmd, systemDeps)
 				default:
 					cmd = nil
 				}
@@ -184,6 +327,8 @@ func TestSupportBundleBuilder_GenerateSupportBundle(t *testing.T) {
 				systemDeps *mocks.SupportSystemDeps,
 				_ afero.Fs,
 			) {
+				config.EXPECT().RedactionRulesFile().Return("redaction-rules.yaml")
+				logger.EXPECT().Debugln("Collecting diagnostics...")
 				logger.EXPECT().Debugf("Creating %s...", gomock.Any())
 				logger.EXPECT().Debugln("Gathering platform data...")
 
@@ -194,6 +339,7 @@ func TestSupportBundleBuilder_GenerateSupportBundle(t *testing.T) {
 					ecc.EXPECT().Create("sw_vers", "-productVersion").Return(cmd)
 				case "linux":
 					ecc.EXPECT().Create("uname", "-r").Return(cmd)
+					expectPackageVersion(ecc, cmd, systemDeps)
 				default:
 					cmd = nil
 				}
@@ -237,6 +383,8 @@ func TestSupportBundleBuilder_GenerateSupportBundle(t *testing.T) {
 				systemDeps *mocks.SupportSystemDeps,
 				_ afero.Fs,
 			) {
+				config.EXPECT().RedactionRulesFile().Return("redaction-rules.yaml")
+				logger.EXPECT().Debugln("Collecting diagnostics...")
 				logger.EXPECT().Debugf("Creating %s...", gomock.Any())
 				logger.EXPECT().Debugln("Gathering platform data...")
 
@@ -247,6 +395,7 @@ func TestSupportBundleBuilder_GenerateSupportBundle(t *testing.T) {
 					ecc.EXPECT().Create("sw_vers", "-productVersion").Return(cmd)
 				case "linux":
 					ecc.EXPECT().Create("uname", "-r").Return(cmd)
+					expectPackageVersion(ecc, cmd, systemDeps)
 				default:
 					cmd = nil
 				}
@@ -290,6 +439,8 @@ func TestSupportBundleBuilder_GenerateSupportBundle(t *testing.T) {
 				systemDeps *mocks.SupportSystemDeps,
 				_ afero.Fs,
 			) {
+				config.EXPECT().RedactionRulesFile().Return("redaction-rules.yaml")
+				logger.EXPECT().Debugln("Collecting diagnostics...")
 				logger.EXPECT().Debugf("Creating %s...", gomock.Any())
 				logger.EXPECT().Debugln("Gathering platform data...")
 
@@ -300,6 +451,7 @@ func TestSupportBundleBuilder_GenerateSupportBundle(t *testing.T) {
 					ecc.EXPECT().Create("sw_vers", "-productVersion").Return(cmd)
 				case "linux":
 					ecc.EXPECT().Create("uname", "-r").Return(cmd)
+					expectPackageVersion(ecc, cmd, systemDeps)
 				default:
 					cmd = nil
 				}
@@ -347,6 +499,8 @@ func TestSupportBundleBuilder_GenerateSupportBundle(t *testing.T) {
 				systemDeps *mocks.SupportSystemDeps,
 				_ afero.Fs,
 			) {
+				config.EXPECT().RedactionRulesFile().Return("redaction-rules.yaml")
+				logger.EXPECT().Debugln("Collecting diagnostics...")
 				logger.EXPECT().Debugf("Creating %s...", gomock.Any())
 				logger.EXPECT().Debugln("Gathering platform data...")
 
@@ -357,6 +511,7 @@ func TestSupportBundleBuilder_GenerateSupportBundle(t *testing.T) {
 					ecc.EXPECT().Create("sw_vers", "-productVersion").Return(cmd)
 				case "linux":
 					ecc.EXPECT().Create("uname", "-r").Return(cmd)
+					expectPackageVersion(ecc, cmd, systemDeps)
 				default:
 					cmd = nil
 				}
@@ -423,6 +578,8 @@ func TestSupportBundleBuilder_GenerateSupportBundle(t *testing.T) {
 				systemDeps *mocks.SupportSystemDeps,
 				_ afero.Fs,
 			) {
+				config.EXPECT().RedactionRulesFile().Return("redaction-rules.yaml")
+				logger.EXPECT().Debugln("Collecting diagnostics...")
 				logger.EXPECT().Debugf("Creating %s...", gomock.Any())
 				logger.EXPECT().Debugln("Gathering platform data...")
 
@@ -433,6 +590,7 @@ func TestSupportBundleBuilder_GenerateSupportBundle(t *testing.T) {
 					ecc.EXPECT().Create("sw_vers", "-productVersion").Return(cmd)
 				case "linux":
 					ecc.EXPECT().Create("uname", "-r").Return(cmd)
+					expectPackageVersion(ecc, cmd, systemDeps)
 				default:
 					cmd = nil
 				}
@@ -490,6 +648,8 @@ func TestSupportBundleBuilder_GenerateSupportBundle(t *testing.T) {
 			cmd := mocks.NewCommand(ctrl)
 			systemDeps := mocks.NewSupportSystemDeps(ctrl)
 
+			stdout := new(bytes.Buffer)
+
 			builder := &bundleBuilder{
 				logger:     logger,
 				fs:         fs,
@@ -499,6 +659,8 @@ func TestSupportBundleBuilder_GenerateSupportBundle(t *testing.T) {
 				ncc:        ncc,
 				lima:       lima,
 				systemDeps: systemDeps,
+				stdout:     stdout,
+				workers:    defaultBundleWorkers,
 			}
 
 			testFiles := []string{
@@ -523,14 +685,51 @@ func TestSupportBundleBuilder_GenerateSupportBundle(t *testing.T) {
 				AdditionalFiles: tc.include,
 				ExcludeFiles:    tc.exclude,
 				LogLines:        100,
+				Output:          tc.output,
+				Format:          tc.format,
+				MaxSize:         tc.maxSize,
 			}
 
-			zipFile, err := builder.GenerateSupportBundle(cfg)
+			bundleFile, err := builder.GenerateSupportBundle(context.Background(), cfg)
 			assert.NoError(t, err)
 
-			exists, err := afero.Exists(fs, zipFile)
-			assert.NoError(t, err)
-			assert.True(t, exists)
+			var bundle []byte
+			if tc.output == Stdout {
+				assert.Equal(t, Stdout, bundleFile)
+				bundle = stdout.Bytes()
+			} else {
+				if tc.output != "" {
+					assert.Equal(t, tc.output, filepath.Dir(bundleFile))
+				}
+				bundle, err = afero.ReadFile(fs, bundleFile)
+				require.NoError(t, err)
+			}
+
+			format := tc.format
+			if format == "" {
+				format = FormatZip
+			}
+			manifest := readManifest(t, bundle, format)
+			assert.Equal(t, ManifestSchemaVersion, manifest.SchemaVersion)
+			assert.Equal(t, *cfg, manifest.Options)
+			sources := map[string]ManifestItem{}
+			for _, item := range manifest.Items {
+				sources[item.Source] = item
+			}
+			assert.Contains(t, sources, "platform")
+			assert.Contains(t, sources, "finch version")
+			for _, file := range tc.include {
+				assert.Contains(t, sources, file)
+			}
+			for _, file := range tc.exclude {
+				assert.True(t, sources[file].Excluded, "%s is not recorded as excluded", file)
+			}
+			if tc.wantTruncated {
+				assert.True(t, sources["log1"].Truncated)
+				assert.Equal(t, int64(14), sources["log1"].OriginalSize)
+				assert.Equal(t, int64(0), sources["log1"].Size)
+				assert.False(t, sources["config1"].Truncated)
+			}
 		})
 	}
 }
@@ -581,16 +780,78 @@ func TestSupport_writePlatformData(t *testing.T) {
 	}
 }
 
+func TestSupport_getPackageVersion(t *testing.T) {
+	t.Parallel()
+
+	rpmArgs := []string{"-qf", "--queryformat", "%{NAME} %{VERSION}-%{RELEASE}", "/usr/bin/finch"}
+	testCases := []struct {
+		name    string
+		mockSvc func(*mocks.CommandCreator, *mocks.Command, *mocks.SupportSystemDeps)
+		want    string
+	}{
+		{
+			name: "rpm package",
+			mockSvc: func(ecc *mocks.CommandCreator, cmd *mocks.Command, systemDeps *mocks.SupportSystemDeps) {
+				systemDeps.EXPECT().Executable().Return("/usr/bin/finch", nil)
+				ecc.EXPECT().Create("rpm", rpmArgs).Return(cmd)
+				cmd.EXPECT().Output().Return([]byte("runfinch-finch 1.4.1-1.amzn2023"), nil)
+			},
+			want: "runfinch-finch 1.4.1-1.amzn2023",
+		},
+		{
+			name: "deb package",
+			mockSvc: func(ecc *mocks.CommandCreator, cmd *mocks.Command, systemDeps *mocks.SupportSystemDeps) {
+				systemDeps.EXPECT().Executable().Return("/usr/bin/finch", nil)
+				ecc.EXPECT().Create("rpm", rpmArgs).Return(cmd)
+				cmd.EXPECT().Output().Return(nil, errors.New("exec: \"rpm\": executable file not found in $PATH"))
+				ecc.EXPECT().Create("dpkg-query", "-S", "/usr/bin/finch").Return(cmd)
+				cmd.EXPECT().Output().Return([]byte("runfinch-finch:amd64: /usr/bin/finch\n"), nil)
+				ecc.EXPECT().Create("dpkg-query", "-W", "-f", "${Package} ${Version}", "runfinch-finch").Return(cmd)
+				cmd.EXPECT().Output().Return([]byte("runfinch-finch 1.4.1"), nil)
+			},
+			want: "runfinch-finch 1.4.1",
+		},
+		{
+			name: "not installed with a package",
+			mockSvc: func(ecc *mocks.CommandCreator, cmd *mocks.Command, systemDeps *mocks.SupportSystemDeps) {
+				systemDeps.EXPECT().Executable().Return("/usr/bin/finch", nil)
+				ecc.EXPECT().Create("rpm", rpmArgs).Return(cmd)
+				cmd.EXPECT().Output().Return(nil, errors.New("exit status 1"))
+				ecc.EXPECT().Create("dpkg-query", "-S", "/usr/bin/finch").Return(cmd)
+				cmd.EXPECT().Output().Return(nil, errors.New("exit status 1"))
+			},
+			want: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			ecc := mocks.NewCommandCreator(ctrl)
+			cmd := mocks.NewCommand(ctrl)
+			systemDeps := mocks.NewSupportSystemDeps(ctrl)
+			tc.mockSvc(ecc, cmd, systemDeps)
+
+			bb := &bundleBuilder{ecc: ecc, systemDeps: systemDeps}
+			assert.Equal(t, tc.want, bb.getPackageVersion())
+		})
+	}
+}
+
 func TestSupport_bundleFileName(t *testing.T) {
 	t.Parallel()
 
-	first := bundleFileName()
+	first := bundleFileName(FormatZip)
 	time.Sleep(time.Second)
-	second := bundleFileName()
+	second := bundleFileName(FormatTarGz)
 
 	assert.Contains(t, first, bundlePrefix)
 	assert.Contains(t, second, bundlePrefix)
 	assert.NotEqual(t, first, second)
+	assert.True(t, strings.HasSuffix(first, ".zip"))
+	assert.True(t, strings.HasSuffix(second, ".tar.gz"))
 }
 
 func TestSupport_fileShouldBeExcluded(t *testing.T) {
@@ -746,6 +1007,86 @@ func TestSupport_writeVersionOutput(t *testing.T) {
 	}
 }
 
+// expectPackageVersion expects the version of the rpm package of finch to be queried, with the output of cmd.
+func TestSupportBundleBuilder_GenerateSupportBundle_redactedManifest(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	fs := afero.NewMemMapFs()
+	logger := mocks.NewLogger(ctrl)
+	config := mocks.NewBundleConfig(ctrl)
+	ecc := mocks.NewCommandCreator(ctrl)
+	lima := mocks.NewMockLimaWrapper(ctrl)
+	cmd := mocks.NewCommand(ctrl)
+	systemDeps := mocks.NewSupportSystemDeps(ctrl)
+	bb := &bundleBuilder{
+		logger:     logger,
+		fs:         fs,
+		config:     config,
+		finch:      fpath.Finch("/opt/finch"),
+		ecc:        ecc,
+		lima:       lima,
+		systemDeps: systemDeps,
+		workers:    defaultBundleWorkers,
+	}
+
+	// the bundle is generated under the fake home of alice, with files missing from it
+	home := "/Users/alice"
+	require.NoError(t, fs.MkdirAll(home+"/bundles", 0o755))
+	require.NoError(t, afero.WriteFile(fs, home+"/rules.yaml", []byte("rules:\n  - name: hosts\n    pattern: corp\n"), 0o644))
+	config.EXPECT().LogFiles().Return([]string{home + "/.finch/logs/finch.log"})
+	config.EXPECT().JournalServices().Return([]string{})
+	config.EXPECT().ConfigFiles().Return([]string{"/home/alice/.finch/finch.yaml"})
+	ecc.EXPECT().Create(gomock.Any(), gomock.Any()).Return(cmd).AnyTimes()
+	cmd.EXPECT().Output().Return([]byte("1.2.3\n"), nil).AnyTimes()
+	systemDeps.EXPECT().Executable().Return("/bin/path", nil).AnyTimes()
+	lima.EXPECT().LimaUser(false).Return(&user.User{Username: "alice"}).AnyTimes()
+	logger.EXPECT().Debugf(gomock.Any(), gomock.Any()).AnyTimes()
+	logger.EXPECT().Debugln(gomock.Any()).AnyTimes()
+	logger.EXPECT().Infof(gomock.Any(), gomock.Any()).AnyTimes()
+	logger.EXPECT().Info(gomock.Any()).AnyTimes()
+	logger.EXPECT().Warnf(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+
+	bundleFile, err := bb.GenerateSupportBundle(context.Background(), &BundleCfg{
+		AdditionalFiles: []string{home + "/notes.txt"},
+		ExcludeFiles:    []string{"diag:all", home + "/.ssh/config"},
+		LogLines:        100,
+		Output:          home + "/bundles",
+		RedactionRules:  home + "/rules.yaml",
+	})
+	require.NoError(t, err)
+
+	bundle, err := afero.ReadFile(fs, bundleFile)
+	require.NoError(t, err)
+	content := readBundle(t, bundle, FormatZip)
+	var manifestContent []byte
+	for name, c := range content {
+		if strings.HasSuffix(name, "/"+manifestFileName) {
+			manifestContent = c
+		}
+	}
+	require.NotNil(t, manifestContent)
+	assert.NotContains(t, string(manifestContent), "alice")
+
+	manifest := readManifest(t, bundle, FormatZip)
+	sources := map[string]ManifestItem{}
+	for _, item := range manifest.Items {
+		sources[item.Source] = item
+	}
+	require.Contains(t, sources, "/Users/<username-elided>/.finch/logs/finch.log")
+	require.Contains(t, sources, "/home/<username-elided>/.finch/finch.yaml")
+	assert.Contains(t, sources["/home/<username-elided>/.finch/finch.yaml"].Error, "<username-elided>")
+	assert.Equal(t, []string{"/Users/<username-elided>/notes.txt"}, manifest.Options.AdditionalFiles)
+	assert.Equal(t, []string{"diag:all", "/Users/<username-elided>/.ssh/config"}, manifest.Options.ExcludeFiles)
+	assert.Equal(t, "/Users/<username-elided>/bundles", manifest.Options.Output)
+	assert.Equal(t, "/Users/<username-elided>/rules.yaml", manifest.Options.RedactionRules)
+}
+
+func expectPackageVersion(ecc *mocks.CommandCreator, cmd *mocks.Command, systemDeps *mocks.SupportSystemDeps) {
+	systemDeps.EXPECT().Executable().Return("/bin/path", nil)
+	ecc.EXPECT().Create("rpm", "-qf", "--queryformat", "%{NAME} %{VERSION}-%{RELEASE}", "/bin/path").Return(cmd)
+}
+
 func checkJournalCmdOutputs(
 	logger *mocks.Logger,
 	config *mocks.BundleConfig,
@@ -766,18 +1107,17 @@ func checkJournalCmdOutputs(
 	logger.EXPECT().Debugln("Copying in journal logs...")
 
 	for _, service := range services {
+		logger.EXPECT().Debugf("Copying %s...", fmt.Sprintf("service:%s", service))
 		switch runtime.GOOS {
 		case "linux":
 			ecc.EXPECT().Create("journalctl", "-n", "100", "--no-pager", "-xu", service).Return(cmd)
-			logger.EXPECT().Debugf("Copying %s...", fmt.Sprintf("service:%s", service))
 		case "windows", "darwin":
-			logger.EXPECT().Debugf("Copying %s...", fmt.Sprintf("service:%s", service))
 			ncc.EXPECT().CreateWithoutStdio("shell", "finch", "sudo", "journalctl", "-n", "100", "--no-pager", "-xu", service).Return(cmd)
-			cmd.EXPECT().SetStdout(gomock.Any())
-			cmd.EXPECT().SetStderr(gomock.Any())
-			cmd.EXPECT().Start()
-			cmd.EXPECT().Wait()
 			lima.EXPECT().LimaUser(false).Return(mockUser).AnyTimes()
 		}
+		cmd.EXPECT().SetStdout(gomock.Any())
+		cmd.EXPECT().SetStderr(gomock.Any())
+		cmd.EXPECT().Start()
+		cmd.EXPECT().Wait()
 	}
 }
@@ -0,0 +1,257 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package support
+
+import (
+	"context"
+	"crypto/md5" //nolint:gosec // the ETag of S3 objects uploaded in a single part is their MD5 checksum
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/docker/go-units"
+	"github.com/spf13/afero"
+
+	"github.com/runfinch/finch/pkg/flog"
+)
+
+const (
+	// uploadAttempts is how many times the upload of a bundle is attempted, if it fails with a transient error.
+	uploadAttempts = 3
+	// uploadBackoff is how long to wait before the first retry of an upload, doubled for each following retry.
+	uploadBackoff = 2 * time.Second
+	// maxUploadErrorBody is how many bytes of the body of an error response are included in the error.
+	maxUploadErrorBody = 1024
+)
+
+// referenceIDHeaders are the response headers of the reference ID of an upload, in order of preference:
+// the one of the ticketing endpoints, then the version and the request ID of S3.
+var referenceIDHeaders = []string{"X-Reference-Id", "X-Amz-Version-Id", "X-Amz-Request-Id"}
+
+var md5ETag = regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+// BundleUploader uploads support bundles.
+//
+//go:generate mockgen -copyright_file=../../copyright_header -destination=../mocks/pkg_support_uploader.go -package=mocks -mock_names BundleUploader=BundleUploader . BundleUploader
+type BundleUploader interface {
+	// Upload uploads the bundle with an HTTPS PUT to uploadURL, e.g. a presigned URL of an S3 bucket,
+	// after verifying its files against its manifest. It returns the reference ID of the upload, to be shared
+	// with the support team, which is the SHA-256 checksum of the bundle if the endpoint returned none,
+	// and whether the endpoint returned the checksum of the bundle it received, which matches.
+	Upload(ctx context.Context, bundlePath string, uploadURL *url.URL) (referenceID string, verified bool, err error)
+}
+
+type bundleUploader struct {
+	fs     afero.Fs
+	logger flog.Logger
+	client *http.Client
+	// progress is where the progress of the uploads is printed.
+	progress io.Writer
+	attempts int
+	backoff  time.Duration
+}
+
+// NewBundleUploader returns a BundleUploader printing the progress of the uploads to progress.
+func NewBundleUploader(fs afero.Fs, logger flog.Logger, progress io.Writer) BundleUploader {
+	return &bundleUploader{
+		fs:       fs,
+		logger:   logger,
+		client:   &http.Client{},
+		progress: progress,
+		attempts: uploadAttempts,
+		backoff:  uploadBackoff,
+	}
+}
+
+// ParseUploadURL parses the URL support bundles are uploaded to, which must be an HTTPS URL.
+func ParseUploadURL(rawURL string) (*url.URL, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		// the error of url.Parse includes the URL, which may hold credentials
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return nil, fmt.Errorf("invalid upload URL: %w", err)
+	}
+	if u.Scheme != "https" || u.Host == "" {
+		return nil, fmt.Errorf("invalid upload URL %s, must be an https URL", DisplayUploadURL(u))
+	}
+	return u, nil
+}
+
+// DisplayUploadURL returns the URL without its user and its query, which may hold credentials,
+// e.g. the signature of a presigned URL.
+func DisplayUploadURL(u *url.URL) string {
+	display := *u
+	display.User = nil
+	display.RawQuery = ""
+	display.Fragment = ""
+	return display.String()
+}
+
+func (bu *bundleUploader) Upload(ctx context.Context, bundlePath string, uploadURL *url.URL) (string, bool, error) {
+	if err := VerifyBundle(bu.fs, bundlePath); err != nil {
+		return "", false, err
+	}
+	size, sha256Sum, md5Sum, err := bu.checksums(bundlePath)
+	if err != nil {
+		return "", false, err
+	}
+
+	var uploadErr error
+	for attempt := range bu.attempts {
+		if attempt > 0 {
+			delay := bu.backoff << (attempt - 1)
+			bu.logger.Warnf("Failed to upload the support bundle, retrying in %s. Error: %s", delay, uploadErr)
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return "", false, ctx.Err()
+			}
+		}
+
+		var resp *http.Response
+		var retry bool
+		resp, retry, uploadErr = bu.put(ctx, bundlePath, size, uploadURL)
+		if uploadErr == nil {
+			var verified bool
+			verified, uploadErr = verifyUpload(resp.Header, sha256Sum, md5Sum)
+			if uploadErr == nil {
+				return referenceID(resp.Header, sha256Sum), verified, nil
+			}
+			// the bundle may have been corrupted in transit
+			retry = true
+		}
+		if !retry {
+			return "", false, uploadErr
+		}
+	}
+	return "", false, fmt.Errorf("failed to upload the support bundle after %d attempts: %w", bu.attempts, uploadErr)
+}
+
+// checksums returns the size, the SHA-256 and the MD5 checksums of the bundle.
+func (bu *bundleUploader) checksums(bundlePath string) (int64, []byte, []byte, error) {
+	f, err := bu.fs.Open(bundlePath)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	defer f.Close() //nolint:errcheck // the file is only read
+
+	sha256Hash := sha256.New()
+	md5Hash := md5.New() //nolint:gosec // the ETag of S3 objects uploaded in a single part is their MD5 checksum
+	size, err := io.Copy(io.MultiWriter(sha256Hash, md5Hash), f)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	return size, sha256Hash.Sum(nil), md5Hash.Sum(nil), nil
+}
+
+// put streams the bundle in a PUT request to uploadURL. It returns whether the upload can be retried if it fails.
+func (bu *bundleUploader) put(ctx context.Context, bundlePath string, size int64, uploadURL *url.URL) (*http.Response, bool, error) {
+	f, err := bu.fs.Open(bundlePath)
+	if err != nil {
+		return nil, false, err
+	}
+	defer f.Close() //nolint:errcheck // the file is only read
+
+	progress := newProgressWriter(bu.progress, size)
+	defer progress.done()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, uploadURL.String(), io.TeeReader(f, progress))
+	if err != nil {
+		return nil, false, err
+	}
+	// the length is required by presigned URLs, which do not support chunked uploads
+	req.ContentLength = size
+
+	resp, err := bu.client.Do(req)
+	if err != nil {
+		// the error of the client includes the URL, which may hold credentials
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return nil, ctx.Err() == nil, fmt.Errorf("failed to upload the support bundle to %s: %w", DisplayUploadURL(uploadURL), err)
+	}
+	defer resp.Body.Close() //nolint:errcheck // the body is only read
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxUploadErrorBody))
+		retry := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests ||
+			resp.StatusCode == http.StatusRequestTimeout
+		return nil, retry, fmt.Errorf("failed to upload the support bundle to %s: %s: %s",
+			DisplayUploadURL(uploadURL), resp.Status, strings.TrimSpace(string(body)))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return resp, false, nil
+}
+
+// referenceID returns the reference ID of the upload returned by the endpoint, or the checksum of the bundle.
+func referenceID(header http.Header, sha256Sum []byte) string {
+	for _, name := range referenceIDHeaders {
+		if id := header.Get(name); id != "" {
+			return id
+		}
+	}
+	return hex.EncodeToString(sha256Sum)
+}
+
+// verifyUpload compares the checksum of the bundle received by the endpoint, if it returned one, with the bundle.
+// S3 returns the SHA-256 checksum of the object if it was requested, and its MD5 checksum as its ETag otherwise.
+func verifyUpload(header http.Header, sha256Sum, md5Sum []byte) (bool, error) {
+	if sum := header.Get("X-Amz-Checksum-Sha256"); sum != "" {
+		if sum != base64.StdEncoding.EncodeToString(sha256Sum) {
+			return false, fmt.Errorf("the SHA-256 checksum of the uploaded support bundle %s does not match the bundle", sum)
+		}
+		return true, nil
+	}
+	if etag := strings.Trim(header.Get("ETag"), `"`); md5ETag.MatchString(etag) {
+		if etag != hex.EncodeToString(md5Sum) {
+			return false, fmt.Errorf("the MD5 checksum of the uploaded support bundle %s does not match the bundle", etag)
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// progressWriter prints the progress of an upload as the bundle is read, each time its percentage changes.
+type progressWriter struct {
+	out     io.Writer
+	total   int64
+	written int64
+	percent int
+}
+
+func newProgressWriter(out io.Writer, total int64) *progressWriter {
+	return &progressWriter{out: out, total: total, percent: -1}
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	p.written += int64(len(b))
+	percent := 100
+	if p.total > 0 {
+		percent = int(p.written * 100 / p.total)
+	}
+	if percent != p.percent {
+		p.percent = percent
+		fmt.Fprintf(p.out, "\rUploading the support bundle... %3d%% (%s/%s)",
+			percent, units.BytesSize(float64(p.written)), units.BytesSize(float64(p.total)))
+	}
+	return len(b), nil
+}
+
+// done ends the line of the progress, if it was printed.
+func (p *progressWriter) done() {
+	if p.percent >= 0 {
+		fmt.Fprintln(p.out)
+	}
+}
@@ -38,6 +38,10 @@ func (s *StdLib) Env(key string) string {
 	return os.Getenv(key)
 }
 
+func (s *StdLib) Setenv(key, value string) error {
+	return os.Setenv(key, value)
+}
+
 func (s *StdLib) LookupEnv(key string) (string, bool) {
 	return os.LookupEnv(key)
 }
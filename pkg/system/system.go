@@ -47,6 +47,11 @@ type EnvGetter interface {
 	Env(key string) string
 }
 
+// EnvSetter mocks out os.Setenv.
+type EnvSetter interface {
+	Setenv(key, value string) error
+}
+
 // EnvChecker mocks out os.LookupEnv.
 type EnvChecker interface {
 	LookupEnv(key string) (string, bool)
@@ -0,0 +1,161 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package templates
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+	"unicode"
+)
+
+const tablePrefix = TableFormatKey + " "
+
+// Format is a parsed --format value, which is either a plain Go template or, when prefixed with "table",
+// a template whose output is aligned in columns below a header, as in the docker CLI.
+type Format struct {
+	tmpl   *template.Template
+	header *template.Template
+}
+
+// Parse parses the value of a --format option.
+// An empty format selects defaultFormat, "json" selects JSONFormat, and "table" alone selects defaultFormat as a table,
+// which must then be a single row, e.g. not a multiline template ranging over slices.
+// In table mode, the escape sequences \t and \n are interpreted, so that columns can be written on the command line.
+func Parse(tag, format, defaultFormat string) (*Format, error) {
+	switch format {
+	case "":
+		format = defaultFormat
+	case JSONFormatKey:
+		format = JSONFormat
+	case TableFormatKey:
+		row := strings.TrimPrefix(defaultFormat, tablePrefix)
+		if strings.TrimSpace(row) == "" || strings.Contains(strings.TrimSpace(row), "\n") {
+			return nil, fmt.Errorf(`the %s output has no default table format, use "table" followed by the template of a row`, tag)
+		}
+		format = tablePrefix + row
+	}
+
+	body, table := strings.CutPrefix(format, tablePrefix)
+	if !table {
+		tmpl, err := New(tag).Parse(format)
+		if err != nil {
+			return nil, err
+		}
+		return &Format{tmpl: tmpl}, nil
+	}
+
+	body = strings.NewReplacer(`\t`, "\t", `\n`, "\n").Replace(strings.TrimSpace(body))
+	tmpl, err := New(tag).Parse(body)
+	if err != nil {
+		return nil, err
+	}
+	header, err := template.New(tag + "-header").Funcs(headerFunctions).Parse(body)
+	if err != nil {
+		return nil, err
+	}
+	return &Format{tmpl: tmpl, header: header}, nil
+}
+
+// IsTable reports whether f renders a table.
+func (f *Format) IsTable() bool {
+	return f.header != nil
+}
+
+// Execute renders every item on its own line to w.
+// In table mode, the columns are aligned and preceded by a header derived from the fields of the items,
+// see Header. Nothing is written for a table without items.
+func (f *Format) Execute(w io.Writer, items ...any) error {
+	if !f.IsTable() {
+		for _, item := range items {
+			var b bytes.Buffer
+			if err := f.tmpl.Execute(&b, item); err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintln(w, b.String()); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if len(items) == 0 {
+		return nil
+	}
+	tw := tabwriter.NewWriter(w, 10, 1, 3, ' ', 0)
+	if err := executeLine(tw, f.header, Header(items[0])); err != nil {
+		return err
+	}
+	for _, item := range items {
+		if err := executeLine(tw, f.tmpl, item); err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
+
+func executeLine(w io.Writer, tmpl *template.Template, data any) error {
+	if err := tmpl.Execute(w, data); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(w)
+	return err
+}
+
+// Header returns the table header of v, which has the shape of v: each field of a struct is replaced by
+// its header, in upper case words (e.g. VMType is "VM TYPE"), unless overridden with a `header` struct tag.
+// Nested structs are returned as nested maps.
+func Header(v any) any {
+	return headerOf(reflect.TypeOf(v))
+}
+
+func headerOf(t reflect.Type) map[string]any {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	header := map[string]any{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		// structs without exported fields, e.g. time.Time, are rendered as a single column
+		if nested := headerOf(field.Type); len(nested) > 0 {
+			header[field.Name] = nested
+			continue
+		}
+		if h, ok := field.Tag.Lookup("header"); ok {
+			header[field.Name] = h
+			continue
+		}
+		header[field.Name] = headerName(field.Name)
+	}
+	return header
+}
+
+// headerName splits a camel case field name into upper case words, keeping acronyms together,
+// e.g. SSHPort is "SSH PORT".
+func headerName(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prevLower := unicode.IsLower(runes[i-1])
+			acronymEnd := unicode.IsUpper(runes[i-1]) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prevLower || acronymEnd {
+				b.WriteRune(' ')
+			}
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
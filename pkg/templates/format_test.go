@@ -0,0 +1,126 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package templates
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testVM struct {
+	Name    string
+	VMType  string
+	CPUs    int `header:"CPUS"`
+	SSHPort int
+	Disk    struct {
+		Size string
+	}
+}
+
+func TestFormat_Execute(t *testing.T) {
+	t.Parallel()
+
+	vm1 := testVM{Name: "finch", VMType: "vz", CPUs: 2, SSHPort: 60022}
+	vm1.Disk.Size = "50GiB"
+	vm2 := testVM{Name: "other-vm", VMType: "qemu", CPUs: 4, SSHPort: 60023}
+
+	testCases := []struct {
+		name          string
+		format        string
+		defaultFormat string
+		items         []any
+		wantTable     bool
+		want          string
+	}{
+		{
+			name:          "default format",
+			defaultFormat: "{{.Name}}",
+			items:         []any{vm1, vm2},
+			want:          "finch\nother-vm\n",
+		},
+		{
+			name:   "json",
+			format: "json",
+			items:  []any{vm1},
+			want:   `{"Name":"finch","VMType":"vz","CPUs":2,"SSHPort":60022,"Disk":{"Size":"50GiB"}}` + "\n",
+		},
+		{
+			name:      "table",
+			format:    `table {{.Name}}\t{{.VMType}}\t{{.CPUs}}\t{{.SSHPort}}\t{{.Disk.Size}}`,
+			items:     []any{vm1, &vm2},
+			wantTable: true,
+			want: "NAME       VM TYPE   CPUS      SSH PORT   SIZE\n" +
+				"finch      vz        2         60022      50GiB\n" +
+				"other-vm   qemu      4         60023      \n",
+		},
+		{
+			name:          "table with the default format",
+			format:        "table",
+			defaultFormat: "{{.Name}}\t{{upper .VMType}}",
+			items:         []any{vm1},
+			wantTable:     true,
+			want:          "NAME      VM TYPE\nfinch     VZ\n",
+		},
+		{
+			name:      "headers are not transformed by functions",
+			format:    `table {{truncate .Name 3}}\t{{pad .VMType 1 0}}`,
+			items:     []any{vm1},
+			wantTable: true,
+			want:      "NAME      VM TYPE\nfin        vz\n",
+		},
+		{
+			name:      "table without items",
+			format:    "table {{.Name}}",
+			wantTable: true,
+			want:      "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			f, err := Parse("test", tc.format, tc.defaultFormat)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantTable, f.IsTable())
+
+			var b bytes.Buffer
+			require.NoError(t, f.Execute(&b, tc.items...))
+			assert.Equal(t, tc.want, b.String())
+		})
+	}
+}
+
+func TestParse_invalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := Parse("test", "table {{.Name", "")
+	assert.Error(t, err)
+}
+
+func TestParse_tableWithoutDefaultRow(t *testing.T) {
+	t.Parallel()
+
+	for _, defaultFormat := range []string{"", "Name: {{.Name}}\n{{range .Disks}}{{.Size}}\n{{end}}"} {
+		_, err := Parse("test", "table", defaultFormat)
+		assert.EqualError(t, err, `the test output has no default table format, use "table" followed by the template of a row`)
+	}
+}
+
+func TestHeaderName(t *testing.T) {
+	t.Parallel()
+
+	for name, want := range map[string]string{
+		"Name":       "NAME",
+		"VMType":     "VM TYPE",
+		"SSHPort":    "SSH PORT",
+		"DiskSize":   "DISK SIZE",
+		"RosettaURL": "ROSETTA URL",
+	} {
+		assert.Equal(t, want, headerName(name))
+	}
+}
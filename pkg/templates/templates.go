@@ -2,20 +2,26 @@
 // SPDX-License-Identifier: Apache-2.0
 
 // Package templates provides functionality for working with templates,
-// including JSON formatting for output.
+// including JSON formatting and docker-style tables for output.
 package templates
 
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"strings"
 	"text/template"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
 )
 
 // Key and format strings in the --format option.
 const (
-	JSONFormatKey = "json"
-	JSONFormat    = "{{json .}}"
+	JSONFormatKey  = "json"
+	JSONFormat     = "{{json .}}"
+	TableFormatKey = "table"
 )
 
 var basicFunctions = template.FuncMap{
@@ -31,9 +37,52 @@ var basicFunctions = template.FuncMap{
 
 		return strings.TrimSpace(buf.String())
 	},
+	"split":    strings.Split,
+	"join":     strings.Join,
+	"title":    title,
+	"lower":    strings.ToLower,
+	"upper":    strings.ToUpper,
+	"pad":      padWithSpace,
+	"truncate": truncateWithLength,
+	"println":  fmt.Sprintln,
+}
+
+// headerFunctions are used to render the header of a table. They return the header of the field as is,
+// as applying, e.g., truncate to a header would make it unreadable.
+var headerFunctions = template.FuncMap{
+	"json":     func(v any) string { return fmt.Sprint(v) },
+	"split":    func(v any, _ string) string { return fmt.Sprint(v) },
+	"join":     func(v any, _ string) string { return fmt.Sprint(v) },
+	"title":    func(v any) string { return fmt.Sprint(v) },
+	"lower":    func(v any) string { return fmt.Sprint(v) },
+	"upper":    func(v any) string { return fmt.Sprint(v) },
+	"pad":      func(v any, _, _ int) string { return fmt.Sprint(v) },
+	"truncate": func(v any, _ int) string { return fmt.Sprint(v) },
+	"println":  fmt.Sprintln,
 }
 
 // New creates a new empty template with the provided tag and built-in template functions.
 func New(tag string) *template.Template {
 	return template.New(tag).Funcs(basicFunctions)
 }
+
+// title returns s with the first letter of each word in upper case.
+func title(s string) string {
+	return cases.Title(language.Und, cases.NoLower).String(s)
+}
+
+// padWithSpace adds spaces before and after source, unless it is empty.
+func padWithSpace(source string, prefix, suffix int) string {
+	if source == "" {
+		return source
+	}
+	return strings.Repeat(" ", prefix) + source + strings.Repeat(" ", suffix)
+}
+
+// truncateWithLength truncates source to length runes.
+func truncateWithLength(source string, length int) string {
+	if utf8.RuneCountInString(source) <= length {
+		return source
+	}
+	return string([]rune(source)[:length])
+}
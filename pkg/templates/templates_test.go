@@ -23,3 +23,37 @@ func TestNew(t *testing.T) {
 	want := "\"linux\""
 	assert.Equal(t, want, b.String())
 }
+
+func TestNew_functions(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{name: "split", template: `{{index (split . ",") 1}}`, want: "b"},
+		{name: "join", template: `{{join (split . ",") "|"}}`, want: "a|b|C"},
+		{name: "lower", template: `{{lower .}}`, want: "a,b,c"},
+		{name: "upper", template: `{{upper .}}`, want: "A,B,C"},
+		{name: "title", template: `{{title "hello world"}}`, want: "Hello World"},
+		{name: "truncate", template: `{{truncate . 3}}`, want: "a,b"},
+		{name: "truncate longer than the value", template: `{{truncate . 10}}`, want: "a,b,C"},
+		{name: "pad", template: `{{pad . 1 2}}`, want: " a,b,C  "},
+		{name: "pad empty value", template: `{{pad "" 1 2}}`, want: ""},
+		{name: "println", template: `{{println .}}`, want: "a,b,C\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			tmpl, err := New(tc.name).Parse(tc.template)
+			assert.NoError(t, err)
+
+			var b bytes.Buffer
+			assert.NoError(t, tmpl.Execute(&b, "a,b,C"))
+			assert.Equal(t, tc.want, b.String())
+		})
+	}
+}
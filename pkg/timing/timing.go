@@ -0,0 +1,148 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package timing records how long the steps of a finch command take, e.g. checking the status of the VM,
+// so that slow commands can be diagnosed with --timings.
+//
+// Recording is disabled until Enable is called, and the package level functions are no-ops until then,
+// so that the steps can be instrumented unconditionally.
+package timing
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/runfinch/finch/pkg/templates"
+)
+
+// Formats of the timings report.
+const (
+	TableFormat = "table"
+	JSONFormat  = "json"
+)
+
+const tableTemplate = "table {{.Name}}\t{{.Start}}\t{{.Duration}}"
+
+// Span is a step of a command.
+type Span struct {
+	Name string
+	// Start is the time the step started at, relative to the start of the recording.
+	Start time.Duration
+	// Duration is how long the step took, or 0 if it did not end.
+	Duration time.Duration
+}
+
+// Recorder records the spans of a command.
+type Recorder struct {
+	mu    sync.Mutex
+	clock func() time.Time
+	start time.Time
+	spans []Span
+}
+
+// NewRecorder creates a Recorder that starts recording now, as given by clock.
+func NewRecorder(clock func() time.Time) *Recorder {
+	return &Recorder{clock: clock, start: clock()}
+}
+
+// Start records the start of the span name, and returns the function recording its end.
+func (r *Recorder) Start(name string) (end func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := len(r.spans)
+	started := r.clock()
+	r.spans = append(r.spans, Span{Name: name, Start: started.Sub(r.start)})
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.spans[i].Duration = r.clock().Sub(started)
+	}
+}
+
+// Spans returns the recorded spans, in the order they started, followed by a "total" span
+// covering the whole recording.
+func (r *Recorder) Spans() []Span {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	spans := append([]Span{}, r.spans...)
+	return append(spans, Span{Name: "total", Duration: r.clock().Sub(r.start)})
+}
+
+// jsonSpan is a span in the JSON report, with the times in milliseconds so that they are easy to plot.
+type jsonSpan struct {
+	Name       string  `json:"Name"`
+	StartMs    float64 `json:"StartMs"`
+	DurationMs float64 `json:"DurationMs"`
+}
+
+// Write writes the recorded spans to w, as a table or as JSON.
+func (r *Recorder) Write(w io.Writer, format string) error {
+	spans := r.Spans()
+	switch format {
+	case JSONFormat:
+		report := make([]jsonSpan, len(spans))
+		for i, s := range spans {
+			report[i] = jsonSpan{Name: s.Name, StartMs: milliseconds(s.Start), DurationMs: milliseconds(s.Duration)}
+		}
+		return json.NewEncoder(w).Encode(report)
+	case TableFormat:
+		tmpl, err := templates.Parse("timings", tableTemplate, "")
+		if err != nil {
+			return err
+		}
+		items := make([]any, len(spans))
+		for i, s := range spans {
+			s.Start = s.Start.Round(time.Microsecond)
+			s.Duration = s.Duration.Round(time.Microsecond)
+			items[i] = s
+		}
+		return tmpl.Execute(w, items...)
+	default:
+		return fmt.Errorf("unknown timings format %q, expected %q or %q", format, TableFormat, JSONFormat)
+	}
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+type enabledRecorder struct {
+	*Recorder
+	format string
+}
+
+var current atomic.Pointer[enabledRecorder]
+
+// Enable starts recording the spans of the process, to be reported in format with Report.
+func Enable(format string) error {
+	if format != TableFormat && format != JSONFormat {
+		return fmt.Errorf("unknown timings format %q, expected %q or %q", format, TableFormat, JSONFormat)
+	}
+	current.CompareAndSwap(nil, &enabledRecorder{Recorder: NewRecorder(time.Now), format: format})
+	return nil
+}
+
+// Start records the start of the span name if recording is enabled, and returns the function recording its end.
+func Start(name string) (end func()) {
+	r := current.Load()
+	if r == nil {
+		return func() {}
+	}
+	return r.Start(name)
+}
+
+// Report writes the spans recorded since Enable to w, in the format given to Enable.
+// Nothing is written if recording is not enabled.
+func Report(w io.Writer) error {
+	r := current.Load()
+	if r == nil {
+		return nil
+	}
+	return r.Write(w, r.format)
+}
@@ -0,0 +1,100 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package timing
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock advances by step every time it is read.
+func fakeClock(step time.Duration) func() time.Time {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	return func() time.Time {
+		t := now
+		now = now.Add(step)
+		return t
+	}
+}
+
+func TestRecorder_Spans(t *testing.T) {
+	t.Parallel()
+
+	r := NewRecorder(fakeClock(time.Millisecond))
+	endOuter := r.Start("outer")
+	endInner := r.Start("inner")
+	endInner()
+	endOuter()
+	r.Start("unfinished")
+
+	assert.Equal(t, []Span{
+		{Name: "outer", Start: time.Millisecond, Duration: 3 * time.Millisecond},
+		{Name: "inner", Start: 2 * time.Millisecond, Duration: time.Millisecond},
+		{Name: "unfinished", Start: 5 * time.Millisecond},
+		{Name: "total", Duration: 6 * time.Millisecond},
+	}, r.Spans())
+}
+
+func TestRecorder_Write(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		format  string
+		want    string
+		wantErr string
+	}{
+		{
+			name:   "table",
+			format: TableFormat,
+			want: "NAME        START     DURATION\n" +
+				"vm status   1.5ms     1.5ms\n" +
+				"total       0s        4.5ms\n",
+		},
+		{
+			name:   "json",
+			format: JSONFormat,
+			want:   `[{"Name":"vm status","StartMs":1.5,"DurationMs":1.5},{"Name":"total","StartMs":0,"DurationMs":4.5}]` + "\n",
+		},
+		{
+			name:    "unknown format",
+			format:  "yaml",
+			wantErr: `unknown timings format "yaml", expected "table" or "json"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := NewRecorder(fakeClock(1500 * time.Microsecond))
+			r.Start("vm status")()
+
+			var b bytes.Buffer
+			err := r.Write(&b, tc.format)
+			if tc.wantErr != "" {
+				assert.EqualError(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, b.String())
+		})
+	}
+}
+
+func TestStart_disabled(t *testing.T) {
+	t.Parallel()
+
+	// recording is only enabled by the finch binary
+	end := Start("step")
+	end()
+
+	var b bytes.Buffer
+	require.NoError(t, Report(&b))
+	assert.Empty(t, b.String())
+}
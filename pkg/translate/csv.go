@@ -0,0 +1,65 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package translate
+
+import (
+	"fmt"
+	"strings"
+)
+
+// csvField is a key[=value] field of a comma separated flag value.
+type csvField struct {
+	key      string
+	value    string
+	hasValue bool
+}
+
+// csvFields is a comma separated flag value, e.g. the value of --mount or --output.
+// The order of the fields is kept so that it can be written back as is.
+type csvFields []csvField
+
+// parseCSVFields splits a comma separated flag value into its fields, e.g. type=bind,source=.,target=/app,readonly.
+func parseCSVFields(v string) csvFields {
+	var fields csvFields
+	for _, e := range strings.Split(v, ",") {
+		k, val, found := strings.Cut(e, "=")
+		fields = append(fields, csvField{key: strings.TrimSpace(k), value: strings.TrimSpace(val), hasValue: found})
+	}
+	return fields
+}
+
+// get returns the value of the first field named by one of keys, and its index.
+func (f csvFields) get(keys ...string) (string, int) {
+	for i, field := range f {
+		for _, k := range keys {
+			if field.key == k {
+				return field.value, i
+			}
+		}
+	}
+	return "", -1
+}
+
+// remove returns f without the fields named key.
+func (f csvFields) remove(key string) csvFields {
+	var res csvFields
+	for _, field := range f {
+		if field.key != key {
+			res = append(res, field)
+		}
+	}
+	return res
+}
+
+func (f csvFields) String() string {
+	parts := make([]string, 0, len(f))
+	for _, field := range f {
+		if field.hasValue {
+			parts = append(parts, fmt.Sprintf("%s=%s", field.key, field.value))
+		} else {
+			parts = append(parts, field.key)
+		}
+	}
+	return strings.Join(parts, ",")
+}
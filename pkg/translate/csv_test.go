@@ -0,0 +1,23 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package translate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCSVFields(t *testing.T) {
+	t.Parallel()
+
+	f := parseCSVFields("type=bind, source=./src ,target=/app,readonly,bind-propagation=rshared")
+	src, i := f.get("src", "source")
+	assert.Equal(t, "./src", src)
+	assert.Equal(t, 1, i)
+	_, i = f.get("dest")
+	assert.Equal(t, -1, i)
+	assert.Equal(t, "type=bind,source=./src,target=/app,readonly,bind-propagation=rshared", f.String())
+	assert.Equal(t, "type=bind,target=/app,readonly,bind-propagation=rshared", f.remove("source").String())
+}
@@ -0,0 +1,139 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package translate
+
+import (
+	"errors"
+	"io/fs"
+	"slices"
+	"strings"
+
+	"github.com/docker/cli/cli/config/configfile"
+)
+
+// dockerConfigFormats maps the commands listing objects to the field of the docker CLI config file
+// that holds their default --format, e.g. psFormat for docker ps.
+var dockerConfigFormats = map[string]func(cfg *configfile.ConfigFile) string{
+	"ps":              psFormat,
+	"container ls":    psFormat,
+	"container ps":    psFormat,
+	"container list":  psFormat,
+	"images":          imagesFormat,
+	"image ls":        imagesFormat,
+	"image list":      imagesFormat,
+	"stats":           statsFormat,
+	"container stats": statsFormat,
+	"network ls":      networksFormat,
+	"network list":    networksFormat,
+	"volume ls":       volumesFormat,
+	"volume list":     volumesFormat,
+}
+
+func psFormat(cfg *configfile.ConfigFile) string       { return cfg.PsFormat }
+func imagesFormat(cfg *configfile.ConfigFile) string   { return cfg.ImagesFormat }
+func statsFormat(cfg *configfile.ConfigFile) string    { return cfg.StatsFormat }
+func networksFormat(cfg *configfile.ConfigFile) string { return cfg.NetworksFormat }
+func volumesFormat(cfg *configfile.ConfigFile) string  { return cfg.VolumesFormat }
+
+// detachKeysCommands are the commands attaching to a container, which use detachKeys of the docker CLI config file
+// as their default --detach-keys.
+// exec is not one of them, unlike with the docker CLI, as nerdctl exec has no --detach-keys flag and would fail.
+var detachKeysCommands = []string{"container run", "start", "container start", "attach", "container attach"}
+
+// dockerConfigDefaults adds the defaults set in the docker CLI config file, such as psFormat or detachKeys,
+// to the arguments of the matching command when the user did not pass the flag, as the docker CLI does.
+// The command line is returned as is if dockercompat is disabled.
+func (tr *Translator) dockerConfigDefaults(cmdName string, args []string) []string {
+	if tr.fc == nil || !tr.fc.DockerCompat {
+		return args
+	}
+
+	// the defaults are inserted after the subcommand, if any, e.g. container ls
+	key, pos := cmdName, 0
+	if !strings.Contains(cmdName, " ") && len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		if sub := cmdName + " " + args[0]; dockerConfigFormats[sub] != nil || slices.Contains(detachKeysCommands, sub) {
+			key, pos = sub, 1
+		}
+	}
+
+	format, hasFormat := dockerConfigFormats[key]
+	attaches := slices.Contains(detachKeysCommands, key)
+	switch {
+	case hasFormat:
+		// the format is ignored by the docker CLI when only IDs are printed
+		if hasFlag(args, "--format") || isQuiet(args) {
+			return args
+		}
+	case attaches:
+		// a command without arguments only prints its help
+		if hasFlag(args, "--detach-keys") || len(args) == pos {
+			return args
+		}
+	default:
+		return args
+	}
+
+	cfg, err := tr.dockerConfig()
+	if err != nil {
+		tr.logger.Warnf("Ignoring the docker CLI config file: %v", err)
+		return args
+	}
+
+	var defaults []string
+	if hasFormat && format(cfg) != "" {
+		defaults = []string{"--format", format(cfg)}
+	}
+	if attaches && cfg.DetachKeys != "" {
+		defaults = []string{"--detach-keys", cfg.DetachKeys}
+	}
+	if defaults == nil {
+		return args
+	}
+	return slices.Concat(args[:pos], defaults, args[pos:])
+}
+
+// dockerConfig loads the docker CLI config file, which is in the directory set by DOCKER_CONFIG or in ~/.docker.
+// An empty config is returned if the file does not exist.
+func (tr *Translator) dockerConfig() (*configfile.ConfigFile, error) {
+	dir := tr.systemDeps.Env("DOCKER_CONFIG")
+	if dir == "" {
+		home, err := tr.systemDeps.GetUserHome()
+		if err != nil {
+			return nil, err
+		}
+		dir = tr.systemDeps.FilePathJoin(home, ".docker")
+	}
+
+	cfg := configfile.New(tr.systemDeps.FilePathJoin(dir, "config.json"))
+	file, err := tr.fs.Open(cfg.Filename)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return cfg, nil
+		}
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	if err := cfg.LoadFromReader(file); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// hasFlag reports whether flag is passed in args, either alone or with its value, e.g. --format=json.
+func hasFlag(args []string, flag string) bool {
+	return slices.ContainsFunc(args, func(arg string) bool {
+		return arg == flag || strings.HasPrefix(arg, flag+"=")
+	})
+}
+
+// isQuiet reports whether -q/--quiet is passed in args, possibly grouped with other short flags, e.g. -aq.
+func isQuiet(args []string) bool {
+	return slices.ContainsFunc(args, func(arg string) bool {
+		if arg == "--quiet" || strings.HasPrefix(arg, "--quiet=") {
+			return true
+		}
+		return len(arg) > 1 && arg[0] == '-' && arg[1] != '-' && !strings.Contains(arg, "=") && strings.Contains(arg, "q")
+	})
+}
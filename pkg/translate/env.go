@@ -0,0 +1,101 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package translate
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/runfinch/finch/pkg/dotenv"
+)
+
+func argIsEnv(arg string) bool {
+	return strings.HasPrefix(arg, "-e") || (strings.HasPrefix(arg, "--env") && !strings.HasPrefix(arg, "--env-file"))
+}
+
+func (tr *Translator) handleEnv(arg, arg2 string) (bool, string) {
+	var (
+		envVar string
+		skip   bool
+	)
+	switch arg {
+	case "-e", "--env":
+		skip = true
+		envVar = arg2
+	default:
+		// flag and value are in the same string
+		if strings.HasPrefix(arg, "-e") {
+			envVar = arg[2:]
+		} else {
+			// only other case is "--env="; skip that prefix
+			eqPos := strings.Index(arg, "=")
+			envVar = arg[eqPos+1:]
+		}
+	}
+
+	if strings.Contains(envVar, "=") {
+		return skip, envVar
+	}
+	// if no value was provided we need to check the OS environment
+	// for a value and only set if it exists in the current env
+	if val, ok := tr.systemDeps.LookupEnv(envVar); ok {
+		return skip, fmt.Sprintf("%s=%s", envVar, val)
+	}
+	// no value found; do not set the variable in the env
+	return skip, ""
+}
+
+func (tr *Translator) handleEnvFile(arg, arg2 string) (bool, []string, error) {
+	var (
+		filename string
+		skip     bool
+	)
+
+	switch arg {
+	case "--env-file":
+		skip = true
+		filename = arg2
+	default:
+		filename = arg[11:]
+	}
+
+	envs, err := tr.parseEnvFile(filename, dotenv.Docker)
+	if err != nil {
+		return false, []string{}, err
+	}
+	return skip, envs, nil
+}
+
+// parseEnvFile reads the env file at filename using the given dialect.
+// Variables listed without a value are resolved from the host environment.
+func (tr *Translator) parseEnvFile(filename string, mode dotenv.Mode) ([]string, error) {
+	file, err := tr.fs.Open(filepath.Clean(filename))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close() //nolint:errcheck // We did not write to the file, and the file will be closed when the CLI process exits anyway.
+
+	envs, err := dotenv.Parse(file, mode, tr.systemDeps.LookupEnv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse env file %q: %w", filename, err)
+	}
+	return envs, nil
+}
+
+// parseComposeEnvFile reads the env file passed to `compose --env-file`.
+// As with docker compose, variables set in the host environment take precedence over the file.
+func (tr *Translator) parseComposeEnvFile(filename string) ([]string, error) {
+	envs, err := tr.parseEnvFile(filename, dotenv.Compose)
+	if err != nil {
+		return nil, err
+	}
+	for i, e := range envs {
+		k, _, _ := strings.Cut(e, "=")
+		if v, ok := tr.systemDeps.LookupEnv(k); ok {
+			envs[i] = fmt.Sprintf("%s=%s", k, v)
+		}
+	}
+	return envs, nil
+}
@@ -0,0 +1,130 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package translate
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+func (tr *Translator) handleMultipleShortFlags(
+	shortFlagBoolSet sets.Set[string],
+	shortFlagArgSet sets.Set[string],
+	args []string,
+	index int,
+) string {
+	arg := args[index]
+	nextArg := args[index+1]
+
+	argFlagFinal := false
+	argFlagMid := false
+	lastPos := len(arg) - 1
+
+	// Scan the compound shortFlag arg for details
+	for i, c := range strings.Split(arg, "") {
+		switch {
+		case c == "-":
+			continue
+		case i != lastPos && shortFlagArgSet.Has("-"+c):
+			argFlagMid = true
+		case i == lastPos && shortFlagArgSet.Has("-"+c):
+			argFlagFinal = true
+		default:
+			if !shortFlagBoolSet.Has("-" + c) {
+				tr.logger.Debugf("The group of short flags contains an unexpected value: %s, %s", arg, c)
+			}
+		}
+	}
+
+	switch {
+	case argFlagMid:
+		// if a flag in the middle requires arguments,
+		//    but there are more short flags immediately following
+		//    then include a finch debug comment
+		//    and pass the concatenated arg to nerdctl
+		tr.logger.Debugln("Mid Position Short Flag requires an Arg: ", arg)
+	case argFlagFinal:
+		// pre-pend the shortFlagArg member to the next Arg
+		separatedShortFlag := "-" + arg[lastPos:]
+		args[index+1] = separatedShortFlag + nextArg
+		// remove the shortFlagArg member from the current Arg
+		args[index] = arg[:lastPos]
+		arg = args[index]
+	default:
+		// if every following character is a shortFlagBool
+		//    then pass into nerdctlArgs
+	}
+
+	return arg
+}
+
+func handleFlagArg(arg string, nextArg string) (bool, string, string) {
+	// handling Flag arguments other than environment variables
+	//    note: a Bool Flag should not be passed into this helper function; only Flags that are followed by one argument
+	var (
+		flagKey, flagVal string
+		skip             bool
+	)
+	switch {
+	case strings.HasPrefix(arg, "--") && strings.Contains(arg, "="):
+		// long flag concatenated to value by '=': --<long_flag>="<value>"
+		skip = false
+		flagKey, flagVal, _ = strings.Cut(arg, "=")
+	case strings.HasPrefix(arg, "--") && isNumericArg(nextArg),
+		strings.HasPrefix(arg, "--") && !strings.HasPrefix(nextArg, "-"):
+		// long flag followed by a value (including a negative number): --<long_flag> "<value>".
+		// the isNumeric check is needed because the value can be a negative number.
+		// for example, in our health check tests where we pass --health-retries -5 or --health-timeout -5s
+		skip = true
+		flagKey = arg
+		flagVal = nextArg
+	// undefined case: long flag adjacent to value: --<long_flag>"<value>" or --<long_flag><value>
+	case strings.HasPrefix(arg, "-") && strings.Contains(arg, "="):
+		// short flag concatenated to value by '=': -?="<value>" or -?=<value>
+		skip = false
+		flagKey, flagVal, _ = strings.Cut(arg, "=")
+	case strings.HasPrefix(arg, "-") && len(arg) > 2:
+		// short flag adjacent to value: -?"<value>" or -?<value>
+		skip = false
+		flagKey = arg[:2]
+		flagVal = arg[2:]
+	case strings.HasPrefix(arg, "-") && len(arg) == 2 && isNumericArg(nextArg),
+		strings.HasPrefix(arg, "-") && len(arg) == 2 && !strings.HasPrefix(nextArg, "-"):
+		// short flag followed by a value (including a negative number): -? "<value>" or -? <value>
+		skip = true
+		flagKey = arg
+		flagVal = nextArg
+	default:
+		return false, "", ""
+	}
+
+	return skip, flagKey, flagVal
+}
+
+// isNumericArg returns whether the passed argument is a numeric argument or not.
+// For example, it returns true for cases like 5, -5, 5s and -5s.
+func isNumericArg(arg string) bool {
+	if arg == "" {
+		return false
+	}
+	// handle the case where the arg is a negative number followed by a char
+	// for example: --health-timeout -5s
+	if arg[0] == '-' && len(arg) > 1 {
+		for i := 1; i < len(arg); i++ {
+			if arg[i] < '0' || arg[i] > '9' {
+				return i > 1
+			}
+		}
+		return true
+	}
+	// handle the case where the arg is a positive number followed by a char
+	// for example: --health-timeout -5s
+	for i := 0; i < len(arg); i++ {
+		if arg[i] < '0' || arg[i] > '9' {
+			return i > 0
+		}
+	}
+	return true
+}
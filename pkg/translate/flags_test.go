@@ -0,0 +1,177 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package translate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleFlagArg(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		inputArgs    []string
+		expectedArgs []string
+	}{
+		{
+			inputArgs:    []string{"--flag", "value"},
+			expectedArgs: []string{"--flag", "value"},
+		},
+		{
+			inputArgs:    []string{"--flag=value", ""},
+			expectedArgs: []string{"--flag", "value"},
+		},
+		{
+			inputArgs:    []string{"--flag=\"value\"", ""},
+			expectedArgs: []string{"--flag", "\"value\""},
+		},
+		{
+			inputArgs:    []string{"--flag", "10"},
+			expectedArgs: []string{"--flag", "10"},
+		},
+		{
+			inputArgs:    []string{"--flag", "-10"},
+			expectedArgs: []string{"--flag", "-10"},
+		},
+		{
+			inputArgs:    []string{"--flag", "10s"},
+			expectedArgs: []string{"--flag", "10s"},
+		},
+		{
+			inputArgs:    []string{"--flag", "-10s"},
+			expectedArgs: []string{"--flag", "-10s"},
+		},
+		{
+			inputArgs:    []string{"--flag=10", ""},
+			expectedArgs: []string{"--flag", "10"},
+		},
+		{
+			inputArgs:    []string{"--flag=10s", ""},
+			expectedArgs: []string{"--flag", "10s"},
+		},
+		{
+			inputArgs:    []string{"--flag=-10", ""},
+			expectedArgs: []string{"--flag", "-10"},
+		},
+		{
+			inputArgs:    []string{"--flag=-10s", ""},
+			expectedArgs: []string{"--flag", "-10s"},
+		},
+		{
+			inputArgs:    []string{"--flag=\"10\"", ""},
+			expectedArgs: []string{"--flag", "\"10\""},
+		},
+		{
+			inputArgs:    []string{"--flag=\"-10\"", ""},
+			expectedArgs: []string{"--flag", "\"-10\""},
+		},
+		{
+			inputArgs:    []string{"--flag=\"10s\"", ""},
+			expectedArgs: []string{"--flag", "\"10s\""},
+		},
+		{
+			inputArgs:    []string{"--flag=\"-10s\"", ""},
+			expectedArgs: []string{"--flag", "\"-10s\""},
+		},
+		{
+			inputArgs:    []string{"-f", "value"},
+			expectedArgs: []string{"-f", "value"},
+		},
+		{
+			inputArgs:    []string{"-f=value", ""},
+			expectedArgs: []string{"-f", "value"},
+		},
+		{
+			inputArgs:    []string{"-f=\"value\"", ""},
+			expectedArgs: []string{"-f", "\"value\""},
+		},
+		{
+			inputArgs:    []string{"-f", "10s"},
+			expectedArgs: []string{"-f", "10s"},
+		},
+		{
+			inputArgs:    []string{"-f", "-10s"},
+			expectedArgs: []string{"-f", "-10s"},
+		},
+		{
+			inputArgs:    []string{"-f=10", ""},
+			expectedArgs: []string{"-f", "10"},
+		},
+		{
+			inputArgs:    []string{"-f=10s", ""},
+			expectedArgs: []string{"-f", "10s"},
+		},
+		{
+			inputArgs:    []string{"-f=-10", ""},
+			expectedArgs: []string{"-f", "-10"},
+		},
+		{
+			inputArgs:    []string{"-f=-10s", ""},
+			expectedArgs: []string{"-f", "-10s"},
+		},
+		{
+			inputArgs:    []string{"-f=\"10\"", ""},
+			expectedArgs: []string{"-f", "\"10\""},
+		},
+		{
+			inputArgs:    []string{"-f=\"10s\"", ""},
+			expectedArgs: []string{"-f", "\"10s\""},
+		},
+		{
+			inputArgs:    []string{"-f=\"-10\"", ""},
+			expectedArgs: []string{"-f", "\"-10\""},
+		},
+		{
+			inputArgs:    []string{"-f=\"-10s\"", ""},
+			expectedArgs: []string{"-f", "\"-10s\""},
+		},
+		{
+			inputArgs:    []string{"-f10", ""},
+			expectedArgs: []string{"-f", "10"},
+		},
+	}
+	for _, tc := range testCases {
+		// the first return value is never used
+		_, flagKey, flagVal := handleFlagArg(tc.inputArgs[0], tc.inputArgs[1])
+		assert.Equal(t, flagKey, tc.expectedArgs[0])
+		assert.Equal(t, flagVal, tc.expectedArgs[1])
+	}
+}
+
+func TestIsNumericArg(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		arg      string
+		expected bool
+	}{
+		{
+			arg:      "5",
+			expected: true,
+		},
+		{
+			arg:      "5s",
+			expected: true,
+		},
+		{
+			arg:      "-5",
+			expected: true,
+		},
+		{
+			arg:      "-5s",
+			expected: true,
+		},
+		{
+			arg:      "abc",
+			expected: false,
+		},
+		{
+			arg:      "-abc",
+			expected: false,
+		},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, isNumericArg(tc.arg))
+	}
+}
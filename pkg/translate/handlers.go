@@ -0,0 +1,320 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package translate
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+type (
+	argHandler     func(tr *Translator, args []string, index int) error
+	commandHandler func(tr *Translator, cmdName *string, args *[]string, inspectType *string) error
+)
+
+var aliasMap = map[string]string{
+	"build": "image build",
+	"run":   "container run",
+	"cp":    "container cp",
+	"save":  "image save",
+	"load":  "image load",
+}
+
+var commandHandlerMap = map[string]commandHandler{
+	"buildx":       handleBuildx,
+	"inspect":      handleDockerCompatInspect,
+	"container cp": cpHandler,
+	"image build":  imageBuildHandler,
+}
+
+// containerArgHandlers are the arg handlers of the commands creating containers, i.e. run and create,
+// which resolve the sources of their bind mounts.
+var containerArgHandlers = map[string]argHandler{
+	"--mount":  handleBindMounts,
+	"-v":       handleVolume,
+	"--volume": handleVolume,
+}
+
+var argHandlerMap = map[string]map[string]argHandler{
+	"image build": {
+		"--load": handleDockerBuildLoad,
+	},
+	// the maps are cloned, as init adds the path flags of each command to them
+	"container run":    maps.Clone(containerArgHandlers),
+	"create":           maps.Clone(containerArgHandlers),
+	"container create": maps.Clone(containerArgHandlers),
+}
+
+func init() {
+	// the flags taking host paths are declared in pathFlags
+	for cmdName, flags := range pathFlags {
+		if argHandlerMap[cmdName] == nil {
+			argHandlerMap[cmdName] = map[string]argHandler{}
+		}
+		for flag, h := range flags {
+			argHandlerMap[cmdName][flag] = h
+		}
+	}
+}
+
+var cmdFlagSetMap = map[string]map[string]sets.Set[string]{
+	"container run": {
+		"shortBoolFlags": sets.New[string]("-d", "-i", "-t"),
+		"longBoolFlags": sets.New[string](
+			"--detach", "--init", "--interactive", "--oom-kill-disable",
+			"--privileged", "--read-only", "--rm", "--rootfs", "--tty", "--sig-proxy"),
+		"shortArgFlags": sets.New[string]("-e", "-h", "-m", "-u", "-w", "-p", "-l", "-v"),
+	},
+	"create": {
+		"shortBoolFlags": sets.New[string]("-i", "-t"),
+		"longBoolFlags": sets.New[string](
+			"--init", "--interactive", "--oom-kill-disable",
+			"--privileged", "--read-only", "--rm", "--rootfs", "--tty"),
+		"shortArgFlags": sets.New[string]("-e", "-h", "-m", "-u", "-w", "-p", "-l", "-v"),
+	},
+	"exec": {
+		"shortBoolFlags": sets.New[string]("-d", "-i", "-t"),
+		"longBoolFlags": sets.New[string](
+			"--detach", "--init", "--interactive", "--oom-kill-disable",
+			"--privileged", "--read-only", "--rm", "--rootfs", "--tty"),
+		"shortArgFlags": sets.New[string]("-e", "-h", "-m", "-u", "-w", "-p", "-l", "-v"),
+	},
+	"compose": {
+		"shortBoolFlags": sets.New[string]("-d", "-i", "-t"),
+		"longBoolFlags": sets.New[string](
+			"--detach", "--init", "--interactive", "--oom-kill-disable",
+			"--privileged", "--read-only", "--rm", "--rootfs", "--tty"),
+		"shortArgFlags": sets.New[string]("-e", "-f", "-h", "-m", "-u", "-w", "-p", "-l", "-v"),
+	},
+}
+
+// converts "docker build --load" flag to "nerdctl build --output=type=docker".
+func handleDockerBuildLoad(tr *Translator, nerdctlCmdArgs []string, index int) error {
+	if tr.fc != nil && tr.fc.DockerCompat {
+		nerdctlCmdArgs[index] = "--output=type=docker"
+	}
+
+	return nil
+}
+
+func handleBuildx(tr *Translator, cmdName *string, args *[]string, _ *string) error {
+	if tr.fc == nil || !tr.fc.DockerCompat {
+		return nil
+	}
+
+	if cmdName != nil && *cmdName == "buildx" {
+		subCmd := (*args)[0]
+		buildxSubcommands := []string{"bake", "create", "debug", "du", "imagetools", "inspect", "ls", "prune", "rm", "stop", "use", "version"}
+
+		if slices.Contains(buildxSubcommands, subCmd) {
+			return fmt.Errorf("unsupported buildx command: %s", subCmd)
+		}
+
+		logrus.Warn("buildx is not supported. using standard buildkit instead...")
+		if subCmd == "build" {
+			*args = (*args)[1:]
+		}
+		*cmdName = "build"
+	}
+	// else, continue with the original command
+	return nil
+}
+
+func handleDockerCompatInspect(tr *Translator, cmdName *string, args *[]string, inspectType *string) error {
+	if tr.fc == nil || !tr.fc.DockerCompat {
+		return nil
+	}
+
+	if *args == nil {
+		return fmt.Errorf("invalid arguments: args (null pointer)")
+	}
+
+	modeDockerCompat := `--mode=dockercompat`
+	sizeArg := ""
+	savedArgs := []string{}
+	skip := false
+	*inspectType = ""
+
+	for idx, arg := range *args {
+		if skip {
+			skip = false
+			continue
+		}
+
+		if (arg == "--type") && (idx < len(*args)-1) {
+			*inspectType = (*args)[idx+1]
+			skip = true
+			continue
+		}
+
+		if strings.Contains(arg, "--type") && strings.Contains(arg, "=") {
+			*inspectType = strings.Split(arg, "=")[1]
+			continue
+		}
+
+		if (arg == "--size") || (arg == "-s") {
+			sizeArg = "--size"
+			continue
+		}
+
+		savedArgs = append(savedArgs, arg)
+	}
+
+	switch *inspectType {
+	case "image":
+		*cmdName = "image inspect"
+		*args = append([]string{modeDockerCompat}, savedArgs...)
+	case "volume":
+		*cmdName = "volume inspect"
+		if sizeArg != "" {
+			*args = append([]string{sizeArg}, savedArgs...)
+		} else {
+			*args = append([]string{}, savedArgs...)
+		}
+	case "container":
+		*cmdName = "inspect"
+		*args = append([]string{modeDockerCompat}, savedArgs...)
+	case "":
+		*cmdName = "inspect"
+		*args = append([]string{modeDockerCompat}, savedArgs...)
+		*inspectType = "container"
+	default:
+		return fmt.Errorf("unsupported inspect type: %s", *inspectType)
+	}
+
+	return nil
+}
+
+// handles the argument & value of --mount option
+//
+//	resolves and converts the source path of the bind mount
+//	and removes the consistency key-value entity from value
+func handleBindMounts(tr *Translator, nerdctlCmdArgs []string, index int) error {
+	before, v, concatenated, err := flagValue(nerdctlCmdArgs, index)
+	if err != nil {
+		return err
+	}
+
+	// eg --mount type=bind,source="$(pwd)"/target,target=/app,readonly
+	// eg --mount type=bind, source=${pwd}/source_dir, target=<path>/target_dir, consistency=cached
+	m := parseCSVFields(v)
+	// Check if type is bind mount, else return
+	if t, _ := m.get("type"); t != "bind" {
+		return nil
+	}
+
+	// Remove 'consistency' key-value pair, if present
+	m = m.remove("consistency")
+
+	// If there is no src or source, do nothing, let nerdctl handle error
+	if source, i := m.get("src", "source"); i >= 0 {
+		resolved, err := tr.resolveHostPath(source)
+		if err != nil {
+			return err
+		}
+		m[i].value = resolved
+	}
+
+	setFlagValue(nerdctlCmdArgs, index, before, m.String(), concatenated)
+	return nil
+}
+
+// flagValue returns the value of the flag at index, which is either concatenated to the flag by '=' or the next argument.
+func flagValue(nerdctlCmdArgs []string, index int) (before, value string, concatenated bool, err error) {
+	prefix := nerdctlCmdArgs[index]
+	if strings.Contains(prefix, "=") {
+		before, value, _ = strings.Cut(prefix, "=")
+		return before, value, true, nil
+	}
+	if (index + 1) < len(nerdctlCmdArgs) {
+		return prefix, nerdctlCmdArgs[index+1], false, nil
+	}
+	return "", "", false, fmt.Errorf("invalid positional parameter for %s", prefix)
+}
+
+// setFlagValue sets the value of the flag at index, keeping the form the value was passed in.
+func setFlagValue(nerdctlCmdArgs []string, index int, before, value string, concatenated bool) {
+	if concatenated {
+		nerdctlCmdArgs[index] = fmt.Sprintf("%s=%s", before, value)
+	} else {
+		nerdctlCmdArgs[index+1] = value
+	}
+}
+
+// handles -v/--volumes option. For anonymous volumes and named volumes this is no-op.
+// For bind mounts the host path is resolved and converted, keeping the options as is.
+func handleVolume(tr *Translator, nerdctlCmdArgs []string, index int) error {
+	before, v, concatenated, err := flagValue(nerdctlCmdArgs, index)
+	if err != nil {
+		return err
+	}
+
+	spec := parseVolumeSpec(v)
+	// This is a named volume, or an anonymous volume from https://github.com/containerd/nerdctl/blob/main/pkg/mountutil/mountutil.go#L76
+	if spec.source == "" || !isHostPath(spec.source) {
+		return nil
+	}
+
+	spec.source, err = tr.resolveHostPath(spec.source)
+	if err != nil {
+		return err
+	}
+	setFlagValue(nerdctlCmdArgs, index, before, spec.String(), concatenated)
+	return nil
+}
+
+// cp command handler, takes command arguments and converts host paths in place. It ignores all other arguments.
+func cpHandler(tr *Translator, _ *string, nerdctlCmdArgs *[]string, _ *string) error {
+	for i, arg := range *nerdctlCmdArgs {
+		// -L and --follow-symlink don't have to be processed
+		if strings.HasPrefix(arg, "-") || arg == "cp" {
+			continue
+		}
+		// If argument contains container path, then continue
+		colon := strings.Index(arg, ":")
+
+		// this is a container path
+		if colon > 1 {
+			continue
+		}
+		convertedPath, err := tr.convertPath(arg)
+		if err != nil {
+			return err
+		}
+		(*nerdctlCmdArgs)[i] = convertedPath
+	}
+	return nil
+}
+
+// this is the handler for image build command. It converts the build context path.
+func imageBuildHandler(tr *Translator, _ *string, nerdctlCmdArgs *[]string, _ *string) error {
+	var err error
+	argLen := len(*nerdctlCmdArgs) - 1
+	if argLen < 0 {
+		return nil
+	}
+	// -h/--help don't have buildcontext, just return
+	for _, a := range *nerdctlCmdArgs {
+		if a == "--help" || a == "-h" {
+			return nil
+		}
+	}
+	if (*nerdctlCmdArgs)[argLen] != "--debug" {
+		(*nerdctlCmdArgs)[argLen], err = tr.convertPath((*nerdctlCmdArgs)[argLen])
+		if err != nil {
+			return err
+		}
+	} else if argLen > 0 {
+		(*nerdctlCmdArgs)[argLen-1], err = tr.convertPath((*nerdctlCmdArgs)[argLen-1])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
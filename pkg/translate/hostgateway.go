@@ -0,0 +1,283 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package translate
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	dockerops "github.com/docker/docker/opts"
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v3"
+)
+
+// HostAliases are the host names that resolve to the host gateway in every container when host_aliases is enabled.
+var HostAliases = []string{"host.finch.internal", "host.docker.internal"}
+
+// defaultComposeFiles are the files compose looks for in the working directory when no file is specified,
+// in order of precedence.
+var defaultComposeFiles = []string{"compose.yaml", "compose.yml", "docker-compose.yml", "docker-compose.yaml"}
+
+// defaultComposeOverrideFiles are the override files compose merges on top of a default file.
+var defaultComposeOverrideFiles = []string{
+	"compose.override.yaml", "compose.override.yml", "docker-compose.override.yml", "docker-compose.override.yaml",
+}
+
+// composeCreateCommands are the compose subcommands that create containers.
+var composeCreateCommands = []string{"up", "run", "create"}
+
+// hostGatewayIP returns the address that "host-gateway" resolves to.
+// host_gateway_ip in finch.yaml takes precedence over the platform default from the HostGatewayIP hook.
+// "host-gateway" itself is returned when neither is available, leaving the resolution to nerdctl.
+func (tr *Translator) hostGatewayIP() (string, error) {
+	if tr.gatewayIP != "" {
+		return tr.gatewayIP, nil
+	}
+
+	switch {
+	case tr.fc != nil && tr.fc.HostGatewayIP != "":
+		tr.gatewayIP = tr.fc.HostGatewayIP
+	case tr.hooks.HostGatewayIP != nil:
+		ip, err := tr.hooks.HostGatewayIP()
+		if err != nil {
+			return "", err
+		}
+		tr.gatewayIP = ip
+	default:
+		tr.gatewayIP = dockerops.HostGatewayName
+	}
+	return tr.gatewayIP, nil
+}
+
+// resolveIP replaces the special "host-gateway" address of an --add-host value, in the form of host:ip,
+// with the IP address that can be used to access the host from the containers.
+func (tr *Translator) resolveIP(host string) (string, error) {
+	name, ip, found := strings.Cut(host, ":")
+	if !found || ip != dockerops.HostGatewayName {
+		return host, nil
+	}
+
+	resolvedIP, err := tr.hostGatewayIP()
+	if err != nil {
+		return "", err
+	}
+	if resolvedIP == dockerops.HostGatewayName {
+		return host, nil
+	}
+	tr.logger.Debugf(`Resolving special IP "host-gateway" to %q for host %q`, resolvedIP, name)
+	return fmt.Sprintf("%s:%s", name, resolvedIP), nil
+}
+
+// hostAliasArgs returns the --add-host flags for the host aliases that the user did not set already.
+func (tr *Translator) hostAliasArgs(userHosts []string) ([]string, error) {
+	ip, err := tr.hostGatewayIP()
+	if err != nil {
+		return nil, err
+	}
+
+	var args []string
+	for _, alias := range HostAliases {
+		if !slices.Contains(userHosts, alias) {
+			args = append(args, "--add-host", fmt.Sprintf("%s:%s", alias, ip))
+		}
+	}
+	return args, nil
+}
+
+// composeHostAliasArgs returns the --file flags that add the host aliases to every service of the compose project.
+//
+// compose has no flag to add hosts to all services, so an override file listing every service is generated.
+// Passing a file disables the lookup of the default files, so those are passed explicitly too.
+func (tr *Translator) composeHostAliasArgs(args []string) ([]string, error) {
+	files, defaulted, err := tr.composeFiles(args)
+	if err != nil {
+		return nil, err
+	}
+
+	var services []string
+	for _, f := range files {
+		names, err := tr.composeServices(f)
+		if err != nil {
+			return nil, err
+		}
+		services = append(services, names...)
+	}
+	if len(services) == 0 {
+		return nil, nil
+	}
+
+	override, err := tr.writeComposeOverride(services)
+	if err != nil {
+		return nil, err
+	}
+
+	var fileArgs []string
+	if defaulted {
+		for _, f := range files {
+			converted, err := tr.convertPath(f)
+			if err != nil {
+				return nil, err
+			}
+			fileArgs = append(fileArgs, "--file", converted)
+		}
+	}
+	converted, err := tr.convertPath(override)
+	if err != nil {
+		return nil, err
+	}
+	return append(fileArgs, "--file", converted), nil
+}
+
+// composeFiles returns the compose files of the project and whether they were found without being specified
+// with --file, in which case they need to be passed to compose along with the override.
+func (tr *Translator) composeFiles(args []string) ([]string, bool, error) {
+	var files []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") {
+			// the compose subcommand; anything after it is not a compose global flag
+			break
+		}
+		switch {
+		case arg == "-f" || arg == "--file":
+			if i+1 < len(args) {
+				files = append(files, args[i+1])
+				i++
+			}
+		case strings.HasPrefix(arg, "--file="):
+			files = append(files, strings.TrimPrefix(arg, "--file="))
+		case strings.HasPrefix(arg, "-f"):
+			files = append(files, strings.TrimPrefix(strings.TrimPrefix(arg, "-f"), "="))
+		case slices.Contains(composeGlobalValueFlags, arg):
+			i++
+		}
+	}
+	if len(files) > 0 {
+		return files, false, nil
+	}
+
+	wd, err := tr.systemDeps.GetWd()
+	if err != nil {
+		return nil, false, err
+	}
+
+	if env := tr.systemDeps.Env("COMPOSE_FILE"); env != "" {
+		sep := tr.systemDeps.Env("COMPOSE_PATH_SEPARATOR")
+		if sep == "" {
+			sep = string(os.PathListSeparator)
+		}
+		for _, f := range strings.Split(env, sep) {
+			if !filepath.IsAbs(f) {
+				f = tr.systemDeps.FilePathJoin(wd, f)
+			}
+			files = append(files, f)
+		}
+		return files, true, nil
+	}
+
+	main := tr.firstExistingFile(wd, defaultComposeFiles)
+	if main == "" {
+		// compose fails with a clear error when there is no project file
+		return nil, false, nil
+	}
+	files = append(files, main)
+	if override := tr.firstExistingFile(wd, defaultComposeOverrideFiles); override != "" {
+		files = append(files, override)
+	}
+	return files, true, nil
+}
+
+// firstExistingFile returns the path of the first of names that exists in dir, or "" if none does.
+func (tr *Translator) firstExistingFile(dir string, names []string) string {
+	for _, name := range names {
+		f := tr.systemDeps.FilePathJoin(dir, name)
+		if _, err := tr.fs.Stat(f); err == nil {
+			return f
+		}
+	}
+	return ""
+}
+
+// composeService is the part of a compose service definition that the translation needs.
+type composeService struct {
+	// Volumes are kept as nodes as they can use either the short or the long syntax.
+	Volumes []yaml.Node `yaml:"volumes"`
+}
+
+// composeProject parses the services of the compose file at path.
+// A missing file yields no services, as compose reports missing files itself.
+func (tr *Translator) composeProject(path string) (map[string]composeService, error) {
+	b, err := afero.ReadFile(tr.fs, path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var project struct {
+		Services map[string]composeService `yaml:"services"`
+	}
+	if err := yaml.Unmarshal(b, &project); err != nil {
+		return nil, fmt.Errorf("failed to parse compose file %q: %w", path, err)
+	}
+	return project.Services, nil
+}
+
+// composeServices returns the names of the services defined in the compose file at path.
+func (tr *Translator) composeServices(path string) ([]string, error) {
+	services, err := tr.composeProject(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for name := range services {
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// writeComposeOverride writes the compose override file adding the host aliases to services.
+// The file is named after its content so that it is reused by later invocations on the same project.
+func (tr *Translator) writeComposeOverride(services []string) (string, error) {
+	ip, err := tr.hostGatewayIP()
+	if err != nil {
+		return "", err
+	}
+
+	var extraHosts []string
+	for _, alias := range HostAliases {
+		extraHosts = append(extraHosts, fmt.Sprintf("%s:%s", alias, ip))
+	}
+	override := map[string]map[string]map[string][]string{"services": {}}
+	for _, name := range services {
+		override["services"][name] = map[string][]string{"extra_hosts": extraHosts}
+	}
+	b, err := yaml.Marshal(override)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal compose override: %w", err)
+	}
+
+	home, err := tr.systemDeps.GetUserHome()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	dir := tr.systemDeps.FilePathJoin(home, ".finch", "compose")
+	path := tr.systemDeps.FilePathJoin(dir, fmt.Sprintf("host-aliases-%s.yaml", hex.EncodeToString(sum[:6])))
+	if err := tr.fs.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("failed to create %q: %w", dir, err)
+	}
+	if err := afero.WriteFile(tr.fs, path, b, 0o600); err != nil {
+		return "", fmt.Errorf("failed to write compose override %q: %w", path, err)
+	}
+	return path, nil
+}
@@ -0,0 +1,116 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package translate
+
+import (
+	"slices"
+	"strings"
+)
+
+// pathConverter converts the host paths found in a flag value.
+type pathConverter func(tr *Translator, value string) (string, error)
+
+// containerPathFlags are the flags taking a host path of the commands creating containers, i.e. run and create.
+var containerPathFlags = map[string]argHandler{
+	"--label-file": pathFlag(hostPath),
+	"--cosign-key": pathFlag(hostPath),
+	"--cidfile":    pathFlag(hostPath),
+}
+
+// pathFlags declares, for each command, the flags whose value contains a host path.
+// The paths are converted with the ConvertPath hook, so that they are valid where nerdctl runs.
+var pathFlags = map[string]map[string]argHandler{
+	"image build": {
+		"-f":        pathFlag(hostPath),
+		"--file":    pathFlag(hostPath),
+		"--iidfile": pathFlag(hostPath),
+		"-o":        pathFlag(csvHostPath("dest")),
+		"--output":  pathFlag(csvHostPath("dest")),
+		"--secret":  pathFlag(csvHostPath("src")),
+	},
+	"image save": {
+		"-o":       pathFlag(hostPath),
+		"--output": pathFlag(hostPath),
+	},
+	"image load": {
+		"-i":      pathFlag(hostPath),
+		"--input": pathFlag(hostPath),
+	},
+	"container run":    containerPathFlags,
+	"create":           containerPathFlags,
+	"container create": containerPathFlags,
+	"compose": {
+		"-f":     composeGlobalFlag(pathFlag(hostPath)),
+		"--file": composeGlobalFlag(pathFlag(hostPath)),
+	},
+}
+
+// hostPath converts a flag value that is a host path, e.g. --iidfile <path>.
+func hostPath(tr *Translator, value string) (string, error) {
+	return tr.convertPath(value)
+}
+
+// csvHostPath converts a flag value made of comma separated key=value pairs, where key holds a host path,
+// e.g. --output type=local,dest=<path>.
+func csvHostPath(key string) pathConverter {
+	return func(tr *Translator, value string) (string, error) {
+		fields := parseCSVFields(value)
+		path, i := fields.get(key)
+		if i < 0 {
+			return value, nil
+		}
+		converted, err := tr.convertPath(path)
+		if err != nil {
+			return "", err
+		}
+		if converted == path {
+			return value, nil
+		}
+		fields[i].value = converted
+		return fields.String(), nil
+	}
+}
+
+// pathFlag returns the handler of a flag whose value is converted with convert.
+func pathFlag(convert pathConverter) argHandler {
+	return func(tr *Translator, nerdctlCmdArgs []string, index int) error {
+		before, value, concatenated, err := flagValue(nerdctlCmdArgs, index)
+		if err != nil {
+			return err
+		}
+		converted, err := convert(tr, value)
+		if err != nil {
+			return err
+		}
+		setFlagValue(nerdctlCmdArgs, index, before, converted, concatenated)
+		return nil
+	}
+}
+
+// composeGlobalFlag restricts h to the compose flags preceding the subcommand,
+// as subcommands reuse their names, e.g. -f for compose logs --follow.
+func composeGlobalFlag(h argHandler) argHandler {
+	return func(tr *Translator, nerdctlCmdArgs []string, index int) error {
+		if index > composeSubcommandIndex(nerdctlCmdArgs) {
+			return nil
+		}
+		return h(tr, nerdctlCmdArgs, index)
+	}
+}
+
+// composeGlobalValueFlags are the compose flags preceding the subcommand that take a value.
+var composeGlobalValueFlags = []string{"-f", "--file", "-p", "--project-name", "--project-directory", "--profile", "--env-file"}
+
+// composeSubcommandIndex returns the index of the compose subcommand in args, or len(args) if there is none.
+func composeSubcommandIndex(args []string) int {
+	for i := 0; i < len(args); i++ {
+		if !strings.HasPrefix(args[i], "-") {
+			return i
+		}
+		if slices.Contains(composeGlobalValueFlags, args[i]) {
+			i++
+		}
+	}
+	return len(args)
+}
@@ -0,0 +1,441 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package translate converts a docker-style command line typed by the user into the nerdctl command line
+// that finch runs.
+//
+// The translation is the same on every platform. The parts that differ, converting host paths and resolving
+// the host gateway, are provided by the caller through Hooks, while running the resulting command is left to the caller.
+package translate
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/afero"
+	orderedmap "github.com/wk8/go-ordered-map"
+
+	"github.com/runfinch/finch/pkg/compat"
+	"github.com/runfinch/finch/pkg/config"
+	"github.com/runfinch/finch/pkg/flog"
+	"github.com/runfinch/finch/pkg/system"
+)
+
+// SystemDeps contains the system dependencies for Translator.
+//
+//go:generate mockgen -copyright_file=../../copyright_header -destination=../mocks/pkg_translate_system_deps.go -package=mocks -mock_names SystemDeps=TranslateSystemDeps . SystemDeps
+type SystemDeps interface {
+	system.EnvChecker
+	system.WorkingDirectory
+	system.FilePathJoiner
+	system.AbsFilePath
+	system.FilePathToSlash
+	system.EnvGetter
+	system.UserHomeDir
+}
+
+// Hooks contains the platform specific parts of the translation.
+type Hooks struct {
+	// ConvertPath converts a host path to the path nerdctl should be given.
+	// Paths are left untouched if it is nil.
+	ConvertPath func(path string) (string, error)
+	// HostGatewayIP returns the IP address that the special "host-gateway" value of --add-host resolves to,
+	// unless it is set with host_gateway_ip in finch.yaml.
+	// "host-gateway" is passed through to nerdctl if neither is set.
+	HostGatewayIP func() (string, error)
+	// CheckHostPath is called with the absolute host path of every bind mount source, before it is converted.
+	// Returning an error fails the translation. Host paths are not checked if it is nil.
+	CheckHostPath func(path string) error
+}
+
+// Command is a translated nerdctl command line.
+type Command struct {
+	// Name is the nerdctl command, which may be made of several words (e.g. "container run").
+	Name string
+	// Args are the arguments following the command name.
+	Args []string
+	// Env contains KEY=VALUE pairs that need to be set in the environment of the nerdctl process.
+	Env []string
+	// InspectType is the type of object inspected by a docker-compatible inspect command.
+	InspectType string
+}
+
+// Translator translates finch command lines into nerdctl command lines.
+type Translator struct {
+	systemDeps SystemDeps
+	logger     flog.Logger
+	fs         afero.Fs
+	fc         *config.Finch
+	hooks      Hooks
+	// gatewayIP caches the resolved host gateway, as resolving it may run a command.
+	gatewayIP string
+}
+
+// NewTranslator creates a new Translator.
+func NewTranslator(systemDeps SystemDeps, logger flog.Logger, fs afero.Fs, fc *config.Finch, hooks Hooks) *Translator {
+	return &Translator{systemDeps: systemDeps, logger: logger, fs: fs, fc: fc, hooks: hooks}
+}
+
+// Translate translates the finch command cmdName invoked with args.
+//
+// It expands aliases, runs the command and argument handlers, aggregates -e/--env and --env-file into
+// individual -e flags, resolves host-gateway in --add-host, splits grouped short flags and consumes --debug.
+// When host_aliases is enabled, the host aliases are added to the containers created by run, create and compose.
+// When dockercompat is enabled, the defaults of the docker CLI config file, such as psFormat, are applied,
+// and a warning is logged for the flags known to be unsupported.
+func (tr *Translator) Translate(cmdName string, args []string) (*Command, error) {
+	var (
+		nerdctlArgs, envs, fileEnvs, cmdArgs, composeEnvs []string
+		userHosts                                         []string
+		skip, hasCmdHandler, hasArgHandler, lastOpt       bool
+		cmdHandler                                        commandHandler
+		aMap                                              map[string]argHandler
+		firstOptPos                                       int
+		inspectType                                       string
+		err                                               error
+	)
+
+	if tr.fc != nil && tr.fc.DockerCompat {
+		tr.warnUnsupported(cmdName, args)
+	}
+
+	// work on a copy as handlers modify the arguments in place
+	origArgs := args
+	args = append([]string{}, args...)
+
+	alias, hasAlias := aliasMap[cmdName]
+	if hasAlias {
+		cmdName = alias
+		cmdHandler, hasCmdHandler = commandHandlerMap[alias]
+		aMap, hasArgHandler = argHandlerMap[alias]
+	} else {
+		// Check if the command has a handler
+		cmdHandler, hasCmdHandler = commandHandlerMap[cmdName]
+		aMap, hasArgHandler = argHandlerMap[cmdName]
+
+		if !hasCmdHandler && !hasArgHandler && len(args) > 0 {
+			// for commands like image build, container run
+			key := fmt.Sprintf("%s %s", cmdName, args[0])
+			cmdHandler, hasCmdHandler = commandHandlerMap[key]
+			aMap, hasArgHandler = argHandlerMap[key]
+		}
+	}
+
+	// First check if the command has command handler
+	if hasCmdHandler {
+		err := cmdHandler(tr, &cmdName, &args, &inspectType)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	args = tr.dockerConfigDefaults(cmdName, args)
+
+	switch cmdName {
+	case "container run", "create", "exec", "compose":
+		// check if an option flag is present; immediately following the command
+		switch {
+		case len(args) > 1 && args[0] == "run" && strings.HasPrefix(args[1], "-"):
+			firstOptPos = 1
+		case len(args) > 0 && strings.HasPrefix(args[0], "-"):
+			firstOptPos = 0
+		case len(args) == 0:
+			args = append(args, "--help")
+			firstOptPos = 0
+		default:
+			firstOptPos = -1
+		}
+		// lastOpt is used to track when no more flags need to be processed
+		lastOpt = false
+
+		shortFlagBoolSet := cmdFlagSetMap[cmdName]["shortBoolFlags"]
+		longFlagBoolSet := cmdFlagSetMap[cmdName]["longBoolFlags"]
+
+		shortFlagArgSet := cmdFlagSetMap[cmdName]["shortArgFlags"]
+
+		for i, arg := range args {
+			// Check if individual argument (and possibly following value) requires manipulation-in-place handling
+			if hasArgHandler {
+				// Check if argument for the command needs handling, sometimes it can be --file=<filename>
+				b, _, _ := strings.Cut(arg, "=")
+				h, ok := aMap[b]
+				if ok {
+					err = h(tr, args, i)
+					if err != nil {
+						return nil, err
+					}
+					// This is required when the positional argument at i is mutated by argHandler, eg -v=C:\Users:/tmp:ro
+					arg = args[i]
+				}
+			}
+
+			// parsing arguments from the command line
+			// may pre-fetch and consume the next argument;
+			// the loop variable will skip any pre-consumed args
+			if skip {
+				skip = false
+				continue
+			}
+
+			// after last Option position is found, pass through each arg as an internal command argument (short-circuit)
+			if lastOpt {
+				cmdArgs = append(cmdArgs, arg)
+				continue
+			}
+			switch {
+			case arg == "--debug":
+				tr.logger.SetLevel(flog.Debug)
+			case arg == "--help":
+				nerdctlArgs = append(nerdctlArgs, arg)
+			case arg == "--add-host":
+				// exact match to --add-host. resolve ip if param passed
+				nerdctlArgs = append(nerdctlArgs, arg)
+				if len(args) > i+1 {
+					userHosts = append(userHosts, hostName(args[i+1]))
+					resolvedHost, err := tr.resolveIP(args[i+1])
+					if err != nil {
+						return nil, err
+					}
+					nerdctlArgs = append(nerdctlArgs, resolvedHost)
+					skip = true
+				}
+			case strings.HasPrefix(arg, "--add-host"):
+				// arg begins with --add-host
+				userHosts = append(userHosts, hostName(arg[11:]))
+				resolvedIP, err := tr.resolveIP(arg[11:])
+				if err != nil {
+					return nil, err
+				}
+				arg = fmt.Sprintf("%s%s", arg[0:11], resolvedIP)
+				nerdctlArgs = append(nerdctlArgs, arg)
+			case cmdName == "compose" && strings.HasPrefix(arg, "--env-file"):
+				// compose reads its env file for interpolation, so the variables are resolved on the host
+				// and exported to the nerdctl process instead of being passed to containers with -e.
+				// /dev/null keeps compose from falling back to the .env file in the project directory.
+				filename, hasValue := strings.CutPrefix(arg, "--env-file=")
+				if !hasValue {
+					if len(args) <= i+1 {
+						nerdctlArgs = append(nerdctlArgs, arg)
+						continue
+					}
+					filename = args[i+1]
+					skip = true
+				}
+				addEnvs, err := tr.parseComposeEnvFile(filename)
+				if err != nil {
+					return nil, err
+				}
+				composeEnvs = append(composeEnvs, addEnvs...)
+				nerdctlArgs = append(nerdctlArgs, "--env-file", "/dev/null")
+			case strings.HasPrefix(arg, "--env-file"):
+				// exact match to --env-file
+				// or arg begins with --env-file
+				if len(args) > i+1 {
+					shouldSkip, addEnvs, err := tr.handleEnvFile(arg, args[i+1])
+					if err != nil {
+						return nil, err
+					}
+					skip = shouldSkip
+					fileEnvs = append(fileEnvs, addEnvs...)
+				} else {
+					// if --env-file is at the end of the args, its refers to entrypoint command
+					// which need not be handled
+					nerdctlArgs = append(nerdctlArgs, arg)
+				}
+			case argIsEnv(arg):
+				// exact match to either -e or --env
+				// or arg begins with -e or --env
+				//     -e="<value>", -e"<value>"
+				//     --env="<key>=<value>", --env"<key>=<value>"
+				if len(args) > i+1 {
+					shouldSkip, addEnv := tr.handleEnv(arg, args[i+1])
+					skip = shouldSkip
+					if addEnv != "" {
+						envs = append(envs, addEnv)
+					}
+				} else {
+					// if -e or --env is at the end of the args, its refers to entrypoint command
+					// which need not be handled
+					nerdctlArgs = append(nerdctlArgs, arg)
+				}
+			case shortFlagBoolSet.Has(arg) || longFlagBoolSet.Has(arg):
+				// exact match to a short no argument flag: -?
+				// or exact match to: --<long_flag>
+				nerdctlArgs = append(nerdctlArgs, arg)
+			case longFlagBoolSet.Has(strings.Split(arg, "=")[0]):
+				// begins with --<long_flag>
+				//    e.g. --sig-proxy=false
+				nerdctlArgs = append(nerdctlArgs, arg)
+			case len(arg) > 1 && shortFlagBoolSet.Has(arg[:2]):
+				// or begins with a defined short no argument flag, but is adjacent to something
+				//   -????   one or more short bool flags; no following values
+				//   -????="<value>" one or more short bool flags ending with a short arg flag equated to value
+				//   -????"<value>" one or more short bool flags ending with a short arg flag concatenated to value
+				addArg := tr.handleMultipleShortFlags(shortFlagBoolSet, shortFlagArgSet, args, i)
+				nerdctlArgs = append(nerdctlArgs, addArg)
+			case shortFlagArgSet.Has(arg) || (len(arg) > 1 && shortFlagArgSet.Has(arg[:2])):
+				// exact match to a short arg flag: -?
+				//     next arg must be the <value>
+				// or begins with a short arg flag:
+				//     short arg flag concatenated to value: -?"<value>"
+				//     short arg flag equated to value: -?="<value>" or -?=<value>
+				if len(args) > i+1 {
+					shouldSkip, addKey, addVal := handleFlagArg(arg, args[i+1])
+					skip = shouldSkip
+					if addKey != "" {
+						nerdctlArgs = append(nerdctlArgs, addKey)
+						nerdctlArgs = append(nerdctlArgs, addVal)
+					}
+				} else {
+					// no value found for short arg flag
+					// pass the arg as a nerdctl command argument
+					nerdctlArgs = append(nerdctlArgs, arg)
+				}
+			case strings.HasPrefix(arg, "--"):
+				// exact match to a long arg flag: -<long_flag>
+				//     next arg must be the <value>
+				// or begins with a long arg flag:
+				//     long arg flag concatenated to value: --<long_flag>"<value>"
+				//     long arg flag equated to value: --<long_flag>="<value>" or --<long_flag>=<value>
+				if len(args) > i+1 {
+					shouldSkip, addKey, addVal := handleFlagArg(arg, args[i+1])
+					skip = shouldSkip
+					if addKey != "" {
+						nerdctlArgs = append(nerdctlArgs, addKey)
+						nerdctlArgs = append(nerdctlArgs, addVal)
+					}
+				} else {
+					// if --<long flag> is at the end of the args, its refers to entrypoint command
+					// which need not be handled
+					nerdctlArgs = append(nerdctlArgs, arg)
+				}
+			default:
+				// arg other than a flag ("-?","--<long_flag>") or a skipped <flag_value>
+				switch {
+				case (i < firstOptPos):
+					// arg is a value prior to the first found flag
+					// pass the arg as a nerdctl command argument
+					nerdctlArgs = append(nerdctlArgs, arg)
+				case (i >= firstOptPos) && !lastOpt:
+					// The first arg after procecssing the flags establishes the last Option Pos
+					lastOpt = true
+					cmdArgs = append(cmdArgs, arg)
+				default:
+					// Unexpected case
+					// pass the arg as a nerdctl arg by default
+					tr.logger.Debugln("Unexpected Arg Value: ", arg)
+					nerdctlArgs = append(nerdctlArgs, arg)
+				}
+			}
+		}
+
+		// to handle environment variables properly, we add all entries found via
+		// env-file includes to the map first and then all command line environment
+		// flags, making sure that command line overrides environment file options,
+		// and that later command line flags override earlier ones
+		envVars := orderedmap.New()
+
+		for _, e := range fileEnvs {
+			evar, eval, _ := strings.Cut(e, "=")
+			envVars.Set(evar, eval)
+		}
+		for _, e := range envs {
+			evar, eval, _ := strings.Cut(e, "=")
+			envVars.Set(evar, eval)
+		}
+
+		var envArgs []string
+		for pair := envVars.Oldest(); pair != nil; pair = pair.Next() {
+			envArgs = append(envArgs, "-e", fmt.Sprintf("%s=%s", pair.Key, pair.Value))
+		}
+
+		if tr.fc != nil && tr.fc.HostAliases && !slices.Contains(nerdctlArgs, "--help") {
+			var aliasArgs []string
+			switch {
+			case cmdName == "container run" || cmdName == "create":
+				aliasArgs, err = tr.hostAliasArgs(userHosts)
+			case len(cmdArgs) > 0 && slices.Contains(composeCreateCommands, cmdArgs[0]):
+				aliasArgs, err = tr.composeHostAliasArgs(origArgs)
+			}
+			if err != nil {
+				return nil, err
+			}
+			nerdctlArgs = append(nerdctlArgs, aliasArgs...)
+		}
+
+		if cmdName == "compose" && tr.hooks.CheckHostPath != nil && len(cmdArgs) > 0 &&
+			slices.Contains(composeCreateCommands, cmdArgs[0]) {
+			if err := tr.checkComposeBindMounts(origArgs); err != nil {
+				return nil, err
+			}
+		}
+
+		nerdctlArgs = append(nerdctlArgs, envArgs...)
+		nerdctlArgs = append(nerdctlArgs, cmdArgs...)
+	default:
+
+		for i, arg := range args {
+			// Check if individual argument (and possibly following value) requires manipulation-in-place handling
+			if hasArgHandler {
+				// Check if argument for the command needs handling, sometimes it can be --file=<filename>
+				b, _, _ := strings.Cut(arg, "=")
+				h, ok := aMap[b]
+				if ok {
+					err = h(tr, args, i)
+					if err != nil {
+						return nil, err
+					}
+					// This is required when the positional argument at i is mutated by argHandler, eg -v=C:\Users:/tmp:ro
+					arg = args[i]
+				}
+			}
+
+			switch arg {
+			case "--debug":
+				tr.logger.SetLevel(flog.Debug)
+			default:
+				nerdctlArgs = append(nerdctlArgs, arg)
+			}
+		}
+	}
+
+	return &Command{
+		Name:        cmdName,
+		Args:        nerdctlArgs,
+		Env:         composeEnvs,
+		InspectType: inspectType,
+	}, nil
+}
+
+// convertPath converts a host path using the ConvertPath hook, if there is one.
+func (tr *Translator) convertPath(path string) (string, error) {
+	if tr.hooks.ConvertPath == nil {
+		return path, nil
+	}
+	return tr.hooks.ConvertPath(path)
+}
+
+// checkHostPath checks a bind mount source with the CheckHostPath hook, if there is one.
+func (tr *Translator) checkHostPath(path string) error {
+	if tr.hooks.CheckHostPath == nil {
+		return nil
+	}
+	return tr.hooks.CheckHostPath(path)
+}
+
+// hostName returns the host name of an --add-host value in the form of host:ip.
+func hostName(host string) string {
+	name, _, _ := strings.Cut(host, ":")
+	return name
+}
+
+// warnUnsupported logs a warning for each command or flag of the command line that the compatibility catalog
+// lists as unsupported, as nerdctl would otherwise fail with an error that does not mention docker.
+func (tr *Translator) warnUnsupported(cmdName string, args []string) {
+	for _, f := range compat.DefaultCatalog().Check(append([]string{cmdName}, args...)) {
+		if f.Status == compat.Unsupported {
+			tr.logger.Warnf("%q is not supported by finch: %s", f.String(), f.Note)
+		}
+	}
+}
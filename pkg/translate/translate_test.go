@@ -0,0 +1,603 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package translate
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/runfinch/finch/pkg/config"
+	"github.com/runfinch/finch/pkg/flog"
+	"github.com/runfinch/finch/pkg/mocks"
+)
+
+func TestTranslator_Translate(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name      string
+		cmdName   string
+		args      []string
+		fc        *config.Finch
+		hooks     Hooks
+		mockSvc   func(t *testing.T, sd *mocks.TranslateSystemDeps, logger *mocks.Logger, fs afero.Fs)
+		want      *Command
+		wantFiles map[string]string
+		wantErr   error
+	}{
+		{
+			name:    "alias is expanded",
+			cmdName: "save",
+			args:    []string{"-o", "out.tar", "alpine"},
+			mockSvc: func(_ *testing.T, _ *mocks.TranslateSystemDeps, _ *mocks.Logger, _ afero.Fs) {},
+			want:    &Command{Name: "image save", Args: []string{"-o", "out.tar", "alpine"}},
+		},
+		{
+			name:    "--debug is consumed",
+			cmdName: "pull",
+			args:    []string{"alpine", "--debug"},
+			mockSvc: func(_ *testing.T, _ *mocks.TranslateSystemDeps, logger *mocks.Logger, _ afero.Fs) {
+				logger.EXPECT().SetLevel(flog.Debug)
+			},
+			want: &Command{Name: "pull", Args: []string{"alpine"}},
+		},
+		{
+			name:    "run without arguments asks for help",
+			cmdName: "run",
+			args:    []string{},
+			mockSvc: func(_ *testing.T, _ *mocks.TranslateSystemDeps, _ *mocks.Logger, _ afero.Fs) {},
+			want:    &Command{Name: "container run", Args: []string{"--help"}},
+		},
+		{
+			name:    "env flags and env file are aggregated, command line wins",
+			cmdName: "run",
+			args: []string{
+				"--env-file", "/env-file", "-e", "A=flag", "--env=FROM_HOST", "-eUNSET",
+				"-it", "alpine", "env", "-e", "X=1",
+			},
+			mockSvc: func(t *testing.T, sd *mocks.TranslateSystemDeps, _ *mocks.Logger, fs afero.Fs) {
+				require.NoError(t, afero.WriteFile(fs, "/env-file", []byte("A=file\nB=file\n"), 0o600))
+				sd.EXPECT().LookupEnv("FROM_HOST").Return("host", true)
+				sd.EXPECT().LookupEnv("UNSET").Return("", false)
+			},
+			want: &Command{
+				Name: "container run",
+				Args: []string{
+					"-it", "-e", "A=flag", "-e", "B=file", "-e", "FROM_HOST=host",
+					"alpine", "env", "-e", "X=1",
+				},
+			},
+		},
+		{
+			name:    "missing env file",
+			cmdName: "run",
+			args:    []string{"--env-file", "/missing", "alpine"},
+			mockSvc: func(_ *testing.T, _ *mocks.TranslateSystemDeps, _ *mocks.Logger, _ afero.Fs) {},
+			wantErr: errors.New("open /missing: file does not exist"),
+		},
+		{
+			name:    "compose env file is exported to nerdctl",
+			cmdName: "compose",
+			args:    []string{"--env-file=/env-file", "up"},
+			mockSvc: func(t *testing.T, sd *mocks.TranslateSystemDeps, _ *mocks.Logger, fs afero.Fs) {
+				require.NoError(t, afero.WriteFile(fs, "/env-file", []byte("A=file\nB=${A}\n"), 0o600))
+				sd.EXPECT().LookupEnv("A").Return("host", true).Times(2)
+				sd.EXPECT().LookupEnv("B").Return("", false)
+			},
+			want: &Command{
+				Name: "compose",
+				Args: []string{"--env-file", "/dev/null", "up"},
+				Env:  []string{"A=host", "B=host"},
+			},
+		},
+		{
+			name:    "host-gateway is resolved with the hook",
+			cmdName: "run",
+			args:    []string{"--add-host", "name:host-gateway", "--add-host=other:10.0.0.1", "alpine"},
+			hooks: Hooks{
+				HostGatewayIP: func() (string, error) { return "192.168.5.2", nil },
+			},
+			mockSvc: func(_ *testing.T, _ *mocks.TranslateSystemDeps, logger *mocks.Logger, _ afero.Fs) {
+				logger.EXPECT().Debugf(`Resolving special IP "host-gateway" to %q for host %q`, "192.168.5.2", "name")
+			},
+			want: &Command{
+				Name: "container run",
+				Args: []string{"--add-host", "name:192.168.5.2", "--add-host=other:10.0.0.1", "alpine"},
+			},
+		},
+		{
+			name:    "host-gateway is passed through without a hook",
+			cmdName: "run",
+			args:    []string{"--add-host", "name:host-gateway", "alpine"},
+			mockSvc: func(_ *testing.T, _ *mocks.TranslateSystemDeps, _ *mocks.Logger, _ afero.Fs) {},
+			want: &Command{
+				Name: "container run",
+				Args: []string{"--add-host", "name:host-gateway", "alpine"},
+			},
+		},
+		{
+			name:    "host-gateway hook error",
+			cmdName: "run",
+			args:    []string{"--add-host=name:host-gateway", "alpine"},
+			hooks: Hooks{
+				HostGatewayIP: func() (string, error) { return "", errors.New("no gateway") },
+			},
+			mockSvc: func(_ *testing.T, _ *mocks.TranslateSystemDeps, _ *mocks.Logger, _ afero.Fs) {},
+			wantErr: errors.New("no gateway"),
+		},
+		{
+			name:    "host_gateway_ip takes precedence over the hook",
+			cmdName: "run",
+			args:    []string{"--add-host=name:host-gateway", "alpine"},
+			fc: &config.Finch{
+				SharedSettings: config.SharedSettings{
+					HostGatewayIP: "10.0.0.2",
+				},
+			},
+			hooks: Hooks{
+				HostGatewayIP: func() (string, error) { return "", errors.New("not called") },
+			},
+			mockSvc: func(_ *testing.T, _ *mocks.TranslateSystemDeps, logger *mocks.Logger, _ afero.Fs) {
+				logger.EXPECT().Debugf(`Resolving special IP "host-gateway" to %q for host %q`, "10.0.0.2", "name")
+			},
+			want: &Command{
+				Name: "container run",
+				Args: []string{"--add-host=name:10.0.0.2", "alpine"},
+			},
+		},
+		{
+			name:    "host aliases are added to run, unless set by the user",
+			cmdName: "run",
+			args:    []string{"--add-host", "host.docker.internal:10.0.0.3", "-e", "A=1", "alpine"},
+			fc: &config.Finch{
+				SharedSettings: config.SharedSettings{
+					HostAliases: true,
+				},
+			},
+			hooks: Hooks{
+				HostGatewayIP: func() (string, error) { return "192.168.5.2", nil },
+			},
+			mockSvc: func(_ *testing.T, _ *mocks.TranslateSystemDeps, _ *mocks.Logger, _ afero.Fs) {},
+			want: &Command{
+				Name: "container run",
+				Args: []string{
+					"--add-host", "host.docker.internal:10.0.0.3", "--add-host", "host.finch.internal:192.168.5.2",
+					"-e", "A=1", "alpine",
+				},
+			},
+		},
+		{
+			name:    "host aliases are not added to exec",
+			cmdName: "exec",
+			args:    []string{"-it", "ctr", "sh"},
+			fc: &config.Finch{
+				SharedSettings: config.SharedSettings{
+					HostAliases: true,
+				},
+			},
+			mockSvc: func(_ *testing.T, _ *mocks.TranslateSystemDeps, _ *mocks.Logger, _ afero.Fs) {},
+			want:    &Command{Name: "exec", Args: []string{"-it", "ctr", "sh"}},
+		},
+		{
+			name:    "host aliases are added to compose services with an override file",
+			cmdName: "compose",
+			args:    []string{"up", "-d"},
+			fc: &config.Finch{
+				SharedSettings: config.SharedSettings{
+					HostAliases: true,
+				},
+			},
+			mockSvc: func(t *testing.T, sd *mocks.TranslateSystemDeps, _ *mocks.Logger, fs afero.Fs) {
+				require.NoError(t, afero.WriteFile(fs, "/project/compose.yaml", []byte("services:\n  web:\n  db:\n"), 0o600))
+				sd.EXPECT().GetWd().Return("/project", nil)
+				sd.EXPECT().Env("COMPOSE_FILE").Return("")
+				sd.EXPECT().FilePathJoin(gomock.Any()).DoAndReturn(filepath.Join).AnyTimes()
+				sd.EXPECT().GetUserHome().Return("/home/user", nil)
+			},
+			want: &Command{
+				Name: "compose",
+				Args: []string{
+					"--file", "/project/compose.yaml",
+					"--file", "/home/user/.finch/compose/host-aliases-" + composeOverrideHash + ".yaml",
+					"up", "-d",
+				},
+			},
+			wantFiles: map[string]string{
+				"/home/user/.finch/compose/host-aliases-" + composeOverrideHash + ".yaml": composeOverride,
+			},
+		},
+		{
+			name:    "paths are converted with the hook",
+			cmdName: "build",
+			args:    []string{"-f", `C:\Dockerfile`, "--iidfile=out", "."},
+			hooks: Hooks{
+				ConvertPath: func(path string) (string, error) { return "/mnt/" + path, nil },
+			},
+			mockSvc: func(_ *testing.T, _ *mocks.TranslateSystemDeps, _ *mocks.Logger, _ afero.Fs) {},
+			want: &Command{
+				Name: "image build",
+				Args: []string{"-f", `/mnt/C:\Dockerfile`, "--iidfile=/mnt/out", "/mnt/."},
+			},
+		},
+		{
+			name:    "relative and home volume sources are resolved, named volumes are kept",
+			cmdName: "run",
+			args: []string{
+				"-v", "./src:/app:ro", "--volume=~/code:/code", "-v", "..:/parent", "-v", "cache:/cache",
+				"-v", "/abs:/abs", "-v", "/anonymous", "alpine",
+			},
+			mockSvc: func(_ *testing.T, sd *mocks.TranslateSystemDeps, _ *mocks.Logger, _ afero.Fs) {
+				sd.EXPECT().FilePathAbs("./src").Return("/project/src", nil)
+				sd.EXPECT().FilePathAbs("..").Return("/", nil)
+				sd.EXPECT().GetUserHome().Return("/home/user", nil)
+				sd.EXPECT().FilePathJoin("/home/user", "/code").Return("/home/user/code")
+			},
+			want: &Command{
+				Name: "container run",
+				Args: []string{
+					"-v", "/project/src:/app:ro", "--volume", "/home/user/code:/code", "-v", "/:/parent", "-v", "cache:/cache",
+					"-v", "/abs:/abs", "-v", "/anonymous", "alpine",
+				},
+			},
+		},
+		{
+			name:    "volume sources of create are resolved",
+			cmdName: "create",
+			args:    []string{"-v", "./src:/app", "--volume=~/code:/code", "alpine"},
+			mockSvc: func(_ *testing.T, sd *mocks.TranslateSystemDeps, _ *mocks.Logger, _ afero.Fs) {
+				sd.EXPECT().FilePathAbs("./src").Return("/project/src", nil)
+				sd.EXPECT().GetUserHome().Return("/home/user", nil)
+				sd.EXPECT().FilePathJoin("/home/user", "/code").Return("/home/user/code")
+			},
+			want: &Command{
+				Name: "create",
+				Args: []string{"-v", "/project/src:/app", "--volume", "/home/user/code:/code", "alpine"},
+			},
+		},
+		{
+			name:    "volume and bind mount sources of container create are resolved",
+			cmdName: "container",
+			args:    []string{"create", "-v", "~/code:/code", "--mount", "type=bind,src=./src,dst=/app", "alpine"},
+			mockSvc: func(_ *testing.T, sd *mocks.TranslateSystemDeps, _ *mocks.Logger, _ afero.Fs) {
+				sd.EXPECT().GetUserHome().Return("/home/user", nil)
+				sd.EXPECT().FilePathJoin("/home/user", "/code").Return("/home/user/code")
+				sd.EXPECT().FilePathAbs("./src").Return("/project/src", nil)
+			},
+			want: &Command{
+				Name: "container",
+				Args: []string{"create", "-v", "/home/user/code:/code", "--mount", "type=bind,src=/project/src,dst=/app", "alpine"},
+			},
+		},
+		{
+			name:    "file flags of create are converted",
+			cmdName: "create",
+			args:    []string{"--cidfile", "./id", "--label-file=labels", "alpine"},
+			hooks: Hooks{
+				ConvertPath: func(path string) (string, error) { return "/mnt/" + path, nil },
+			},
+			mockSvc: func(_ *testing.T, _ *mocks.TranslateSystemDeps, _ *mocks.Logger, _ afero.Fs) {},
+			want: &Command{
+				Name: "create",
+				Args: []string{"--cidfile", "/mnt/./id", "--label-file", "/mnt/labels", "alpine"},
+			},
+		},
+		{
+			name:    "file flags of container create are converted",
+			cmdName: "container",
+			args:    []string{"create", "--cosign-key", "cosign.pub", "alpine"},
+			hooks: Hooks{
+				ConvertPath: func(path string) (string, error) { return "/mnt/" + path, nil },
+			},
+			mockSvc: func(_ *testing.T, _ *mocks.TranslateSystemDeps, _ *mocks.Logger, _ afero.Fs) {},
+			want:    &Command{Name: "container", Args: []string{"create", "--cosign-key", "/mnt/cosign.pub", "alpine"}},
+		},
+		{
+			name:    "bind mount source is resolved and converted, options are kept in order",
+			cmdName: "run",
+			args: []string{
+				"--mount", "type=bind,source=.,target=/app,readonly,consistency=cached,bind-propagation=rshared",
+				"--mount=type=volume,source=cache,target=/cache", "alpine",
+			},
+			hooks: Hooks{
+				ConvertPath: func(path string) (string, error) { return "/mnt" + path, nil },
+			},
+			mockSvc: func(_ *testing.T, sd *mocks.TranslateSystemDeps, _ *mocks.Logger, _ afero.Fs) {
+				sd.EXPECT().FilePathAbs(".").Return("/project", nil)
+			},
+			want: &Command{
+				Name: "container run",
+				Args: []string{
+					"--mount", "type=bind,source=/mnt/project,target=/app,readonly,bind-propagation=rshared",
+					"--mount", "type=volume,source=cache,target=/cache", "alpine",
+				},
+			},
+		},
+		{
+			name:    "volume source fails to resolve",
+			cmdName: "run",
+			args:    []string{"-v", "./src:/app", "alpine"},
+			mockSvc: func(_ *testing.T, sd *mocks.TranslateSystemDeps, _ *mocks.Logger, _ afero.Fs) {
+				sd.EXPECT().FilePathAbs("./src").Return("", errors.New("getwd failed"))
+			},
+			wantErr: errors.New("getwd failed"),
+		},
+		{
+			name:    "bind mount sources are checked with the hook before being converted",
+			cmdName: "run",
+			args:    []string{"-v", "~/code:/code", "alpine"},
+			hooks: Hooks{
+				ConvertPath: func(path string) (string, error) { return "/mnt" + path, nil },
+				CheckHostPath: func(path string) error {
+					if path != "/home/user/code" {
+						return errors.New("unexpected path " + path)
+					}
+					return errors.New("not shared")
+				},
+			},
+			mockSvc: func(_ *testing.T, sd *mocks.TranslateSystemDeps, _ *mocks.Logger, _ afero.Fs) {
+				sd.EXPECT().GetUserHome().Return("/home/user", nil)
+				sd.EXPECT().FilePathJoin("/home/user", "/code").Return("/home/user/code")
+			},
+			wantErr: errors.New("not shared"),
+		},
+		{
+			name:    "compose bind mount sources are checked with the hook",
+			cmdName: "compose",
+			args:    []string{"--file=/project/compose.yaml", "up"},
+			hooks: Hooks{
+				CheckHostPath: func(path string) error {
+					if path == "/data" {
+						return errors.New("not shared: " + path)
+					}
+					return nil
+				},
+			},
+			mockSvc: func(t *testing.T, sd *mocks.TranslateSystemDeps, _ *mocks.Logger, fs afero.Fs) {
+				require.NoError(t, afero.WriteFile(fs, "/project/compose.yaml", []byte(`services:
+  web:
+    volumes:
+      - ./src:/app:ro
+      - ${DATA}:/var/data
+      - cache:/cache
+  db:
+    volumes:
+      - type: bind
+        source: /data
+        target: /data
+`), 0o600))
+				sd.EXPECT().FilePathJoin("/project", "./src").Return("/project/src").AnyTimes()
+			},
+			wantErr: errors.New("not shared: /data"),
+		},
+		{
+			name:    "compose bind mount sources of a relative compose file are resolved against the working directory",
+			cmdName: "compose",
+			args:    []string{"-f", "sub/compose.yaml", "up"},
+			hooks: Hooks{
+				CheckHostPath: func(path string) error { return errors.New("checked: " + path) },
+			},
+			mockSvc: func(t *testing.T, sd *mocks.TranslateSystemDeps, _ *mocks.Logger, fs afero.Fs) {
+				require.NoError(t, afero.WriteFile(fs, "/work/sub/compose.yaml", []byte("services:\n  web:\n    volumes:\n      - ./src:/app\n"), 0o600))
+				sd.EXPECT().GetWd().Return("/work", nil)
+				sd.EXPECT().FilePathJoin("/work", "sub/compose.yaml").Return("/work/sub/compose.yaml")
+				sd.EXPECT().FilePathJoin("/work/sub", "./src").Return("/work/sub/src")
+			},
+			wantErr: errors.New("checked: /work/sub/src"),
+		},
+		{
+			name:    "compose bind mount sources are resolved against the project directory",
+			cmdName: "compose",
+			args:    []string{"--project-directory", "/project", "-f", "/work/compose.yaml", "up"},
+			hooks: Hooks{
+				CheckHostPath: func(path string) error { return errors.New("checked: " + path) },
+			},
+			mockSvc: func(t *testing.T, sd *mocks.TranslateSystemDeps, _ *mocks.Logger, fs afero.Fs) {
+				require.NoError(t, afero.WriteFile(fs, "/work/compose.yaml", []byte("services:\n  web:\n    volumes:\n      - ./src:/app\n"), 0o600))
+				sd.EXPECT().FilePathJoin("/project", "./src").Return("/project/src")
+			},
+			wantErr: errors.New("checked: /project/src"),
+		},
+		{
+			name:    "csv path flags keep the order of their fields",
+			cmdName: "build",
+			args:    []string{"--secret", "id=npm,src=npmrc", "-o=type=local,dest=out,platform-split=true", "."},
+			hooks: Hooks{
+				ConvertPath: func(path string) (string, error) { return "/mnt/" + path, nil },
+			},
+			mockSvc: func(_ *testing.T, _ *mocks.TranslateSystemDeps, _ *mocks.Logger, _ afero.Fs) {},
+			want: &Command{
+				Name: "image build",
+				Args: []string{
+					"--secret", "id=npm,src=/mnt/npmrc", "-o=type=local,dest=/mnt/out,platform-split=true", "/mnt/.",
+				},
+			},
+		},
+		{
+			name:    "compose files are converted, subcommand flags with the same name are not",
+			cmdName: "compose",
+			args:    []string{"-p", "proj", "-f", "compose.yaml", "logs", "-f", "web"},
+			hooks: Hooks{
+				ConvertPath: func(path string) (string, error) { return "/mnt/" + path, nil },
+			},
+			mockSvc: func(_ *testing.T, _ *mocks.TranslateSystemDeps, _ *mocks.Logger, _ afero.Fs) {},
+			want: &Command{
+				Name: "compose",
+				Args: []string{"-p", "proj", "-f", "/mnt/compose.yaml", "logs", "-f", "web"},
+			},
+		},
+		{
+			name:    "psFormat of the docker CLI config is the default format of ps",
+			cmdName: "ps",
+			args:    []string{"-a"},
+			fc:      &config.Finch{SharedSettings: config.SharedSettings{DockerCompat: true}},
+			mockSvc: func(t *testing.T, sd *mocks.TranslateSystemDeps, _ *mocks.Logger, fs afero.Fs) {
+				sd.EXPECT().Env("DOCKER_CONFIG").Return("")
+				sd.EXPECT().GetUserHome().Return("/home/user", nil)
+				sd.EXPECT().FilePathJoin(gomock.Any()).DoAndReturn(filepath.Join).Times(2)
+				require.NoError(t, afero.WriteFile(fs, "/home/user/.docker/config.json",
+					[]byte(`{"psFormat": "table {{.ID}}\t{{.Names}}"}`), 0o600))
+			},
+			want: &Command{Name: "ps", Args: []string{"--format", "table {{.ID}}\t{{.Names}}", "-a"}},
+		},
+		{
+			name:    "imagesFormat of the docker CLI config is inserted after the subcommand",
+			cmdName: "image",
+			args:    []string{"ls", "alpine"},
+			fc:      &config.Finch{SharedSettings: config.SharedSettings{DockerCompat: true}},
+			mockSvc: func(t *testing.T, sd *mocks.TranslateSystemDeps, _ *mocks.Logger, fs afero.Fs) {
+				sd.EXPECT().Env("DOCKER_CONFIG").Return("/docker")
+				sd.EXPECT().FilePathJoin("/docker", "config.json").Return("/docker/config.json")
+				require.NoError(t, afero.WriteFile(fs, "/docker/config.json", []byte(`{"imagesFormat": "{{.ID}}"}`), 0o600))
+			},
+			want: &Command{Name: "image", Args: []string{"ls", "--format", "{{.ID}}", "alpine"}},
+		},
+		{
+			name:    "docker CLI config format is not applied when the user sets the format or only prints IDs",
+			cmdName: "ps",
+			args:    []string{"-aq", "--format=json"},
+			fc:      &config.Finch{SharedSettings: config.SharedSettings{DockerCompat: true}},
+			mockSvc: func(_ *testing.T, _ *mocks.TranslateSystemDeps, _ *mocks.Logger, _ afero.Fs) {},
+			want:    &Command{Name: "ps", Args: []string{"-aq", "--format=json"}},
+		},
+		{
+			name:    "docker CLI config is not applied without dockercompat",
+			cmdName: "ps",
+			args:    []string{"-a"},
+			mockSvc: func(_ *testing.T, _ *mocks.TranslateSystemDeps, _ *mocks.Logger, _ afero.Fs) {},
+			want:    &Command{Name: "ps", Args: []string{"-a"}},
+		},
+		{
+			name:    "detachKeys of the docker CLI config is the default of run",
+			cmdName: "run",
+			args:    []string{"-it", "alpine"},
+			fc:      &config.Finch{SharedSettings: config.SharedSettings{DockerCompat: true}},
+			mockSvc: func(t *testing.T, sd *mocks.TranslateSystemDeps, _ *mocks.Logger, fs afero.Fs) {
+				sd.EXPECT().Env("DOCKER_CONFIG").Return("/docker")
+				sd.EXPECT().FilePathJoin("/docker", "config.json").Return("/docker/config.json")
+				require.NoError(t, afero.WriteFile(fs, "/docker/config.json", []byte(`{"detachKeys": "ctrl-x,x"}`), 0o600))
+			},
+			want: &Command{Name: "container run", Args: []string{"--detach-keys", "ctrl-x,x", "-it", "alpine"}},
+		},
+		{
+			name:    "detach keys set by the user win over the docker CLI config",
+			cmdName: "container",
+			args:    []string{"start", "--detach-keys=ctrl-y", "-a", "abc"},
+			fc:      &config.Finch{SharedSettings: config.SharedSettings{DockerCompat: true}},
+			mockSvc: func(_ *testing.T, _ *mocks.TranslateSystemDeps, _ *mocks.Logger, _ afero.Fs) {},
+			want:    &Command{Name: "container", Args: []string{"start", "--detach-keys=ctrl-y", "-a", "abc"}},
+		},
+		{
+			name:    "detachKeys of the docker CLI config is not passed to exec, which nerdctl does not support",
+			cmdName: "exec",
+			args:    []string{"-it", "abc", "sh"},
+			fc:      &config.Finch{SharedSettings: config.SharedSettings{DockerCompat: true}},
+			mockSvc: func(_ *testing.T, _ *mocks.TranslateSystemDeps, _ *mocks.Logger, _ afero.Fs) {},
+			want:    &Command{Name: "exec", Args: []string{"-it", "abc", "sh"}},
+		},
+		{
+			name:    "detachKeys of the docker CLI config is not passed to container exec",
+			cmdName: "container",
+			args:    []string{"exec", "-it", "abc", "sh"},
+			fc:      &config.Finch{SharedSettings: config.SharedSettings{DockerCompat: true}},
+			mockSvc: func(_ *testing.T, _ *mocks.TranslateSystemDeps, _ *mocks.Logger, _ afero.Fs) {},
+			want:    &Command{Name: "container", Args: []string{"exec", "-it", "abc", "sh"}},
+		},
+		{
+			name:    "invalid docker CLI config is ignored",
+			cmdName: "stats",
+			args:    []string{},
+			fc:      &config.Finch{SharedSettings: config.SharedSettings{DockerCompat: true}},
+			mockSvc: func(t *testing.T, sd *mocks.TranslateSystemDeps, logger *mocks.Logger, fs afero.Fs) {
+				sd.EXPECT().Env("DOCKER_CONFIG").Return("/docker")
+				sd.EXPECT().FilePathJoin("/docker", "config.json").Return("/docker/config.json")
+				require.NoError(t, afero.WriteFile(fs, "/docker/config.json", []byte(`{`), 0o600))
+				logger.EXPECT().Warnf("Ignoring the docker CLI config file: %v", gomock.Any())
+			},
+			want: &Command{Name: "stats"},
+		},
+		{
+			name:    "unsupported flags are reported with dockercompat",
+			cmdName: "run",
+			args:    []string{"--link", "db", "alpine"},
+			fc:      &config.Finch{SharedSettings: config.SharedSettings{DockerCompat: true}},
+			mockSvc: func(_ *testing.T, sd *mocks.TranslateSystemDeps, logger *mocks.Logger, _ afero.Fs) {
+				logger.EXPECT().Warnf("%q is not supported by finch: %s", "run --link db", gomock.Any())
+				sd.EXPECT().Env("DOCKER_CONFIG").Return("/docker")
+				sd.EXPECT().FilePathJoin("/docker", "config.json").Return("/docker/config.json")
+			},
+			want: &Command{Name: "container run", Args: []string{"--link", "db", "alpine"}},
+		},
+		{
+			name:    "docker compatible container inspect",
+			cmdName: "inspect",
+			args:    []string{"--type=container", "abc"},
+			fc: &config.Finch{
+				SharedSettings: config.SharedSettings{
+					DockerCompat: true,
+				},
+			},
+			mockSvc: func(_ *testing.T, _ *mocks.TranslateSystemDeps, _ *mocks.Logger, _ afero.Fs) {},
+			want: &Command{
+				Name:        "inspect",
+				Args:        []string{"--mode=dockercompat", "abc"},
+				InspectType: "container",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			sd := mocks.NewTranslateSystemDeps(ctrl)
+			logger := mocks.NewLogger(ctrl)
+			fs := afero.NewMemMapFs()
+			tc.mockSvc(t, sd, logger, fs)
+
+			fc := tc.fc
+			if fc == nil {
+				fc = &config.Finch{}
+			}
+			got, err := NewTranslator(sd, logger, fs, fc, tc.hooks).Translate(tc.cmdName, tc.args)
+			if tc.wantErr != nil {
+				assert.EqualError(t, err, tc.wantErr.Error())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+			if tc.wantFiles != nil {
+				for path, content := range tc.wantFiles {
+					b, err := afero.ReadFile(fs, path)
+					require.NoError(t, err)
+					assert.Equal(t, content, string(b))
+				}
+			}
+		})
+	}
+}
+
+const composeOverride = `services:
+    db:
+        extra_hosts:
+            - host.finch.internal:host-gateway
+            - host.docker.internal:host-gateway
+    web:
+        extra_hosts:
+            - host.finch.internal:host-gateway
+            - host.docker.internal:host-gateway
+`
+
+var composeOverrideHash = func() string {
+	sum := sha256.Sum256([]byte(composeOverride))
+	return hex.EncodeToString(sum[:6])
+}()
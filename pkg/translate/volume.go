@@ -0,0 +1,228 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package translate
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// volumeSpec is the value of a -v/--volume flag, in the form of [source:]target[:options].
+type volumeSpec struct {
+	source  string
+	target  string
+	options string
+}
+
+// parseVolumeSpec splits the value of a -v/--volume flag into its parts.
+// A Windows drive letter at the start of the source (e.g. C:\src:/app) is kept as part of the source.
+func parseVolumeSpec(spec string) volumeSpec {
+	parts := strings.Split(spec, ":")
+	if len(parts) > 2 && isDriveLetter(parts[0]) && (strings.HasPrefix(parts[1], `\`) || strings.HasPrefix(parts[1], "/")) {
+		parts = append([]string{parts[0] + ":" + parts[1]}, parts[2:]...)
+	}
+
+	switch len(parts) {
+	case 1:
+		// anonymous volume
+		return volumeSpec{target: parts[0]}
+	case 2:
+		return volumeSpec{source: parts[0], target: parts[1]}
+	default:
+		return volumeSpec{source: parts[0], target: parts[1], options: strings.Join(parts[2:], ":")}
+	}
+}
+
+func (v volumeSpec) String() string {
+	s := v.target
+	if v.source != "" {
+		s = v.source + ":" + s
+	}
+	if v.options != "" {
+		s = s + ":" + v.options
+	}
+	return s
+}
+
+// isHostPath reports whether the source of a volume refers to a host path rather than to a named volume.
+// Volume names cannot start with '.' or '~' nor contain path separators.
+func isHostPath(source string) bool {
+	return strings.HasPrefix(source, ".") || strings.HasPrefix(source, "~") ||
+		strings.ContainsAny(source, `/\`)
+}
+
+// resolveHostPath resolves a volume source to an absolute host path, checks it with the CheckHostPath hook
+// and converts it with the ConvertPath hook.
+// '~' is expanded to the home directory, and relative paths are resolved against the working directory.
+// Named volumes are returned as is, and so are absolute POSIX paths once checked.
+func (tr *Translator) resolveHostPath(source string) (string, error) {
+	if !isHostPath(source) {
+		return source, nil
+	}
+
+	posixAbs := strings.HasPrefix(source, "/") && !strings.Contains(source, `\`)
+	path, expanded, err := tr.expandHome(source)
+	if err != nil {
+		return "", err
+	}
+	if !expanded && !posixAbs && !isWindowsAbs(source) {
+		abs, err := tr.systemDeps.FilePathAbs(source)
+		if err != nil {
+			return "", err
+		}
+		path = abs
+	}
+
+	if err := tr.checkHostPath(path); err != nil {
+		return "", err
+	}
+	if posixAbs {
+		return path, nil
+	}
+
+	converted, err := tr.convertPath(path)
+	if err != nil {
+		return "", fmt.Errorf("could not get host path for %s: %w", source, err)
+	}
+	return converted, nil
+}
+
+// checkComposeBindMounts checks the bind mount sources of the services of the compose project with the
+// CheckHostPath hook. Relative sources are resolved against the project directory, which is set with
+// --project-directory or is the directory of the compose file defining them, as compose does.
+func (tr *Translator) checkComposeBindMounts(args []string) error {
+	files, _, err := tr.composeFiles(args)
+	if err != nil {
+		return err
+	}
+	projectDir := composeProjectDirectory(args)
+	if projectDir != "" {
+		if projectDir, err = tr.composeAbsPath(projectDir); err != nil {
+			return err
+		}
+	}
+
+	for _, f := range files {
+		// compose files passed with -f are relative to the working directory
+		f, err := tr.composeAbsPath(f)
+		if err != nil {
+			return err
+		}
+		services, err := tr.composeProject(f)
+		if err != nil {
+			return err
+		}
+		dir := projectDir
+		if dir == "" {
+			dir = filepath.Dir(f)
+		}
+		for _, service := range services {
+			for _, v := range service.Volumes {
+				source := composeBindSource(&v)
+				// sources using variables are interpolated by compose
+				if source == "" || strings.Contains(source, "$") {
+					continue
+				}
+				path, err := tr.composeHostPath(dir, source)
+				if err != nil {
+					return err
+				}
+				if err := tr.checkHostPath(path); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// composeProjectDirectory returns the value of the --project-directory compose global flag, or "" if it is not set.
+func composeProjectDirectory(args []string) string {
+	for i := 0; i < len(args) && strings.HasPrefix(args[i], "-"); i++ {
+		if dir, ok := strings.CutPrefix(args[i], "--project-directory="); ok {
+			return dir
+		}
+		if args[i] == "--project-directory" && i+1 < len(args) {
+			return args[i+1]
+		}
+		if slices.Contains(composeGlobalValueFlags, args[i]) {
+			i++
+		}
+	}
+	return ""
+}
+
+// composeAbsPath resolves path against the working directory if it is relative.
+func (tr *Translator) composeAbsPath(path string) (string, error) {
+	if strings.HasPrefix(path, "/") || isWindowsAbs(path) {
+		return path, nil
+	}
+	wd, err := tr.systemDeps.GetWd()
+	if err != nil {
+		return "", err
+	}
+	return tr.systemDeps.FilePathJoin(wd, path), nil
+}
+
+// composeBindSource returns the host path of a compose service volume, written in either the short
+// or the long syntax, or "" if it is not a bind mount.
+func composeBindSource(v *yaml.Node) string {
+	switch v.Kind {
+	case yaml.ScalarNode:
+		if spec := parseVolumeSpec(v.Value); isHostPath(spec.source) {
+			return spec.source
+		}
+	case yaml.MappingNode:
+		var long struct {
+			Type   string `yaml:"type"`
+			Source string `yaml:"source"`
+		}
+		if err := v.Decode(&long); err == nil && long.Type == "bind" {
+			return long.Source
+		}
+	}
+	return ""
+}
+
+// composeHostPath resolves the source of a compose bind mount against the project directory dir.
+func (tr *Translator) composeHostPath(dir, source string) (string, error) {
+	path, expanded, err := tr.expandHome(source)
+	if err != nil || expanded {
+		return path, err
+	}
+	if strings.HasPrefix(source, "/") || isWindowsAbs(source) {
+		return source, nil
+	}
+	return tr.systemDeps.FilePathJoin(dir, source), nil
+}
+
+// expandHome expands a leading "~" of path to the home directory of the user.
+// expanded is false, and path is returned as is, if path does not refer to the home directory.
+func (tr *Translator) expandHome(path string) (_ string, expanded bool, _ error) {
+	rest, ok := strings.CutPrefix(path, "~")
+	if !ok || (rest != "" && !strings.ContainsAny(rest[:1], `/\`)) {
+		return path, false, nil
+	}
+	home, err := tr.systemDeps.GetUserHome()
+	if err != nil {
+		return "", false, err
+	}
+	return tr.systemDeps.FilePathJoin(home, rest), true, nil
+}
+
+// isWindowsAbs reports whether path is an absolute Windows path, with a drive letter or a UNC path.
+func isWindowsAbs(path string) bool {
+	if strings.HasPrefix(path, `\\`) {
+		return true
+	}
+	return len(path) > 2 && isDriveLetter(path[:1]) && path[1] == ':' && (path[2] == '\\' || path[2] == '/')
+}
+
+func isDriveLetter(s string) bool {
+	return len(s) == 1 && (s[0] >= 'a' && s[0] <= 'z' || s[0] >= 'A' && s[0] <= 'Z')
+}
@@ -0,0 +1,72 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package translate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseVolumeSpec(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name string
+		spec string
+		want volumeSpec
+	}{
+		{
+			name: "anonymous volume",
+			spec: "/data",
+			want: volumeSpec{target: "/data"},
+		},
+		{
+			name: "named volume",
+			spec: "cache:/cache",
+			want: volumeSpec{source: "cache", target: "/cache"},
+		},
+		{
+			name: "relative bind mount with options",
+			spec: "./src:/app:ro,rshared",
+			want: volumeSpec{source: "./src", target: "/app", options: "ro,rshared"},
+		},
+		{
+			name: "windows drive",
+			spec: `C:\src:/app:rro`,
+			want: volumeSpec{source: `C:\src`, target: "/app", options: "rro"},
+		},
+		{
+			name: "windows drive with forward slashes",
+			spec: "c:/src:/app",
+			want: volumeSpec{source: "c:/src", target: "/app"},
+		},
+		{
+			name: "single letter named volume",
+			spec: "c:/app",
+			want: volumeSpec{source: "c", target: "/app"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := parseVolumeSpec(tc.spec)
+			assert.Equal(t, tc.want, got)
+			assert.Equal(t, tc.spec, got.String())
+		})
+	}
+}
+
+func TestIsHostPath(t *testing.T) {
+	t.Parallel()
+
+	for _, source := range []string{".", "..", "./src", "../src", "~", "~/code", "src/app", "/abs", `C:\src`} {
+		assert.True(t, isHostPath(source), source)
+	}
+	for _, source := range []string{"cache", "my_volume-1", ""} {
+		assert.False(t, isHostPath(source), source)
+	}
+}